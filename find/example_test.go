@@ -23,7 +23,12 @@ import (
 
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
 )
 
 func ExampleMultipleFoundError() {
@@ -57,3 +62,74 @@ func ExampleMultipleFoundError() {
 	// /DC0/network/DC0_DVPG0
 	// /DC0/network/DC0_DVPG1
 }
+
+func ExampleFinder_SetTagFilter() {
+	simulator.Run(func(ctx context.Context, c *vim25.Client) error {
+		rc := rest.NewClient(c)
+		if err := rc.Login(ctx, simulator.DefaultLogin); err != nil {
+			return err
+		}
+
+		m := tags.NewManager(rc)
+
+		id, err := m.CreateCategory(ctx, &tags.Category{Name: "env"})
+		if err != nil {
+			return err
+		}
+
+		id, err = m.CreateTag(ctx, &tags.Tag{CategoryID: id, Name: "prod"})
+		if err != nil {
+			return err
+		}
+
+		finder := find.NewFinder(c)
+
+		vm0, err := finder.VirtualMachine(ctx, "DC0_H0_VM1")
+		if err != nil {
+			return err
+		}
+		vm1, err := finder.VirtualMachine(ctx, "DC0_C0_RP0_VM0")
+		if err != nil {
+			return err
+		}
+		host, err := finder.HostSystem(ctx, "DC0_H0")
+		if err != nil {
+			return err
+		}
+
+		for _, ref := range []mo.Reference{vm0, vm1, host} {
+			if err = m.AttachTag(ctx, id, ref); err != nil {
+				return err
+			}
+		}
+
+		refs, err := m.SelectAttachedObjects(ctx, "env:prod")
+		if err != nil {
+			return err
+		}
+
+		finder.SetTagFilter(find.NewTagFilter(refs))
+
+		vms, err := finder.VirtualMachineList(ctx, "/DC0/vm/*")
+		if err != nil {
+			return err
+		}
+		for _, vm := range vms {
+			fmt.Println(vm.InventoryPath)
+		}
+
+		hosts, err := finder.HostSystemList(ctx, "*")
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			fmt.Println(host.InventoryPath)
+		}
+
+		return nil
+	})
+	// Output:
+	// /DC0/vm/DC0_H0_VM1
+	// /DC0/vm/DC0_C0_RP0_VM0
+	// /DC0/host/DC0_H0/DC0_H0
+}
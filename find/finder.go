@@ -38,6 +38,7 @@ type Finder struct {
 	dc      *object.Datacenter
 	si      *object.SearchIndex
 	folders *object.DatacenterFolders
+	tags    *TagFilter
 }
 
 func NewFinder(client *vim25.Client, all ...bool) *Finder {
@@ -63,6 +64,13 @@ func NewFinder(client *vim25.Client, all ...bool) *Finder {
 	return f
 }
 
+// SetTagFilter restricts find results to objects matching the given TagFilter.
+// A nil filter removes any restriction.
+func (f *Finder) SetTagFilter(t *TagFilter) *Finder {
+	f.tags = t
+	return f
+}
+
 func (f *Finder) SetDatacenter(dc *object.Datacenter) *Finder {
 	f.dc = dc
 	f.folders = nil
@@ -114,6 +122,22 @@ func (f *Finder) findRoot(ctx context.Context, root *list.Element, parts []strin
 }
 
 func (f *Finder) find(ctx context.Context, arg string, s *spec) ([]list.Element, error) {
+	es, err := f.search(ctx, arg, s)
+	if err != nil || f.tags == nil || s.Expand {
+		return es, err
+	}
+
+	var tagged []list.Element
+	for _, e := range es {
+		if f.tags.Match(e.Object) {
+			tagged = append(tagged, e)
+		}
+	}
+
+	return tagged, nil
+}
+
+func (f *Finder) search(ctx context.Context, arg string, s *spec) ([]list.Element, error) {
 	isPath := strings.Contains(arg, "/")
 
 	root := list.Element{
@@ -678,6 +702,7 @@ func (f *Finder) HostSystemList(ctx context.Context, path string) ([]*object.Hos
 		Relative: f.hostFolder,
 		Parents:  []string{"ComputeResource", "ClusterComputeResource"},
 		Include:  []string{"HostSystem"},
+		Expand:   true,
 	}
 
 	es, err := f.find(ctx, path, s)
@@ -709,6 +734,16 @@ func (f *Finder) HostSystemList(ctx context.Context, path string) ([]*object.Hos
 		}
 	}
 
+	if f.tags != nil {
+		var tagged []*object.HostSystem
+		for _, hs := range hss {
+			if f.tags.Match(hs) {
+				tagged = append(tagged, hs)
+			}
+		}
+		hss = tagged
+	}
+
 	if len(hss) == 0 {
 		return nil, &NotFoundError{"host", path}
 	}
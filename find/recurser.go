@@ -55,6 +55,10 @@ type spec struct {
 
 	// ChildType avoids traversing into folders that can't contain the Include types, used only in "find" mode.
 	ChildType []string
+
+	// Expand defers TagFilter matching to the caller, for results that are expanded
+	// into other types, such as the hosts of a ComputeResource.
+	Expand bool
 }
 
 func (s *spec) traversable(o mo.Reference) bool {
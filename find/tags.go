@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package find
+
+import (
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// TagFilter restricts Finder results to a set of tagged objects,
+// such as those returned by tags.Manager.SelectAttachedObjects.
+type TagFilter struct {
+	refs map[types.ManagedObjectReference]bool
+}
+
+// NewTagFilter creates a TagFilter matching the given objects.
+func NewTagFilter(refs []types.ManagedObjectReference) *TagFilter {
+	t := &TagFilter{refs: make(map[types.ManagedObjectReference]bool, len(refs))}
+	for _, ref := range refs {
+		t.refs[ref] = true
+	}
+	return t
+}
+
+// Match returns true if the given object is in the filter's set of tagged objects.
+func (t *TagFilter) Match(ref mo.Reference) bool {
+	return t.refs[ref.Reference()]
+}
@@ -8,6 +8,8 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/rasky/go-xdr v0.0.0-20170217172119-4930550ba2e2
 	github.com/vmware/vmw-guestinfo v0.0.0-20170707015358-25eff159a728
+	golang.org/x/sys v0.5.0
+	golang.org/x/term v0.5.0
 )
 
 require (
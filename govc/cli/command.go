@@ -114,6 +114,29 @@ func clientLogout(ctx context.Context, cmd Command) error {
 }
 
 func Run(args []string) int {
+	if len(args) != 0 && args[0] == "-batch" {
+		// govc -batch FILE is shorthand for govc shell -batch FILE
+		args = append([]string{"shell"}, args...)
+	}
+
+	ctx := context.Background()
+
+	if id := os.Getenv("GOVC_OPERATION_ID"); id != "" {
+		ctx = context.WithValue(ctx, types.ID{}, id)
+	}
+
+	return run(ctx, args, true)
+}
+
+// Execute runs the command named by args[0] with the given args.
+// Flags stored in ctx are shared with the command, such as the flags.ClientFlag
+// used by 'govc shell' to reuse a single session across commands.
+// Unlike Run, the client session is not logged out once the command completes.
+func Execute(ctx context.Context, args []string) int {
+	return run(ctx, args, false)
+}
+
+func run(ctx context.Context, args []string, logout bool) int {
 	hw := os.Stderr
 	rc := 1
 	hwrc := func(arg string) {
@@ -138,6 +161,9 @@ func Run(args []string) int {
 
 	cmd, ok := commands[name]
 	if !ok {
+		if path, ok := lookupPlugin(name); ok {
+			return runPlugin(ctx, path, args[1:])
+		}
 		hwrc(name)
 		generalHelp(hw, name)
 		return rc
@@ -146,12 +172,6 @@ func Run(args []string) int {
 	fs := flag.NewFlagSet("", flag.ContinueOnError)
 	fs.SetOutput(ioutil.Discard)
 
-	ctx := context.Background()
-
-	if id := os.Getenv("GOVC_OPERATION_ID"); id != "" {
-		ctx = context.WithValue(ctx, types.ID{}, id)
-	}
-
 	cmd.Register(ctx, fs)
 
 	if err = fs.Parse(args[1:]); err != nil {
@@ -166,8 +186,10 @@ func Run(args []string) int {
 		goto error
 	}
 
-	if err = clientLogout(ctx, cmd); err != nil {
-		goto error
+	if logout {
+		if err = clientLogout(ctx, cmd); err != nil {
+			goto error
+		}
 	}
 
 	return 0
@@ -193,7 +215,9 @@ error:
 		}
 	}
 
-	_ = clientLogout(ctx, cmd)
+	if logout {
+		_ = clientLogout(ctx, cmd)
+	}
 
 	return rc
 }
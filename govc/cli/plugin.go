@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/vmware/govmomi/govc/flags"
+)
+
+// PluginPrefix is the file name prefix of external plugin commands.
+// For example, 'govc foo' runs the 'govc-foo' executable found in PATH,
+// if 'foo' is not the name of a registered command.
+const PluginPrefix = "govc-"
+
+// Plugins returns a map of plugin command names to executable paths found in PATH.
+// If the same plugin exists in multiple PATH directories, the first is used.
+func Plugins() map[string]string {
+	plugins := make(map[string]string)
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name := file.Name()
+			if !strings.HasPrefix(name, PluginPrefix) {
+				continue
+			}
+
+			path, err := exec.LookPath(filepath.Join(dir, name))
+			if err != nil {
+				continue // not executable
+			}
+
+			name = strings.TrimPrefix(name, PluginPrefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name)) // e.g. .exe
+			}
+			if _, ok := plugins[name]; !ok {
+				plugins[name] = path
+			}
+		}
+	}
+
+	return plugins
+}
+
+func lookupPlugin(name string) (string, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+
+	return path, true
+}
+
+// runPlugin runs the plugin executable with the given args and the environment
+// returned by pluginEnviron, propagating the plugin's exit code.
+func runPlugin(ctx context.Context, path string, args []string) int {
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = pluginEnviron(ctx)
+
+	if err := cmd.Run(); err != nil {
+		if x, ok := err.(*exec.ExitError); ok {
+			return x.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+// pluginEnviron returns the current environment, with the addition of:
+// The connection variables resolved by flags.ClientFlag, as output by 'govc env',
+// when a URL is specified.
+// GOVMOMI_HOME, the directory containing the session cache (and debug logs),
+// such that plugins using the session/cache package reuse govc's sessions.
+// GOVC_PLUGIN_EXE, the path to the govc executable that dispatched the plugin.
+func pluginEnviron(ctx context.Context) []string {
+	env := os.Environ()
+
+	client, ctx := flags.NewClientFlag(ctx)
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	client.Register(ctx, fs)
+
+	if err := client.Process(ctx); err == nil {
+		env = append(env, client.Environ(false)...)
+	}
+
+	env = append(env, "GOVMOMI_HOME="+flags.Home())
+
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, "GOVC_PLUGIN_EXE="+exe)
+	}
+
+	return env
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vim25"
+)
+
+type completion struct {
+	complete bool
+}
+
+func init() {
+	cli.Register("completion", &completion{})
+}
+
+func (cmd *completion) Register(ctx context.Context, f *flag.FlagSet) {
+	f.BoolVar(&cmd.complete, "complete", false, "Print candidates for the last ARG of a govc command line")
+}
+
+func (cmd *completion) Process(ctx context.Context) error {
+	return nil
+}
+
+func (cmd *completion) Usage() string {
+	return "SHELL | -complete -- ARG..."
+}
+
+func (cmd *completion) Description() string {
+	return `Generate shell completion script for SHELL, one of: bash, zsh or fish.
+
+Completion is provided for command names and flags.
+Inventory paths are completed for the values of flags that take an inventory path (-vm, -ds, -host, etc),
+and for arguments that start with '/'.
+The GOVC_* environment variables are used to connect when completing inventory paths.
+
+Examples:
+  source <(govc completion bash)
+  govc completion zsh > "${fpath[1]}/_govc"
+  govc completion fish > ~/.config/fish/completions/govc.fish
+  govc completion -complete -- vm.info -vm /DC0/vm/`
+}
+
+func (cmd *completion) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.complete {
+		return cmd.candidates(ctx, f.Args())
+	}
+
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	script, ok := scripts[f.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unsupported shell: %q", f.Arg(0))
+	}
+
+	_, err := fmt.Fprint(os.Stdout, script)
+	return err
+}
+
+func (cmd *completion) candidates(ctx context.Context, args []string) error {
+	// Connection flags are not registered by this command, as the args being completed may use the same names.
+	client, cctx := flags.NewClientFlag(ctx)
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	client.Register(cctx, fs)
+
+	c := Completer{
+		Client: func() (*vim25.Client, error) {
+			if err := client.Process(cctx); err != nil {
+				return nil, err
+			}
+			return client.Client()
+		},
+		Datacenter: os.Getenv("GOVC_DATACENTER"),
+	}
+
+	for _, match := range c.Complete(ctx, args) {
+		fmt.Println(match)
+	}
+
+	return client.Logout(ctx)
+}
+
+var scripts = map[string]string{
+	"bash": `# bash completion for govc
+_govc_complete() {
+  local cur="${COMP_WORDS[COMP_CWORD]}"
+  local line="${COMP_LINE:0:COMP_POINT}"
+  local words
+  read -r -a words <<< "$line"
+  if [[ "$line" == *[[:space:]] ]]; then
+    words+=("")
+  fi
+
+  local candidates
+  mapfile -t candidates < <("${words[0]}" completion -complete -- "${words[@]:1}" 2>/dev/null)
+
+  # COMP_WORDBREAKS splits words at '=' and ':', candidates must only replace the current word
+  local prefix="${words[-1]%"$cur"}"
+  COMPREPLY=("${candidates[@]#"$prefix"}")
+
+  if [[ "${COMPREPLY[*]}" == *[/=] || "${COMPREPLY[*]}" == *[/=]\ * ]]; then
+    compopt -o nospace
+  fi
+}
+
+complete -o default -F _govc_complete govc
+`,
+
+	"zsh": `#compdef govc
+# zsh completion for govc
+_govc() {
+  local -a candidates partial
+  candidates=("${(@f)$(${words[1]} completion -complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+  partial=(${(M)candidates:#*[/=]})
+  candidates=(${candidates:#*[/=]})
+
+  (( ${#partial} )) && compadd -Q -S '' -- "${partial[@]}"
+  (( ${#candidates} )) && compadd -Q -- "${candidates[@]}"
+  (( ${#partial} + ${#candidates} )) || _files
+}
+
+compdef _govc govc
+`,
+
+	"fish": `# fish completion for govc
+function __govc_complete
+    set -l args (commandline -opc) (commandline -ct)
+    $args[1] completion -complete -- $args[2..-1] 2>/dev/null
+end
+
+complete -c govc -f -a '(__govc_complete)'
+`,
+}
@@ -0,0 +1,361 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/vim25"
+)
+
+// PathFlags maps the names of flags that take an inventory path to the managed object types they accept.
+var PathFlags = map[string][]string{
+	"cluster":           {"ClusterComputeResource"},
+	"datastore-cluster": {"StoragePod"},
+	"dc":                {"Datacenter"},
+	"ds":                {"Datastore"},
+	"folder":            {"Folder"},
+	"host":              {"HostSystem"},
+	"net":               {"Network", "DistributedVirtualPortgroup", "OpaqueNetwork"},
+	"pool":              {"ResourcePool"},
+	"vapp":              {"VirtualApp"},
+	"vm":                {"VirtualMachine"},
+}
+
+// containers maps managed object types to the inventory container types that may include them.
+var containers = map[string][]string{
+	"Datastore":      {"StoragePod"},
+	"HostSystem":     {"ClusterComputeResource", "ComputeResource"},
+	"ResourcePool":   {"ClusterComputeResource", "ComputeResource", "ResourcePool", "VirtualApp"},
+	"VirtualApp":     {"ClusterComputeResource", "ComputeResource", "ResourcePool", "VirtualApp"},
+	"VirtualMachine": {"VirtualApp"},
+}
+
+// isContainer returns true if kind is an inventory container that may include objects of the given kinds.
+// Folders and Datacenters may include any kind, all containers match if kinds is empty.
+func isContainer(kind string, kinds []string) bool {
+	switch kind {
+	case "Folder", "Datacenter":
+		return true
+	}
+
+	for _, k := range kinds {
+		if contains(containers[k], kind) {
+			return true
+		}
+	}
+
+	if len(kinds) == 0 {
+		for _, c := range containers {
+			if contains(c, kind) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Completer provides completion candidates for govc command lines.
+type Completer struct {
+	// Client is called to connect when inventory paths need to be completed.
+	Client func() (*vim25.Client, error)
+
+	// Datacenter is used to complete relative inventory paths, the default datacenter is used if empty.
+	Datacenter string
+}
+
+// Complete returns the candidates for the last word in args,
+// where args[0] is the command name (without the leading 'govc').
+// Flags stored in ctx are shared with the command when registering its flags.
+// Candidates for a word in the form of -flag=value include the -flag= prefix.
+func (c *Completer) Complete(ctx context.Context, args []string) []string {
+	if len(args) == 0 {
+		return Commands("")
+	}
+
+	word := args[len(args)-1]
+
+	if len(args) == 1 {
+		return Commands(word)
+	}
+
+	cmd, ok := cli.Commands()[args[0]]
+	if !ok {
+		return nil
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	cmd.Register(ctx, fs)
+
+	if strings.HasPrefix(word, "-") {
+		name := strings.TrimLeft(word, "-")
+		if i := strings.IndexRune(name, '='); i > 0 {
+			prefix := word[:len(word)-len(name)+i+1]
+			return withPrefix(prefix, c.Values(ctx, name[:i], name[i+1:]))
+		}
+		return Flags(fs, word)
+	}
+
+	prev := args[len(args)-2]
+	if strings.HasPrefix(prev, "-") && !strings.ContainsRune(prev, '=') {
+		if f := fs.Lookup(strings.TrimLeft(prev, "-")); f != nil && !isBoolFlag(f) {
+			return c.Values(ctx, f.Name, word)
+		}
+	}
+
+	if strings.HasPrefix(word, "/") {
+		return c.Paths(ctx, word)
+	}
+
+	return nil
+}
+
+// Commands returns the names of registered commands and plugins with the given prefix.
+func Commands(prefix string) []string {
+	var matches []string
+
+	commands := cli.Commands()
+
+	for name := range commands {
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+
+	for name := range cli.Plugins() {
+		if _, ok := commands[name]; !ok && strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	}
+
+	sort.Strings(matches)
+	return matches
+}
+
+// Flags returns the flags in fs with the given prefix, including the leading '-'.
+func Flags(fs *flag.FlagSet, prefix string) []string {
+	var matches []string
+
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "-" + f.Name
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+	})
+
+	sort.Strings(matches)
+	return matches
+}
+
+// Values returns candidates for the value of the given flag name.
+// Only flags listed in PathFlags have candidates, absolute paths are completed one level at a time,
+// otherwise object names relative to the datacenter are completed.
+func (c *Completer) Values(ctx context.Context, name string, word string) []string {
+	kinds, ok := PathFlags[name]
+	if !ok {
+		return nil
+	}
+
+	if strings.HasPrefix(word, "/") {
+		return c.Paths(ctx, word, kinds...)
+	}
+
+	finder, err := c.finder(ctx)
+	if err != nil {
+		return nil
+	}
+
+	paths, err := list(ctx, finder, name, word+"*")
+	if err != nil {
+		return nil
+	}
+
+	var matches []string
+
+	for _, p := range paths {
+		if !strings.ContainsRune(word, '/') {
+			p = path.Base(p)
+		}
+		if strings.HasPrefix(p, word) {
+			matches = append(matches, p)
+		}
+	}
+
+	sort.Strings(matches)
+	return matches
+}
+
+// Paths returns the absolute inventory paths with the given prefix.
+// If kinds are specified, only objects of those types and the containers that may include them are returned.
+// Container paths have a trailing '/'.
+func (c *Completer) Paths(ctx context.Context, word string, kinds ...string) []string {
+	finder, err := c.finder(ctx)
+	if err != nil {
+		return nil
+	}
+
+	dir := word
+	if !strings.HasSuffix(dir, "/") {
+		dir = path.Dir(dir)
+	}
+
+	elements, err := finder.ManagedObjectListChildren(ctx, dir)
+	if err != nil {
+		return nil
+	}
+
+	var matches []string
+
+	for _, e := range elements {
+		if !strings.HasPrefix(e.Path, word) {
+			continue
+		}
+
+		p := e.Path
+		kind := e.Object.Reference().Type
+		container := isContainer(kind, kinds)
+
+		if !container && len(kinds) != 0 && !contains(kinds, kind) {
+			continue
+		}
+
+		if container {
+			p += "/"
+		}
+
+		matches = append(matches, p)
+	}
+
+	sort.Strings(matches)
+	return matches
+}
+
+func (c *Completer) finder(ctx context.Context) (*find.Finder, error) {
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	finder := find.NewFinder(client, false)
+
+	dc, err := finder.DatacenterOrDefault(ctx, c.Datacenter)
+	if err == nil {
+		finder.SetDatacenter(dc)
+	}
+
+	return finder, nil
+}
+
+// list returns the inventory paths of objects matching pattern, for the given PathFlags name.
+func list(ctx context.Context, finder *find.Finder, name string, pattern string) ([]string, error) {
+	var paths []string
+
+	switch name {
+	case "cluster":
+		objs, err := finder.ClusterComputeResourceList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "datastore-cluster":
+		objs, err := finder.DatastoreClusterList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "dc":
+		objs, err := finder.DatacenterList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "ds":
+		objs, err := finder.DatastoreList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "folder":
+		objs, err := finder.FolderList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "host":
+		objs, err := finder.HostSystemList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "net":
+		objs, err := finder.NetworkList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.GetInventoryPath())
+		}
+		return paths, err
+	case "pool":
+		objs, err := finder.ResourcePoolList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "vapp":
+		objs, err := finder.VirtualAppList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	case "vm":
+		objs, err := finder.VirtualMachineList(ctx, pattern)
+		for _, o := range objs {
+			paths = append(paths, o.InventoryPath)
+		}
+		return paths, err
+	}
+
+	return nil, nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func withPrefix(prefix string, values []string) []string {
+	for i := range values {
+		values[i] = prefix + values[i]
+	}
+	return values
+}
+
+func contains(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/units"
+)
+
+type sync struct {
+	*flags.DatastoreFlag
+	*flags.OutputFlag
+
+	download bool
+	delete   bool
+	dryRun   bool
+	include  flags.StringList
+	exclude  flags.StringList
+	parallel int
+}
+
+func init() {
+	cli.Register("datastore.sync", &sync{})
+}
+
+func (cmd *sync) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatastoreFlag, ctx = flags.NewDatastoreFlag(ctx)
+	cmd.DatastoreFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.download, "download", false, "Sync from datastore SOURCE to local DEST")
+	f.BoolVar(&cmd.delete, "delete", false, "Delete files in DEST that do not exist in SOURCE")
+	f.BoolVar(&cmd.dryRun, "dry-run", false, "Print changes without making them")
+	f.Var(&cmd.include, "include", "Only sync files matching PATTERN (can be specified multiple times)")
+	f.Var(&cmd.exclude, "exclude", "Exclude files and directories matching PATTERN (can be specified multiple times)")
+	f.IntVar(&cmd.parallel, "p", 1, "Number of parallel file transfers")
+}
+
+func (cmd *sync) Process(ctx context.Context) error {
+	if err := cmd.DatastoreFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *sync) Usage() string {
+	return "SOURCE DEST"
+}
+
+func (cmd *sync) Description() string {
+	return `Sync local directory SOURCE to datastore directory DEST.
+
+With -download, sync datastore directory SOURCE to local directory DEST.
+A file is copied if it does not exist in DEST, if the size differs or if the SOURCE file is newer.
+Include and exclude patterns are matched against the path relative to SOURCE and the file base name.
+An excluded directory is excluded along with its contents.
+
+Examples:
+  govc datastore.sync ./isos isos
+  govc datastore.sync -delete -p 4 -exclude '*.tmp' ./isos isos
+  govc datastore.sync -dry-run -include '*.iso' ./isos isos
+  govc datastore.sync -download -json vm-name ./vm-backup`
+}
+
+func (cmd *sync) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	dc, err := cmd.Datacenter()
+	if err != nil {
+		return err
+	}
+
+	ds, err := cmd.Datastore()
+	if err != nil {
+		return err
+	}
+
+	s := ds.NewSync(dc)
+	s.Delete = cmd.delete
+	s.DryRun = cmd.dryRun
+	s.Include = cmd.include
+	s.Exclude = cmd.exclude
+	s.Parallel = cmd.parallel
+
+	wait := func() {}
+	if cmd.OutputFlag.TTY && !cmd.dryRun {
+		logger := cmd.ProgressLogger("Syncing... ")
+		s.Progress = logger
+		wait = logger.Wait
+	}
+
+	src, dst := f.Arg(0), f.Arg(1)
+	sync := s.Upload
+	if cmd.download {
+		sync = s.Download
+	}
+
+	changes, err := sync(ctx, src, dst)
+	wait()
+	if err != nil {
+		if len(changes) != 0 {
+			_ = cmd.WriteResult(&syncResult{Changes: changes})
+		}
+		return err
+	}
+
+	return cmd.WriteResult(&syncResult{Changes: changes})
+}
+
+type syncResult struct {
+	Changes []object.DatastoreSyncChange `json:"changes"`
+}
+
+func (r *syncResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, c := range r.Changes {
+		size := ""
+		if c.Op != object.DatastoreSyncMkdir {
+			size = units.ByteSize(c.Size).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Op, size, c.Path)
+	}
+
+	return tw.Flush()
+}
@@ -90,6 +90,11 @@ func init() {
 	}
 }
 
+// Home returns the directory used for the session cache and debug logs, $GOVMOMI_HOME or $HOME/.govmomi by default.
+func Home() string {
+	return home
+}
+
 func NewClientFlag(ctx context.Context) (*ClientFlag, context.Context) {
 	if v := ctx.Value(clientFlagKey); v != nil {
 		return v.(*ClientFlag), ctx
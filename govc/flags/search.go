@@ -25,6 +25,7 @@ import (
 
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/soap"
 	"github.com/vmware/govmomi/vim25/types"
@@ -51,9 +52,13 @@ type SearchFlag struct {
 	byIP            string
 	byUUID          string
 
+	tags *StringList
+
 	isset bool
 }
 
+var searchTagsKey = flagKey("searchTags")
+
 func NewSearchFlag(ctx context.Context, t int) (*SearchFlag, context.Context) {
 	searchFlagKey := flagKey(fmt.Sprintf("search%d", t))
 
@@ -68,6 +73,14 @@ func NewSearchFlag(ctx context.Context, t int) (*SearchFlag, context.Context) {
 	v.ClientFlag, ctx = NewClientFlag(ctx)
 	v.DatacenterFlag, ctx = NewDatacenterFlag(ctx)
 
+	// The -tag flag is shared by all search flags of a command
+	if tags := ctx.Value(searchTagsKey); tags != nil {
+		v.tags = tags.(*StringList)
+	} else {
+		v.tags = new(StringList)
+		ctx = context.WithValue(ctx, searchTagsKey, v.tags)
+	}
+
 	switch t {
 	case SearchVirtualMachines:
 		v.entity = "VM"
@@ -104,6 +117,11 @@ func (flag *SearchFlag) Register(ctx context.Context, fs *flag.FlagSet) {
 			register(&flag.byDNSName, "dns", "Find %s by FQDN")
 			register(&flag.byIP, "ip", "Find %s by IP address")
 			register(&flag.byUUID, "uuid", "Find %s by UUID")
+
+			if fs.Lookup("tag") == nil {
+				usage := fmt.Sprintf("Select %ss by attached tag (category:name, category:* or name)", flag.entity)
+				fs.Var(flag.tags, "tag", usage)
+			}
 		}
 
 		register(&flag.byInventoryPath, "ipath", "Find %s by inventory path")
@@ -145,6 +163,46 @@ func (flag *SearchFlag) IsSet() bool {
 	return flag.isset
 }
 
+// IsTagged returns true if the -tag flag was specified.
+func (flag *SearchFlag) IsTagged() bool {
+	return len(*flag.tags) != 0
+}
+
+// tagged returns a Finder restricted to objects matching the -tag flag,
+// along with the arguments to search, defaulting to all objects.
+func (flag *SearchFlag) tagged(args []string) (*find.Finder, []string, error) {
+	finder, err := flag.Finder()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !flag.IsTagged() {
+		return finder, args, nil
+	}
+
+	if flag.IsSet() {
+		return nil, nil, errors.New("cannot use -tag with other search flags")
+	}
+
+	c, err := flag.RestClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs, err := tags.NewManager(c).SelectAttachedObjects(context.TODO(), *flag.tags...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(args) == 0 || (len(args) == 1 && args[0] == "") {
+		args = []string{"*"}
+	}
+
+	// Copy to avoid filtering the shared Finder
+	tagged := *finder
+	return tagged.SetTagFilter(find.NewTagFilter(refs)), args, nil
+}
+
 func (flag *SearchFlag) searchIndex(c *vim25.Client) *object.SearchIndex {
 	return object.NewSearchIndex(c)
 }
@@ -295,6 +353,11 @@ func (flag *SearchFlag) VirtualMachines(args []string) ([]*object.VirtualMachine
 	ctx := context.TODO()
 	var out []*object.VirtualMachine
 
+	finder, args, err := flag.tagged(args)
+	if err != nil {
+		return nil, err
+	}
+
 	if flag.IsSet() {
 		vm, err := flag.VirtualMachine()
 		if err != nil {
@@ -310,11 +373,6 @@ func (flag *SearchFlag) VirtualMachines(args []string) ([]*object.VirtualMachine
 		return nil, errors.New("no argument")
 	}
 
-	finder, err := flag.Finder()
-	if err != nil {
-		return nil, err
-	}
-
 	var nfe error
 
 	// List virtual machines for every argument
@@ -404,6 +462,11 @@ func (flag *SearchFlag) HostSystems(args []string) ([]*object.HostSystem, error)
 	ctx := context.TODO()
 	var out []*object.HostSystem
 
+	finder, args, err := flag.tagged(args)
+	if err != nil {
+		return nil, err
+	}
+
 	if flag.IsSet() {
 		host, err := flag.HostSystem()
 		if err != nil {
@@ -419,11 +482,6 @@ func (flag *SearchFlag) HostSystems(args []string) ([]*object.HostSystem, error)
 		return nil, errors.New("no argument")
 	}
 
-	finder, err := flag.Finder()
-	if err != nil {
-		return nil, err
-	}
-
 	// List host systems for every argument
 	for _, arg := range args {
 		vms, err := finder.HostSystemList(ctx, arg)
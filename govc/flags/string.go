@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"flag"
+)
+
+type stringptrValue struct {
+	val **string
+}
+
+func (s *stringptrValue) Set(v string) error {
+	*s.val = new(string)
+	**s.val = v
+	return nil
+}
+
+func (s *stringptrValue) Get() interface{} {
+	if s.val == nil || *s.val == nil {
+		return nil
+	}
+	return **s.val
+}
+
+func (s *stringptrValue) String() string {
+	if s.val == nil || *s.val == nil {
+		return ""
+	}
+	return **s.val
+}
+
+// NewOptionalString behaves as flag.StringVar, but leaves v nil unless the flag is set,
+// such that an empty value can be distinguished from an unset value.
+func NewOptionalString(v **string) flag.Value {
+	return &stringptrValue{val: v}
+}
@@ -110,3 +110,13 @@ func (flag *VirtualMachineFlag) VirtualMachine() (*object.VirtualMachine, error)
 	flag.vm, err = finder.VirtualMachine(ctx, flag.name)
 	return flag.vm, err
 }
+
+// VirtualMachineList returns the virtual machines matching the -vm flag,
+// which may be an inventory path pattern that matches multiple virtual machines.
+func (flag *VirtualMachineFlag) VirtualMachineList() ([]*object.VirtualMachine, error) {
+	if !flag.SearchFlag.IsSet() && flag.name == "" && !flag.SearchFlag.IsTagged() {
+		return nil, nil
+	}
+
+	return flag.SearchFlag.VirtualMachines([]string{flag.name})
+}
@@ -87,7 +87,7 @@ func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
 	}
 
 	// Default only if there is a single host
-	if host == nil && f.NArg() == 0 {
+	if host == nil && f.NArg() == 0 && !cmd.IsTagged() {
 		host, err = cmd.HostSystem()
 		if err != nil {
 			return err
@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/session/cache"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/mirror"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+type mirrorCmd struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	target  string
+	ds      string
+	scratch string
+}
+
+func init() {
+	cli.Register("library.mirror", &mirrorCmd{})
+}
+
+func (cmd *mirrorCmd) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	env := "GOVC_TARGET_URL"
+	usage := fmt.Sprintf("Target vCenter URL [%s]", env)
+	f.StringVar(&cmd.target, "target", os.Getenv(env), usage)
+	f.StringVar(&cmd.ds, "ds", "", "Target datastore for a new library")
+	f.StringVar(&cmd.scratch, "scratch", "", "Source library for temporary OVF exports of VM templates")
+}
+
+func (cmd *mirrorCmd) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *mirrorCmd) Usage() string {
+	return "LIBRARY [TARGET]"
+}
+
+func (cmd *mirrorCmd) Description() string {
+	return `Mirror LIBRARY to the -target vCenter.
+
+All LIBRARY items and their files are copied from the source vCenter (-u) to the TARGET library on the -target vCenter.
+The TARGET library name defaults to LIBRARY and is created if it does not exist, in which case -ds must be specified.
+Items with the same name in TARGET are only updated if their file checksums differ from the source.
+VM template (vm-template) items are exported as OVF on the source and mirrored as OVF items.
+The export is skipped if the TARGET item was mirrored from the current version of the VM template.
+Temporary exports are created in the -scratch library, which defaults to LIBRARY and is required if LIBRARY is subscribed.
+Unlike a subscribed library, the target vCenter does not need network access to the source vCenter.
+
+Examples:
+  govc library.mirror -target user:pass@vc2.example.com -ds datastore1 library_name
+  govc library.mirror -target user:pass@vc2.example.com library_name mirror_library_name
+  govc library.mirror -target user:pass@vc2.example.com -scratch scratch_library subscribed_library_name`
+}
+
+type mirrorResult []mirror.Result
+
+func (r mirrorResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, item := range r {
+		fmt.Fprintf(tw, "%s/%s\t%s\t%d\t%s\n", item.Library, item.Item, item.Type, item.Files, item.Status)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *mirrorCmd) storage(ctx context.Context, session *cache.Session) ([]library.StorageBackings, error) {
+	if cmd.ds == "" {
+		return nil, nil
+	}
+
+	c := new(vim25.Client)
+	if err := session.Login(ctx, c, cmd.ConfigureTLS); err != nil {
+		return nil, err
+	}
+
+	ds, err := find.NewFinder(c).Datastore(ctx, cmd.ds)
+	if err != nil {
+		return nil, err
+	}
+
+	return []library.StorageBackings{{DatastoreID: ds.Reference().Value, Type: "DATASTORE"}}, nil
+}
+
+func (cmd *mirrorCmd) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 1 || f.NArg() > 2 || cmd.target == "" {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+	cmd.KeepAlive(c)
+
+	res, err := flags.ContentLibraryResult(ctx, c, "", f.Arg(0))
+	if err != nil {
+		return err
+	}
+	src, ok := res.GetResult().(library.Library)
+	if !ok {
+		return fmt.Errorf("%q is a %T", f.Arg(0), res.GetResult())
+	}
+
+	session := cmd.Session
+	session.URL, err = soap.ParseURL(cmd.target)
+	if err != nil {
+		return err
+	}
+
+	target := new(rest.Client)
+	if err = session.Login(ctx, target, cmd.ConfigureTLS); err != nil {
+		return err
+	}
+	cmd.KeepAlive(target)
+
+	m := &mirror.Mirror{Source: c, Target: target}
+
+	if cmd.scratch != "" {
+		scratch, err := library.NewManager(c).GetLibraryByName(ctx, cmd.scratch)
+		if err != nil {
+			return err
+		}
+		m.Scratch = scratch.ID
+	}
+
+	m.Storage, err = cmd.storage(ctx, &session)
+	if err != nil {
+		return err
+	}
+
+	items, err := m.Library(ctx, &src, f.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(mirrorResult(items))
+}
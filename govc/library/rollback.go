@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/vcenter"
+)
+
+type rollback struct {
+	*flags.ClientFlag
+	vcenter.Rollback
+}
+
+func init() {
+	cli.Register("library.rollback", &rollback{})
+}
+
+func (cmd *rollback) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.Message, "m", "", "Rollback message")
+}
+
+func (cmd *rollback) Usage() string {
+	return "PATH VERSION"
+}
+
+func (cmd *rollback) Description() string {
+	return `Rollback VM template Content Library item PATH to a previous VERSION.
+
+The VM template of the given VERSION is restored as a new version of the item.
+See library.versions for the list of previous versions.
+
+Note: this command requires vCenter 7.0 or higher.
+
+Examples:
+  govc library.versions my-content/template-vm-item
+  govc library.rollback -m "undo changes" my-content/template-vm-item 2`
+}
+
+func (cmd *rollback) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	item, err := flags.ContentLibraryItem(ctx, c, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	version, err := vcenter.NewManager(c).RollbackTemplate(ctx, item.ID, f.Arg(1), &cmd.Rollback)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s (%s) rolled back to version %s as content version %s\n", item.Name, item.ID, f.Arg(1), version)
+
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package library
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/vcenter"
+)
+
+type versions struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("library.versions", &versions{})
+}
+
+func (cmd *versions) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *versions) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *versions) Usage() string {
+	return "PATH"
+}
+
+func (cmd *versions) Description() string {
+	return `List versions of VM template Content Library item PATH.
+
+The current version is listed first, followed by the previous versions that can be restored with library.rollback.
+
+Note: this command requires vCenter 7.0 or higher.
+
+Examples:
+  govc library.versions my-content/template-vm-item
+  govc library.versions -json my-content/template-vm-item`
+}
+
+type versionsResult struct {
+	Current  vcenter.TemplateVersion   `json:"current"`
+	Versions []vcenter.TemplateVersion `json:"versions"`
+}
+
+func (r *versionsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Version\tVM Template\t")
+	fmt.Fprintf(tw, "%s (current)\t%s\t\n", r.Current.Version, r.Current.VMTemplate)
+	for i := len(r.Versions) - 1; i >= 0; i-- {
+		v := r.Versions[i]
+		fmt.Fprintf(tw, "%s\t%s\t\n", v.Version, v.VMTemplate)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *versions) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	item, err := flags.ContentLibraryItem(ctx, c, f.Arg(0))
+	if err != nil {
+		return err
+	}
+	if item.Type != library.ItemTypeVMTX {
+		return fmt.Errorf("library item type should be '%s' instead of '%s'", library.ItemTypeVMTX, item.Type)
+	}
+
+	m := vcenter.NewManager(c)
+
+	info, err := m.GetLibraryTemplateInfo(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+
+	res := &versionsResult{
+		Current: vcenter.TemplateVersion{Version: item.ContentVersion, VMTemplate: info.VmTemplate},
+	}
+
+	res.Versions, err = m.ListTemplateVersions(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(res)
+}
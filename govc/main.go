@@ -26,6 +26,7 @@ import (
 	_ "github.com/vmware/govmomi/govc/cluster/module"
 	_ "github.com/vmware/govmomi/govc/cluster/override"
 	_ "github.com/vmware/govmomi/govc/cluster/rule"
+	_ "github.com/vmware/govmomi/govc/completion"
 	_ "github.com/vmware/govmomi/govc/datacenter"
 	_ "github.com/vmware/govmomi/govc/datastore"
 	_ "github.com/vmware/govmomi/govc/datastore/cluster"
@@ -75,14 +76,18 @@ import (
 	_ "github.com/vmware/govmomi/govc/ls"
 	_ "github.com/vmware/govmomi/govc/metric"
 	_ "github.com/vmware/govmomi/govc/metric/interval"
+	_ "github.com/vmware/govmomi/govc/namespace"
 	_ "github.com/vmware/govmomi/govc/namespace/cluster"
 	_ "github.com/vmware/govmomi/govc/namespace/service"
+	_ "github.com/vmware/govmomi/govc/namespace/vmclass"
 	_ "github.com/vmware/govmomi/govc/object"
 	_ "github.com/vmware/govmomi/govc/option"
 	_ "github.com/vmware/govmomi/govc/permissions"
+	_ "github.com/vmware/govmomi/govc/plugin"
 	_ "github.com/vmware/govmomi/govc/pool"
 	_ "github.com/vmware/govmomi/govc/role"
 	_ "github.com/vmware/govmomi/govc/session"
+	_ "github.com/vmware/govmomi/govc/shell"
 	_ "github.com/vmware/govmomi/govc/sso/group"
 	_ "github.com/vmware/govmomi/govc/sso/idp"
 	_ "github.com/vmware/govmomi/govc/sso/lpp"
@@ -98,9 +103,12 @@ import (
 	_ "github.com/vmware/govmomi/govc/vcsa/access/dcui"
 	_ "github.com/vmware/govmomi/govc/vcsa/access/shell"
 	_ "github.com/vmware/govmomi/govc/vcsa/access/ssh"
+	_ "github.com/vmware/govmomi/govc/vcsa/backup"
+	_ "github.com/vmware/govmomi/govc/vcsa/health"
 	_ "github.com/vmware/govmomi/govc/vcsa/log"
 	_ "github.com/vmware/govmomi/govc/vcsa/proxy"
 	_ "github.com/vmware/govmomi/govc/vcsa/shutdown"
+	_ "github.com/vmware/govmomi/govc/vcsa/svc"
 	_ "github.com/vmware/govmomi/govc/version"
 	_ "github.com/vmware/govmomi/govc/vm"
 	_ "github.com/vmware/govmomi/govc/vm/disk"
@@ -0,0 +1,362 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/performance"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// openMetricsType is the Content-Type of the OpenMetrics text format
+const openMetricsType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+type serve struct {
+	*PerformanceFlag
+
+	listen   string
+	refresh  time.Duration
+	prefix   string
+	instance string
+
+	mu      sync.Mutex
+	metrics []byte
+	paths   map[types.ManagedObjectReference]string
+}
+
+func init() {
+	cli.Register("metric.serve", &serve{})
+}
+
+func (cmd *serve) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.PerformanceFlag, ctx = NewPerformanceFlag(ctx)
+	cmd.PerformanceFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.listen, "l", "127.0.0.1:9272", "Listen address for HTTP server")
+	f.DurationVar(&cmd.refresh, "refresh", 20*time.Second, "Interval between samples")
+	f.StringVar(&cmd.prefix, "prefix", "vsphere", "Metric name prefix")
+	f.StringVar(&cmd.instance, "instance", "*", "Instance")
+}
+
+func (cmd *serve) Usage() string {
+	return "PATH... NAME..."
+}
+
+func (cmd *serve) Description() string {
+	return `Serve metric NAME for object PATH on an HTTP /metrics endpoint in OpenMetrics text format.
+
+Each counter is exported as a gauge named PREFIX_GROUP_NAME_ROLLUP, for example cpu.usage.average
+is exported as vsphere_cpu_usage_average.  Percentage counters are scaled to their percent value.
+Samples are labeled with the object's inventory path, type and moref, and the counter instance
+if the instance is not the aggregate.
+
+The latest sample is collected every REFRESH interval, the last successful sample is served if a refresh fails.
+PATH is resolved on each refresh, such that objects added to or removed from the inventory are included or dropped.
+See 'govc metric.sample' for the INSTANCE flag.
+
+Examples:
+  govc metric.serve host/cluster1/* cpu.usage.average mem.usage.average
+  govc metric.serve -l :9272 -refresh 1m -instance - vm/* net.bytesTx.average
+  curl -s http://127.0.0.1:9272/metrics`
+}
+
+func (cmd *serve) Process(ctx context.Context) error {
+	if err := cmd.PerformanceFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *serve) Run(ctx context.Context, f *flag.FlagSet) error {
+	m, err := cmd.Manager(ctx)
+	if err != nil {
+		return err
+	}
+
+	counters, err := m.CounterInfoByName(ctx)
+	if err != nil {
+		return err
+	}
+
+	var paths []string
+	var names []string
+
+	for _, arg := range f.Args() {
+		if _, ok := counters[arg]; ok {
+			names = append(names, arg)
+		} else {
+			paths = append(paths, arg)
+		}
+	}
+
+	if len(paths) == 0 || len(names) == 0 {
+		return flag.ErrHelp
+	}
+
+	objs, err := cmd.ManagedObjects(ctx, paths)
+	if err != nil {
+		return err
+	}
+
+	s, err := m.ProviderSummary(ctx, objs[0])
+	if err != nil {
+		return err
+	}
+
+	if cmd.instance == "-" {
+		cmd.instance = ""
+	}
+
+	spec := types.PerfQuerySpec{
+		Format:     string(types.PerfFormatNormal),
+		MaxSample:  1,
+		MetricId:   []types.PerfMetricId{{Instance: cmd.instance}},
+		IntervalId: cmd.Interval(s.RefreshRate),
+	}
+
+	collect := func() error {
+		// Resolve PATH on each refresh, such that objects are added and removed along with the inventory
+		objs, err := cmd.ManagedObjects(ctx, paths)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := cmd.collect(ctx, m, spec, names, objs, counters, &buf); err != nil {
+			return err
+		}
+		cmd.mu.Lock()
+		cmd.metrics = buf.Bytes()
+		cmd.mu.Unlock()
+		return nil
+	}
+
+	if err = collect(); err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", cmd.listen)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", cmd.ServeHTTP)
+	srv := &http.Server{Handler: mux}
+
+	go func() {
+		ticker := time.NewTicker(cmd.refresh)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				_ = srv.Close()
+				return
+			case <-ticker.C:
+				if err := collect(); err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[0], err)
+				}
+			}
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving metrics on http://%s/metrics\n", l.Addr())
+
+	err = srv.Serve(l)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+func (cmd *serve) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cmd.mu.Lock()
+	metrics := cmd.metrics
+	cmd.mu.Unlock()
+
+	w.Header().Set("Content-Type", openMetricsType)
+	_, _ = w.Write(metrics)
+}
+
+// inventoryPath returns the inventory path of the given entity, caching the result.
+func (cmd *serve) inventoryPath(ctx context.Context, ref types.ManagedObjectReference) string {
+	if p, ok := cmd.paths[ref]; ok {
+		return p
+	}
+
+	if cmd.paths == nil {
+		cmd.paths = make(map[types.ManagedObjectReference]string)
+	}
+
+	c, err := cmd.Client()
+	if err != nil {
+		return ""
+	}
+
+	p, err := find.InventoryPath(ctx, c, ref)
+	if err != nil {
+		return ""
+	}
+
+	cmd.paths[ref] = p
+	return p
+}
+
+type serveSample struct {
+	labels string
+	value  string
+	time   time.Time
+}
+
+// collect samples the given counters and writes them to w in OpenMetrics text format.
+func (cmd *serve) collect(ctx context.Context, m *performance.Manager, spec types.PerfQuerySpec, names []string,
+	objs []types.ManagedObjectReference, counters map[string]*types.PerfCounterInfo, w io.Writer) error {
+	sample, err := m.SampleByName(ctx, spec, names, objs)
+	if err != nil {
+		return err
+	}
+
+	result, err := m.ToMetricSeries(ctx, sample)
+	if err != nil {
+		return err
+	}
+
+	// Drop the cached inventory path of objects that no longer match, which may have been removed or moved
+	for ref := range cmd.paths {
+		found := false
+		for _, obj := range objs {
+			if obj == ref {
+				found = true
+				break
+			}
+		}
+		if !found {
+			delete(cmd.paths, ref)
+		}
+	}
+
+	samples := make(map[string][]serveSample)
+
+	for _, metric := range result {
+		if len(metric.SampleInfo) == 0 {
+			continue
+		}
+		ts := metric.SampleInfo[len(metric.SampleInfo)-1].Timestamp
+
+		labels := []string{
+			openMetricsLabel("entity", cmd.inventoryPath(ctx, metric.Entity)),
+			openMetricsLabel("type", metric.Entity.Type),
+			openMetricsLabel("moid", metric.Entity.Value),
+		}
+
+		for _, v := range metric.Value {
+			if len(v.Value) == 0 {
+				continue
+			}
+
+			l := labels
+			if v.Instance != "" {
+				l = append(l[:len(l):len(l)], openMetricsLabel("instance", v.Instance))
+			}
+
+			samples[v.Name] = append(samples[v.Name], serveSample{
+				labels: strings.Join(l, ","),
+				value:  openMetricsValue(counters[v.Name], v.Value[len(v.Value)-1]),
+				time:   ts,
+			})
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		counter := counters[name]
+		metric := openMetricsName(cmd.prefix, counter)
+
+		fmt.Fprintf(w, "# TYPE %s gauge\n", metric)
+		help := counter.NameInfo.GetElementDescription().Summary
+		if help != "" {
+			fmt.Fprintf(w, "# HELP %s %s\n", metric, openMetricsEscape(help))
+		}
+
+		for _, s := range samples[name] {
+			fmt.Fprintf(w, "%s{%s} %s %d\n", metric, s.labels, s.value, s.time.Unix())
+		}
+	}
+
+	_, err = fmt.Fprintln(w, "# EOF")
+	return err
+}
+
+// openMetricsName returns the metric name for the given counter, in the form of PREFIX_GROUP_NAME_ROLLUP
+func openMetricsName(prefix string, counter *types.PerfCounterInfo) string {
+	parts := []string{
+		counter.GroupInfo.GetElementDescription().Key,
+		counter.NameInfo.GetElementDescription().Key,
+		string(counter.RollupType),
+	}
+
+	if prefix != "" {
+		parts = append([]string{prefix}, parts...)
+	}
+
+	name := []byte(strings.Join(parts, "_"))
+
+	for i, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+		case c >= '0' && c <= '9' && i != 0:
+		default:
+			name[i] = '_'
+		}
+	}
+
+	return string(name)
+}
+
+// openMetricsValue formats val according to the counter's unit, percentages are stored as 1/100th of a percent.
+func openMetricsValue(counter *types.PerfCounterInfo, val int64) string {
+	if counter.UnitInfo.GetElementDescription().Key == string(types.PerformanceManagerUnitPercent) {
+		return strconv.FormatFloat(float64(val)/100.0, 'f', -1, 64)
+	}
+	return strconv.FormatInt(val, 10)
+}
+
+func openMetricsLabel(name, value string) string {
+	return name + `="` + openMetricsEscape(value) + `"`
+}
+
+var openMetricsReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func openMetricsEscape(s string) string {
+	return openMetricsReplacer.Replace(s)
+}
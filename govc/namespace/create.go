@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+type create struct {
+	*flags.ClusterFlag
+
+	spec specFlag
+}
+
+func init() {
+	cli.Register("namespace.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClusterFlag, ctx = flags.NewClusterFlag(ctx)
+	cmd.ClusterFlag.Register(ctx, f)
+
+	cmd.spec.Register(f)
+}
+
+func (cmd *create) Description() string {
+	return `Creates a vSphere Namespace on the Supervisor cluster.
+
+Examples:
+  govc namespace.create -cluster Workload-Cluster test-namespace
+  govc namespace.create -cluster Workload-Cluster -storage "vSAN Default Storage Policy" test-namespace
+  govc namespace.create -cluster Workload-Cluster -library images -vmclass best-effort-small -vmclass best-effort-medium test-namespace`
+}
+
+func (cmd *create) Usage() string {
+	return "NAME"
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	cluster, err := cmd.Cluster()
+	if err != nil {
+		return err
+	}
+
+	spec := namespace.NamespacesInstanceCreateSpec{
+		Cluster:   cluster.Reference().Value,
+		Namespace: f.Arg(0),
+	}
+
+	if cmd.spec.description != nil {
+		spec.Description = *cmd.spec.description
+	}
+
+	spec.StorageSpecs, err = cmd.spec.storageSpecs(ctx, cmd.ClientFlag)
+	if err != nil {
+		return err
+	}
+
+	vmService, err := cmd.spec.vmServiceSpec(ctx, cmd.ClientFlag)
+	if err != nil {
+		return err
+	}
+	if vmService != nil {
+		spec.VmServiceSpec = *vmService
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return namespace.NewManager(c).CreateNamespace(ctx, spec)
+}
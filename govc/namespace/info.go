@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("namespace.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Usage() string {
+	return "NAME"
+}
+
+func (cmd *info) Description() string {
+	return `Displays the details of a vSphere Namespace.
+
+Examples:
+  govc namespace.info test-namespace
+  govc namespace.info -json test-namespace | jq .`
+}
+
+type infoWriter struct {
+	Namespace namespace.NamespacesInstanceInfo
+}
+
+func (r *infoWriter) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	ns := r.Namespace
+	fmt.Fprintf(tw, "Cluster:\t%s\n", ns.ClusterId)
+	fmt.Fprintf(tw, "Status:\t%s\n", ns.ConfigStatus)
+	fmt.Fprintf(tw, "Description:\t%s\n", ns.Description)
+	for _, s := range ns.StorageSpecs {
+		fmt.Fprintf(tw, "Storage policy:\t%s\n", s.Policy)
+	}
+	fmt.Fprintf(tw, "Content libraries:\t%s\n", strings.Join(ns.VmServiceSpec.ContentLibraries, ","))
+	fmt.Fprintf(tw, "VM classes:\t%s\n", strings.Join(ns.VmServiceSpec.VmClasses, ","))
+	for _, a := range ns.AccessList {
+		fmt.Fprintf(tw, "Access:\t%s %s@%s %s\n", a.SubjectType, a.Subject, a.Domain, a.Role)
+	}
+
+	return tw.Flush()
+}
+
+func (r *infoWriter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Namespace)
+}
+
+func (r *infoWriter) Dump() interface{} {
+	return r.Namespace
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	ns, err := namespace.NewManager(c).GetNamespace(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&infoWriter{ns})
+}
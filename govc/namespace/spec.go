@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/govc/storage/policy"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+// specFlag contains the namespace settings shared by namespace.create and namespace.update
+type specFlag struct {
+	description *string
+	libraries   flags.StringList
+	vmClasses   flags.StringList
+	storage     flags.StringList
+}
+
+func (s *specFlag) Register(f *flag.FlagSet) {
+	f.Var(flags.NewOptionalString(&s.description), "description", "Description")
+	f.Var(&s.libraries, "library", "Content library name or ID to bind for VM images")
+	f.Var(&s.vmClasses, "vmclass", "VM class to bind")
+	f.Var(&s.storage, "storage", "Storage policy name to bind")
+}
+
+// storageSpecs looks up the storage policy IDs of the given policy names
+func (s *specFlag) storageSpecs(ctx context.Context, cmd *flags.ClientFlag) ([]namespace.StorageSpec, error) {
+	if len(s.storage) == 0 {
+		return nil, nil
+	}
+
+	c, err := cmd.PbmClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var specs []namespace.StorageSpec
+	for _, name := range s.storage {
+		policies, err := policy.ListProfiles(ctx, c, name)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up storage policy %q: %s", name, err)
+		}
+		if len(policies) != 1 {
+			return nil, fmt.Errorf("could not find a unique storage policy ID for query %q", name)
+		}
+		specs = append(specs, namespace.StorageSpec{Policy: policies[0].GetPbmProfile().ProfileId.UniqueId})
+	}
+
+	return specs, nil
+}
+
+// vmServiceSpec looks up the IDs of the given content libraries
+func (s *specFlag) vmServiceSpec(ctx context.Context, cmd *flags.ClientFlag) (*namespace.VmServiceSpec, error) {
+	if len(s.libraries) == 0 && len(s.vmClasses) == 0 {
+		return nil, nil
+	}
+
+	spec := &namespace.VmServiceSpec{VmClasses: s.vmClasses}
+
+	if len(s.libraries) != 0 {
+		c, err := cmd.RestClient()
+		if err != nil {
+			return nil, err
+		}
+
+		m := library.NewManager(c)
+		for _, name := range s.libraries {
+			id := name
+			if l, err := m.GetLibraryByName(ctx, name); err == nil {
+				id = l.ID
+			}
+			spec.ContentLibraries = append(spec.ContentLibraries, id)
+		}
+	}
+
+	return spec, nil
+}
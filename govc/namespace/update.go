@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+type update struct {
+	*flags.ClientFlag
+
+	spec specFlag
+}
+
+func init() {
+	cli.Register("namespace.update", &update{})
+}
+
+func (cmd *update) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.spec.Register(f)
+}
+
+func (cmd *update) Description() string {
+	return `Updates a vSphere Namespace.
+
+The -library, -vmclass and -storage flags replace the existing bindings of the namespace.
+Only the settings given are changed, the description can be reset with an explicit "".
+
+Examples:
+  govc namespace.update -description "Team A" test-namespace
+  govc namespace.update -library images -vmclass best-effort-small test-namespace
+  govc namespace.update -storage "vSAN Default Storage Policy" test-namespace`
+}
+
+func (cmd *update) Usage() string {
+	return "NAME"
+}
+
+func (cmd *update) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	spec := namespace.NamespacesInstanceUpdateSpec{
+		Description: cmd.spec.description,
+	}
+
+	var err error
+	spec.StorageSpecs, err = cmd.spec.storageSpecs(ctx, cmd.ClientFlag)
+	if err != nil {
+		return err
+	}
+
+	spec.VmServiceSpec, err = cmd.spec.vmServiceSpec(ctx, cmd.ClientFlag)
+	if err != nil {
+		return err
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return namespace.NewManager(c).UpdateNamespace(ctx, f.Arg(0), spec)
+}
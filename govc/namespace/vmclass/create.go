@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmclass
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+type create struct {
+	*flags.ClientFlag
+
+	spec namespace.VirtualMachineClassCreateSpec
+}
+
+func init() {
+	cli.Register("namespace.vmclass.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.Int64Var(&cmd.spec.CpuCount, "cpus", 0, "The number of CPUs")
+	f.Int64Var(&cmd.spec.MemoryMb, "memory", 0, "The amount of memory (in MB)")
+	f.Int64Var(&cmd.spec.CpuReservation, "cpu-reservation", 0, "Percentage of CPU reserved")
+	f.Int64Var(&cmd.spec.MemoryReservation, "memory-reservation", 0, "Percentage of memory reserved")
+	f.StringVar(&cmd.spec.Description, "description", "", "Description")
+}
+
+func (cmd *create) Description() string {
+	return `Creates a new virtual machine class.
+
+The name of the class must be a valid DNS label.
+
+Examples:
+  govc namespace.vmclass.create -cpus=8 -memory=8192 test-class-01
+  govc namespace.vmclass.create -cpus=4 -memory=4096 -cpu-reservation=100 -memory-reservation=100 test-class-02`
+}
+
+func (cmd *create) Usage() string {
+	return "NAME"
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	cmd.spec.ID = f.Arg(0)
+
+	return namespace.NewManager(c).CreateVmClass(ctx, cmd.spec)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmclass
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("namespace.vmclass.info", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return cmd.OutputFlag.Process(ctx)
+}
+
+func (cmd *info) Usage() string {
+	return "NAME"
+}
+
+func (cmd *info) Description() string {
+	return `Displays the details of a virtual machine class.
+
+Examples:
+  govc namespace.vmclass.info best-effort-small
+  govc namespace.vmclass.info -json best-effort-small | jq .`
+}
+
+type infoWriter struct {
+	Class namespace.VirtualMachineClassInfo
+}
+
+func (r *infoWriter) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	class := r.Class
+	fmt.Fprintf(tw, "Name:\t%s\n", class.ID)
+	fmt.Fprintf(tw, "Description:\t%s\n", class.Description)
+	fmt.Fprintf(tw, "CPUs:\t%d\n", class.CpuCount)
+	fmt.Fprintf(tw, "Memory:\t%dMB\n", class.MemoryMb)
+	fmt.Fprintf(tw, "CPU reservation:\t%d%%\n", class.CpuReservation)
+	fmt.Fprintf(tw, "Memory reservation:\t%d%%\n", class.MemoryReservation)
+	fmt.Fprintf(tw, "Status:\t%s\n", class.ConfigStatus)
+	fmt.Fprintf(tw, "Namespaces:\t%s\n", strings.Join(class.Namespaces, ","))
+
+	return tw.Flush()
+}
+
+func (r *infoWriter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Class)
+}
+
+func (r *infoWriter) Dump() interface{} {
+	return r.Class
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	class, err := namespace.NewManager(c).GetVmClass(ctx, f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&infoWriter{class})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vmclass
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/namespace"
+)
+
+type update struct {
+	*flags.ClientFlag
+
+	spec namespace.VirtualMachineClassUpdateSpec
+}
+
+func init() {
+	cli.Register("namespace.vmclass.update", &update{})
+}
+
+func (cmd *update) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.Var(flags.NewOptionalInt64(&cmd.spec.CpuCount), "cpus", "The number of CPUs")
+	f.Var(flags.NewOptionalInt64(&cmd.spec.MemoryMb), "memory", "The amount of memory (in MB)")
+	f.Var(flags.NewOptionalInt64(&cmd.spec.CpuReservation), "cpu-reservation", "Percentage of CPU reserved")
+	f.Var(flags.NewOptionalInt64(&cmd.spec.MemoryReservation), "memory-reservation", "Percentage of memory reserved")
+	f.Var(flags.NewOptionalString(&cmd.spec.Description), "description", "Description")
+}
+
+func (cmd *update) Description() string {
+	return `Modifies an existing virtual machine class.
+
+Only the settings given are changed, a reservation or the description can be reset with an explicit 0 or "".
+
+Examples:
+  govc namespace.vmclass.update -cpus=8 -memory=8192 test-class-01
+  govc namespace.vmclass.update -cpu-reservation=0 -description "" test-class-01`
+}
+
+func (cmd *update) Usage() string {
+	return "NAME"
+}
+
+func (cmd *update) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	return namespace.NewManager(c).UpdateVmClass(ctx, f.Arg(0), cmd.spec)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+)
+
+type ls struct {
+	*flags.OutputFlag
+
+	long bool
+}
+
+func init() {
+	cli.Register("plugin.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.long, "l", false, "Long listing format")
+}
+
+func (cmd *ls) Description() string {
+	return `List plugin commands.
+
+A plugin is an executable in PATH with a name prefixed by 'govc-'.
+'govc NAME [ARG]...' runs the 'govc-NAME' plugin with the given ARGs, if NAME is not a govc command.
+The plugin inherits the environment of govc, along with:
+  GOVC_URL, GOVC_USERNAME, GOVC_PASSWORD, etc - the connection variables, as output by 'govc env'
+  GOVMOMI_HOME - the directory containing the session cache, such that sessions can be shared with govc
+  GOVC_PLUGIN_EXE - the path to the govc executable
+
+Examples:
+  govc plugin.ls
+  cat > ~/bin/govc-vm.count <<'EOS'
+  #!/bin/sh
+  govc ls -t VirtualMachine "$@" | wc -l
+  EOS
+  chmod +x ~/bin/govc-vm.count
+  govc vm.count /DC0/vm`
+}
+
+type plugin struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type lsResult struct {
+	cmd     *ls
+	Plugins []plugin `json:"plugins"`
+}
+
+func (r *lsResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, p := range r.Plugins {
+		if r.cmd.long {
+			fmt.Fprintf(tw, "%s\t%s\n", p.Name, p.Path)
+		} else {
+			fmt.Fprintln(tw, p.Name)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	res := &lsResult{cmd: cmd}
+	commands := cli.Commands()
+
+	for name, path := range cli.Plugins() {
+		if _, ok := commands[name]; ok {
+			continue // commands take precedence over plugins
+		}
+		res.Plugins = append(res.Plugins, plugin{name, path})
+	}
+
+	sort.Slice(res.Plugins, func(i, j int) bool {
+		return res.Plugins[i].Name < res.Plugins[j].Name
+	})
+
+	return cmd.WriteResult(res)
+}
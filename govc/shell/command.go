@@ -0,0 +1,428 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+)
+
+type shell struct {
+	*flags.ClientFlag
+
+	batch  string
+	prompt string
+
+	// ctx holds the ClientFlag shared by each command run in the shell
+	ctx    context.Context
+	status int
+}
+
+func init() {
+	cli.Register("shell", &shell{})
+}
+
+func (cmd *shell) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, cmd.ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(cmd.ctx, f)
+
+	f.StringVar(&cmd.batch, "batch", "", "Read commands from FILE ('-' for stdin)")
+	f.StringVar(&cmd.prompt, "prompt", "govc> ", "Interactive prompt")
+}
+
+func (cmd *shell) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *shell) Usage() string {
+	return "[FILE]"
+}
+
+func (cmd *shell) Description() string {
+	return `Run govc commands using a single client connection.
+
+Each line is a govc command, without the leading 'govc'.
+The connection options (-u, -k, -cert, etc.) are set when the shell starts
+and the session is reused by all commands until the shell exits.
+
+Lines are split into words using shell-like quoting rules, '#' starts a comment.
+Variables are assigned using NAME=VALUE and expanded using $NAME or ${NAME},
+falling back to the environment.  Expanded values are not split into multiple words.
+$? expands to the exit code of the previous command.
+Assignments are exported to the environment, such that GOVC_* variables apply to
+the commands that follow, with the exception of connection variables such as GOVC_URL.
+
+In interactive mode, a failed command does not exit the shell.
+In batch mode, execution stops at the first failed command, unless the line is prefixed with '-'.
+'govc -batch FILE' is shorthand for 'govc shell -batch FILE'.
+
+Interactive mode supports TAB completion of command names, flags and inventory paths.
+
+Examples:
+  govc shell -u user:pass@vcenter
+  govc shell < commands.txt
+  govc -batch commands.txt -u user:pass@vcenter
+  cat > commands.txt <<EOF
+  vm=/DC0/vm/DC0_H0_VM0
+  vm.power -off $vm
+  -vm.destroy /DC0/vm/enoent # ignore error
+  vm.info -json $vm
+  EOF`
+}
+
+// errExit is returned by exec when the 'exit' builtin is used
+type errExit int
+
+func (e errExit) Error() string {
+	return "exit " + strconv.Itoa(int(e))
+}
+
+func (e errExit) ExitCode() int {
+	return int(e)
+}
+
+// exitError propagates a non-zero command exit code from batch mode
+type exitError struct {
+	line int
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("line %d: exit %d", e.line, e.code)
+}
+
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+func (cmd *shell) Run(ctx context.Context, f *flag.FlagSet) error {
+	defer func() {
+		_ = cmd.ClientFlag.Logout(ctx)
+	}()
+
+	file := cmd.batch
+
+	switch f.NArg() {
+	case 0:
+	case 1:
+		if file != "" {
+			return flag.ErrHelp
+		}
+		file = f.Arg(0)
+	default:
+		return flag.ErrHelp
+	}
+
+	if file == "" && term.IsTerminal(int(os.Stdin.Fd())) {
+		return cmd.interactive()
+	}
+
+	in := os.Stdin
+	if file != "" && file != "-" {
+		var err error
+		in, err = os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+	}
+
+	return cmd.script(in, cmd.batch != "")
+}
+
+// script runs commands read from r, stopping on error if batch is true.
+func (cmd *shell) script(r io.Reader, batch bool) error {
+	scanner := bufio.NewScanner(r)
+	n := 0
+
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+
+		ignore := strings.HasPrefix(line, "-")
+		if ignore {
+			line = line[1:]
+		}
+
+		ran, err := cmd.exec(line)
+		if err != nil {
+			if e, ok := err.(errExit); ok {
+				if e == 0 {
+					return nil
+				}
+				return &exitError{n, int(e)}
+			}
+			fmt.Fprintf(os.Stderr, "%s: line %d: %s\n", os.Args[0], n, err)
+			cmd.status = 1
+			ran = true
+		}
+
+		// blank and comment lines keep the status of the previous line, but do not fail the batch
+		if batch && ran && cmd.status != 0 && !ignore {
+			return &exitError{n, cmd.status}
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (cmd *shell) interactive() error {
+	fd := int(os.Stdin.Fd())
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, cmd.prompt)
+
+	c := completer{shell: cmd, t: t}
+	c.completer.Client = cmd.Client
+	t.AutoCompleteCallback = c.complete
+
+	for {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+
+		line, err := t.ReadLine()
+		_ = term.Restore(fd, state)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if _, err = cmd.exec(line); err != nil {
+			if e, ok := err.(errExit); ok {
+				if e == 0 {
+					return nil
+				}
+				return e
+			}
+			fmt.Fprintf(os.Stderr, "%s: %s\n", os.Args[0], err)
+			cmd.status = 1
+		}
+	}
+}
+
+// exec runs a single line, which can be empty, a variable assignment, a builtin or a govc command.
+// The returned bool is false if the line was empty, in which case the status is unchanged.
+func (cmd *shell) exec(line string) (bool, error) {
+	args, err := cmd.split(line)
+	if err != nil {
+		return false, err
+	}
+
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	if len(args) == 1 {
+		if name, value, ok := assignment(args[0]); ok {
+			cmd.status = 0
+			return true, os.Setenv(name, value)
+		}
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		code := cmd.status
+		if len(args) > 1 {
+			if code, err = strconv.Atoi(args[1]); err != nil {
+				return true, err
+			}
+		}
+		return true, errExit(code)
+	case "shell":
+		return true, errors.New("shell: cannot be nested")
+	}
+
+	ctx, cancel := context.WithCancel(cmd.ctx)
+	defer cancel()
+
+	// SIGINT cancels the command rather than exiting the shell
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT)
+	defer signal.Stop(sig)
+
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	cmd.status = cli.Execute(ctx, args)
+
+	return true, nil
+}
+
+// assignment returns the NAME and VALUE of a NAME=VALUE word
+func assignment(word string) (string, string, bool) {
+	i := strings.IndexRune(word, '=')
+	if i <= 0 {
+		return "", "", false
+	}
+
+	name := word[:i]
+	for j, r := range name {
+		if !isNameRune(r) || (j == 0 && r >= '0' && r <= '9') {
+			return "", "", false
+		}
+	}
+
+	return name, word[i+1:], true
+}
+
+func isNameRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+// lookup returns the value of variable name, including the special $? variable
+func (cmd *shell) lookup(name string) string {
+	if name == "?" {
+		return strconv.Itoa(cmd.status)
+	}
+	return os.Getenv(name)
+}
+
+// split parses line into words, using quoting rules similar to sh(1):
+// No expansion is done within single quotes, $VAR and ${VAR} are expanded
+// outside of quotes and within double quotes, a backslash escapes the next character
+// and an unquoted '#' at the start of a word starts a comment.
+// Unlike sh(1), expanded values are not subject to word splitting.
+func (cmd *shell) split(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord := false
+
+	runes := []rune(line)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch quote {
+		case '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+			continue
+		case '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`, runes[i+1]):
+				i++
+				word.WriteRune(runes[i])
+			case r == '$':
+				i = cmd.expand(runes, i, &word)
+			default:
+				word.WriteRune(r)
+			}
+			continue
+		}
+
+		switch {
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case r == '#' && !inWord:
+			return words, nil
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+		case r == '$':
+			i = cmd.expand(runes, i, &word)
+		default:
+			word.WriteRune(r)
+		}
+
+		inWord = true
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// expand writes the value of the variable referenced at runes[i] to word,
+// returning the index of the last rune consumed.
+func (cmd *shell) expand(runes []rune, i int, word *strings.Builder) int {
+	rest := runes[i+1:]
+
+	switch {
+	case len(rest) == 0:
+	case rest[0] == '?':
+		word.WriteString(cmd.lookup("?"))
+		return i + 1
+	case rest[0] == '{':
+		for j, r := range rest {
+			if r == '}' {
+				word.WriteString(cmd.lookup(string(rest[1:j])))
+				return i + 1 + j
+			}
+		}
+	default:
+		j := 0
+		for j < len(rest) && isNameRune(rest[j]) {
+			j++
+		}
+		if j != 0 {
+			word.WriteString(cmd.lookup(string(rest[:j])))
+			return i + j
+		}
+	}
+
+	word.WriteRune('$')
+	return i
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	os.Setenv("GOVC_TEST_VM", "/DC0/vm/my vm")
+	defer os.Unsetenv("GOVC_TEST_VM")
+
+	cmd := &shell{status: 3}
+
+	tests := []struct {
+		line  string
+		words []string
+	}{
+		{"", nil},
+		{"   # comment", nil},
+		{"ls", []string{"ls"}},
+		{"ls  -l\t/DC0 # comment", []string{"ls", "-l", "/DC0"}},
+		{"vm.info $GOVC_TEST_VM", []string{"vm.info", "/DC0/vm/my vm"}},
+		{`vm.info "$GOVC_TEST_VM"`, []string{"vm.info", "/DC0/vm/my vm"}},
+		{`vm.info "${GOVC_TEST_VM}/x"`, []string{"vm.info", "/DC0/vm/my vm/x"}},
+		{`vm.info '$GOVC_TEST_VM'`, []string{"vm.info", "$GOVC_TEST_VM"}},
+		{`vm.info my\ vm "a\"b" a#b`, []string{"vm.info", "my vm", `a"b`, "a#b"}},
+		{"echo $? $ ${GOVC_TEST_ENOENT}", []string{"echo", "3", "$", ""}},
+		{`find "" -name x`, []string{"find", "", "-name", "x"}},
+	}
+
+	for _, test := range tests {
+		words, err := cmd.split(test.line)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(words, test.words) {
+			t.Errorf("split(%q)=%q, expected %q", test.line, words, test.words)
+		}
+	}
+
+	for _, line := range []string{`ls '/DC0`, `ls "/DC0`} {
+		_, err := cmd.split(line)
+		if err == nil {
+			t.Errorf("expected error for %q", line)
+		}
+	}
+}
+
+func TestAssignment(t *testing.T) {
+	tests := []struct {
+		word  string
+		name  string
+		value string
+		ok    bool
+	}{
+		{"vm=/DC0/vm/foo", "vm", "/DC0/vm/foo", true},
+		{"GOVC_DATACENTER=", "GOVC_DATACENTER", "", true},
+		{"a=b=c", "a", "b=c", true},
+		{"=foo", "", "", false},
+		{"1a=foo", "", "", false},
+		{"-dc=DC0", "", "", false},
+		{"ls", "", "", false},
+	}
+
+	for _, test := range tests {
+		name, value, ok := assignment(test.word)
+		if name != test.name || value != test.value || ok != test.ok {
+			t.Errorf("assignment(%q)=%q,%q,%t", test.word, name, value, ok)
+		}
+	}
+}
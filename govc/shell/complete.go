@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/term"
+
+	"github.com/vmware/govmomi/govc/completion"
+)
+
+const keyTab = '\t'
+
+type completer struct {
+	*shell
+
+	t         *term.Terminal
+	completer completion.Completer
+}
+
+// complete implements the term.Terminal AutoCompleteCallback,
+// completing the word before the cursor when TAB is pressed.
+func (c *completer) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != keyTab {
+		return "", 0, false
+	}
+
+	prefix, suffix := line[:pos], line[pos:]
+	words := strings.Fields(prefix)
+
+	word := ""
+	if len(words) != 0 && !strings.HasSuffix(prefix, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	c.completer.Datacenter = os.Getenv("GOVC_DATACENTER")
+	matches := c.completer.Complete(c.ctx, append(words, word))
+
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	match := commonPrefix(matches)
+	if len(matches) == 1 && !strings.HasSuffix(match, "/") && !strings.HasSuffix(match, "=") {
+		match += " "
+	}
+
+	if match == word {
+		c.list(matches)
+		return "", 0, false
+	}
+
+	prefix = prefix[:len(prefix)-len(word)] + match
+
+	return prefix + suffix, len(prefix), true
+}
+
+// list writes the given completion candidates to the terminal
+func (c *completer) list(matches []string) {
+	// buffer output as each terminal Write redraws the prompt
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 2, 0, 2, ' ', 0)
+	for i, m := range matches {
+		sep := "\t"
+		if (i+1)%4 == 0 || i == len(matches)-1 {
+			sep = "\n"
+		}
+		fmt.Fprintf(tw, "%s%s", path.Base(m), sep)
+	}
+	_ = tw.Flush()
+	_, _ = c.t.Write(buf.Bytes())
+}
+
+func commonPrefix(matches []string) string {
+	prefix := matches[0]
+
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	return prefix
+}
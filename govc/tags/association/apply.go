@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package association
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type apply struct {
+	*flags.DatacenterFlag
+
+	tags   flags.StringList
+	file   string
+	create bool
+	dryRun bool
+}
+
+func init() {
+	cli.Register("tags.apply", &apply{})
+}
+
+func (cmd *apply) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.DatacenterFlag, ctx = flags.NewDatacenterFlag(ctx)
+	cmd.DatacenterFlag.Register(ctx, f)
+
+	f.Var(&cmd.tags, "t", "Desired tag (category:name)")
+	f.StringVar(&cmd.file, "f", "", "JSON file mapping object PATH to desired tags (use '-' for STDIN)")
+	f.BoolVar(&cmd.create, "create", false, "Create missing categories and tags")
+	f.BoolVar(&cmd.dryRun, "n", false, "Dry run, print changes without applying them")
+}
+
+func (cmd *apply) Usage() string {
+	return "PATH..."
+}
+
+func (cmd *apply) Description() string {
+	return `Reconcile tags attached to object PATH with the desired set of tags.
+
+Tags attached to PATH that are not in the desired set are detached,
+desired tags that are not attached to PATH are attached.
+The -f flag can be used to specify the desired tags per object, for example:
+  {"/dc1/vm/vm1": ["env:prod", "team:web"], "/dc1/host/cluster1": []}
+
+Examples:
+  govc tags.apply -t env:prod -t team:web /dc1/vm/vm1 /dc1/vm/vm2
+  govc tags.apply /dc1/vm/vm1 # detach all tags
+  govc tags.apply -n -create -f tags.json
+  govc tags.apply -json -create -f - < tags.json | jq .`
+}
+
+type applyResult struct {
+	*tags.ReconcileResult
+	paths map[types.ManagedObjectReference]string
+}
+
+func join(list []tags.CategoryTag) string {
+	s := make([]string, len(list))
+	for i := range list {
+		s[i] = list[i].String()
+	}
+	return strings.Join(s, ",")
+}
+
+func (r *applyResult) Write(w io.Writer) error {
+	for _, name := range r.Categories {
+		fmt.Fprintf(w, "Create category %s\n", name)
+	}
+
+	for _, tag := range r.Tags {
+		fmt.Fprintf(w, "Create tag %s\n", tag)
+	}
+
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, change := range r.Changes {
+		fmt.Fprintf(tw, "%s:", r.paths[change.Object])
+		if len(change.Attach) != 0 {
+			fmt.Fprintf(tw, "\tattach %s", join(change.Attach))
+		}
+		if len(change.Detach) != 0 {
+			fmt.Fprintf(tw, "\tdetach %s", join(change.Detach))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *apply) desired(f *flag.FlagSet) (map[string][]string, error) {
+	spec := make(map[string][]string)
+
+	if cmd.file != "" {
+		var r io.Reader = os.Stdin
+		if cmd.file != "-" {
+			file, err := os.Open(cmd.file)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			r = file
+		}
+
+		if err := json.NewDecoder(r).Decode(&spec); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, arg := range f.Args() {
+		spec[arg] = append(spec[arg], cmd.tags...)
+	}
+
+	return spec, nil
+}
+
+func (cmd *apply) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 && cmd.file == "" {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	desired, err := cmd.desired(f)
+	if err != nil {
+		return err
+	}
+
+	spec := tags.ReconcileSpec{
+		Objects: make(map[types.ManagedObjectReference][]tags.CategoryTag, len(desired)),
+		Create:  cmd.create,
+		DryRun:  cmd.dryRun,
+	}
+	paths := make(map[types.ManagedObjectReference]string, len(desired))
+
+	for path, names := range desired {
+		ref, err := convertPath(ctx, c, cmd.DatacenterFlag, path)
+		if err != nil {
+			return err
+		}
+
+		list := []tags.CategoryTag{}
+		for _, name := range names {
+			tag, err := tags.ParseCategoryTag(name)
+			if err != nil {
+				return err
+			}
+			list = append(list, tag)
+		}
+
+		spec.Objects[*ref] = list
+		paths[*ref] = path
+	}
+
+	res, err := tags.NewManager(c).Reconcile(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&applyResult{res, paths})
+}
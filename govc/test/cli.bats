@@ -292,3 +292,133 @@ load test_helper
   run govc volume.ls -verbose # cns.Client
   assert_success
 }
+
+@test "shell" {
+  vcsim_env
+
+  run govc shell <<EOS
+ls /DC0
+vm=/DC0/vm/DC0_H0_VM0
+vm.power -off \$vm
+vm.info "\$vm"
+EOS
+  assert_success
+  assert_matches "Powering off"
+  assert_matches "Name: *DC0_H0_VM0"
+
+  run govc shell <<EOS
+vm.info /DC0/vm/enoent
+exit 3
+EOS
+  assert_equal 3 "$status"
+
+  script=$($mktemp)
+
+  cat > "$script" <<EOS
+-vm.destroy /DC0/vm/enoent
+vm.info /DC0/vm/DC0_H0_VM1
+EOS
+  run govc -batch "$script"
+  assert_success
+  assert_matches "Name: *DC0_H0_VM1"
+
+  cat > "$script" <<EOS
+-vm.destroy /DC0/vm/enoent
+
+# the ignored failure above does not fail the batch
+vm.info /DC0/vm/DC0_H0_VM1
+EOS
+  run govc -batch "$script"
+  assert_success
+  assert_matches "Name: *DC0_H0_VM1"
+
+  cat > "$script" <<EOS
+vm.destroy /DC0/vm/enoent
+vm.info /DC0/vm/DC0_H0_VM1
+EOS
+  run govc -batch "$script"
+  assert_failure
+  refute_line "Name:               DC0_H0_VM1"
+
+  run govc shell -batch "$script" extra-arg
+  assert_failure
+
+  rm "$script"
+}
+
+@test "completion" {
+  vcsim_env
+
+  run govc completion
+  assert_failure
+
+  run govc completion enoent
+  assert_failure
+
+  for shell in bash zsh fish ; do
+    run govc completion $shell
+    assert_success
+  done
+
+  run govc completion -complete -- vm.po
+  assert_success "vm.power"
+
+  run govc completion -complete -- vm.power -o
+  assert_success
+  assert_line "-off"
+  assert_line "-on"
+
+  run govc completion -complete -- device.ls -vm DC0_H0_VM
+  assert_success
+  assert_line DC0_H0_VM0
+  assert_line DC0_H0_VM1
+
+  run govc completion -complete -- device.ls -vm=/DC0/vm/DC0_H0_VM0
+  assert_success "-vm=/DC0/vm/DC0_H0_VM0"
+
+  run govc completion -complete -- ls /DC0/
+  assert_success
+  assert_line /DC0/vm/
+  assert_line /DC0/host/
+
+  run govc completion -complete -- vm.create -host /DC0/host/DC0_C0/
+  assert_success
+  assert_line /DC0/host/DC0_C0/DC0_C0_H0
+  refute_line /DC0/host/DC0_C0/Resources/
+
+  run env -u GOVC_URL govc completion -complete -- ls /DC0/
+  assert_success ""
+}
+
+@test "plugin" {
+  vcsim_env
+
+  dir=$($mktemp -d)
+  cat > "$dir/govc-test.plugin" <<'EOS'
+#!/bin/sh
+echo "args=$*"
+env | grep -E '^(GOVC_URL|GOVC_USERNAME|GOVMOMI_HOME)='
+exit 3
+EOS
+  chmod +x "$dir/govc-test.plugin"
+
+  run govc test.plugin
+  assert_failure # not in PATH
+
+  PATH="$dir:$PATH"
+
+  run govc plugin.ls
+  assert_success "test.plugin"
+
+  run govc test.plugin -a b
+  assert_equal 3 "$status"
+  assert_line "args=-a b"
+  assert_line "GOVC_USERNAME=$(govc env GOVC_USERNAME)"
+  assert_line "GOVC_URL=$(govc env GOVC_URL)"
+  assert_matches "GOVMOMI_HOME="
+
+  run govc completion -complete -- test.plu
+  assert_success "test.plugin"
+
+  rm -rf "$dir"
+}
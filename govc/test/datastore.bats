@@ -198,6 +198,64 @@ upload_file() {
   assert_output "Hello world"
 }
 
+@test "datastore.sync" {
+  vcsim_env
+
+  src=$($mktemp --tmpdir -d govc-sync-src-XXXXXX)
+  dst=$($mktemp --tmpdir -d govc-sync-dst-XXXXXX)
+  mkdir -p "$src/a/b" "$src/skip"
+  echo one > "$src/a/one"
+  echo two > "$src/a/b/two"
+  echo skip > "$src/skip/file"
+
+  run govc datastore.sync -exclude skip "$src" sync
+  assert_success
+  assert_line "upload 4B a/b/two"
+  refute_line "mkdir skip"
+
+  run govc datastore.ls sync/a/b/two
+  assert_success
+
+  run govc datastore.sync -dry-run -exclude skip "$src" sync
+  assert_success ""
+
+  rm "$src/a/b/two"
+  run govc datastore.sync -delete -dry-run -exclude skip "$src" sync
+  assert_success "delete  4B  a/b/two"
+
+  run govc datastore.ls sync/a/b/two
+  assert_success
+
+  run govc datastore.sync -delete -exclude skip "$src" sync
+  assert_success
+
+  run govc datastore.ls sync/a/b/two
+  assert_failure
+
+  run govc datastore.sync -download sync "$dst"
+  assert_success
+
+  run cat "$dst/a/one"
+  assert_success "one"
+
+  run govc datastore.sync -dry-run -download sync "$dst"
+  assert_success ""
+
+  run govc datastore.sync -delete "$src/enoent" sync
+  assert_failure # missing source must not delete DEST files
+
+  run govc datastore.sync -delete -download enoent "$dst"
+  assert_failure
+
+  run govc datastore.ls sync/a/one
+  assert_success
+
+  run cat "$dst/a/one"
+  assert_success "one"
+
+  rm -rf "$src" "$dst"
+}
+
 @test "datastore.tail" {
   esx_env
 
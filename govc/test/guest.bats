@@ -155,6 +155,80 @@ vcsim_guest() {
   assert_success
   assert_matches FOO=bar
   assert_matches PWD=/tmp
+
+  run govc guest.run -stream uname -a
+  assert_success
+  assert_matches Linux
+
+  run govc guest.run -stream -timeout 2s sleep 30
+  assert_failure
+  assert_matches "deadline exceeded"
+
+  run govc guest.run -stream -d "hello stream" cat
+  assert_success "hello stream"
+
+  run bash -c "(echo one; sleep 2; echo two) | govc guest.run -stream -d - cat"
+  assert_success
+  assert_line one
+  assert_line two
+}
+
+@test "guest.sync" {
+  vcsim_guest
+
+  dir=$($mktemp --tmpdir -d govc-test-XXXXX)
+  mkdir -p "$dir/src/a/b"
+  echo one > "$dir/src/a/one"
+  echo two > "$dir/src/a/b/two"
+  chmod 0750 "$dir/src/a/b/two"
+
+  run govc guest.sync "$dir/src" /tmp/sync
+  assert_success
+  assert_matches "upload.*a/b/two"
+
+  run govc guest.sync "$dir/src" /tmp/sync
+  assert_success ""  # unchanged
+
+  run govc guest.sync -f -json "$dir/src" /tmp/sync
+  assert_success
+  assert_equal 2 "$(jq '[.changes[] | select(.op == "upload")] | length' <<<"$output")"
+
+  run govc guest.sync -download /tmp/sync "$dir/dst"
+  assert_success
+
+  run diff -r "$dir/src" "$dir/dst"
+  assert_success
+
+  assert_equal 750 "$(stat -c %a "$dir/dst/a/b/two")"
+
+  run govc guest.sync -download /tmp/enoent "$dir/dst"
+  assert_failure
+
+  run govc guest.sync -f -verify "$dir/src" /tmp/sync
+  assert_success
+  assert_matches "upload.*a/b/two"
+
+  run govc guest.sync -f -verify -download /tmp/sync "$dir/dst"
+  assert_success
+  assert_matches "download.*a/b/two"
+
+  run govc guest.sync "$dir/enoent" /tmp/sync
+  assert_failure
+
+  echo eno > "$dir/dst/a/one" # same size and newer, different content
+
+  run govc guest.sync -download /tmp/sync "$dir/dst"
+  assert_success ""
+
+  run govc guest.sync -checksum -download /tmp/sync "$dir/dst"
+  assert_success
+  assert_matches "download.*a/one"
+  assert_equal 0 "$(grep -c "a/b/two" <<<"$output")"
+
+  run diff -r "$dir/src" "$dir/dst"
+  assert_success
+
+  rm -rf "$dir"
 }
 
 @test "guest tools status" {
@@ -181,3 +255,108 @@ vcsim_guest() {
   run govc guest.run uname -a
   assert_failure # powered off
 }
+
+@test "guest.reg" {
+  vcsim_env
+
+  export GOVC_VM=DC0_H0_VM0 GOVC_GUEST_LOGIN=user:pass
+
+  key='HKEY_LOCAL_MACHINE\SOFTWARE\govc'
+
+  run govc guest.reg.mkkey "$key"
+  assert_failure # parent does not exist
+
+  run govc guest.reg.mkkey 'HKEY_LOCAL_MACHINE\SOFTWARE'
+  assert_success
+
+  run govc guest.reg.mkkey "$key"
+  assert_success
+
+  run govc guest.reg.mkkey "$key"
+  assert_failure # exists
+
+  run govc guest.reg.mkkey "$key\\sub"
+  assert_success
+
+  run govc guest.reg.ls -r 'HKEY_LOCAL_MACHINE'
+  assert_success
+  assert_line 'HKEY_LOCAL_MACHINE\SOFTWARE\govc\sub'
+
+  run govc guest.reg.set -type dword "$key" Enabled 0x10
+  assert_success
+
+  run govc guest.reg.set -type multi "$key" Servers a b
+  assert_success
+
+  run govc guest.reg.get "$key" Enabled
+  assert_success 0x10
+
+  run govc guest.reg.set -type dword "$key" Mask 0xffffffff
+  assert_success
+
+  run govc guest.reg.get "$key" Mask
+  assert_success 0xffffffff
+
+  run govc guest.reg.set -type qword "$key" Mask64 0xffffffffffffffff
+  assert_success
+
+  run govc guest.reg.get "$key" Mask64
+  assert_success 0xffffffffffffffff
+
+  run govc guest.reg.get "$key"
+  assert_success
+  assert_line 'Servers REG_MULTI_SZ a\0b'
+
+  run govc guest.reg.rm "$key" Enabled
+  assert_success
+
+  run govc guest.reg.rm "$key" Enabled
+  assert_failure
+
+  run govc guest.reg.rmkey "$key"
+  assert_failure # has subkeys
+
+  run govc guest.reg.rmkey -r "$key"
+  assert_success
+
+  run govc guest.reg.ls 'HKEY_LOCAL_MACHINE\SOFTWARE'
+  assert_success ""
+}
+
+@test "guest.alias" {
+  vcsim_env
+
+  export GOVC_VM=DC0_H0_VM0 GOVC_GUEST_LOGIN=user:pass
+
+  cert=$($mktemp --tmpdir govc-cert-XXXXXX)
+  base64 <<<"fake certificate" > "$cert"
+
+  run govc guest.alias.add -user root -signer "$cert" -map -comment test administrator@vsphere.local
+  assert_success
+
+  run govc guest.alias.add -user root -signer "$cert" -any
+  assert_success
+
+  run govc guest.alias.ls -user root
+  assert_success
+  assert_matches "administrator@vsphere.local"
+  assert_equal 2 "${#lines[@]}"
+
+  run govc guest.alias.ls -mapped
+  assert_success
+  assert_matches "root *administrator@vsphere.local"
+
+  run govc guest.alias.rm -user root -signer "$cert" administrator@vsphere.local
+  assert_success
+
+  run govc guest.alias.ls -mapped
+  assert_success ""
+
+  run govc guest.alias.rm -user root -signer "$cert"
+  assert_success
+
+  run govc guest.alias.ls -user root
+  assert_success ""
+
+  rm "$cert"
+}
@@ -337,6 +337,53 @@ EOF
   assert_failure # $item no longer exists
 }
 
+@test "library.versions" {
+  vcsim_env
+
+  vm=DC0_H0_VM0
+  item="${vm}_item"
+
+  run govc library.create my-content
+  assert_success
+
+  run govc library.clone -vm $vm my-content $item
+  assert_success
+
+  v1=$(govc ls -i vm/$item)
+
+  run govc library.versions my-content/$item
+  assert_success
+  assert_equal 2 ${#lines[@]}
+
+  run govc library.checkout my-content/$item my-vm-checkout
+  assert_success
+
+  run govc library.checkin -vm my-vm-checkout my-content/$item
+  assert_success
+
+  run govc library.versions -json my-content/$item
+  assert_success
+  assert_equal 2 "$(jq -r .current.version <<<"$output")"
+  assert_equal 1 "$(jq -r '.versions | length' <<<"$output")"
+
+  run govc object.collect -s vm/$item-v1 config.template
+  assert_success "true"
+
+  run govc library.rollback my-content/$item enoent
+  assert_failure
+
+  run govc library.rollback -m "undo" my-content/$item 1
+  assert_success
+
+  run govc ls -i vm/$item
+  assert_success "$v1"
+
+  run govc library.versions -json my-content/$item
+  assert_success
+  assert_equal 3 "$(jq -r .current.version <<<"$output")"
+  assert_equal 2 "$(jq -r '.versions[0].version' <<<"$output")"
+}
+
 @test "library.vmtx.info" {
   vcsim_env
 
@@ -551,3 +598,79 @@ EOF
   # remove generated cert and key
   rm "$pem".{crt,key}
 }
+
+@test "library.mirror" {
+  vcsim_env
+
+  run govc library.create my-content
+  assert_success
+
+  run govc library.import -t iso -n boot my-content cli.bats
+  assert_success
+
+  run govc library.import -n library.bats my-content library.bats
+  assert_success
+
+  run govc library.mirror my-content my-mirror
+  assert_failure # -target required
+
+  run govc library.mirror -target "$GOVC_URL" my-content my-mirror
+  assert_failure # -ds required to create my-mirror
+
+  run govc library.mirror -target "$GOVC_URL" -ds LocalDS_0 my-content my-mirror
+  assert_success
+  assert_matches "my-mirror/boot +iso +1 +created"
+  assert_matches "my-mirror/library.bats +1 +created"
+
+  run govc library.ls my-mirror/
+  assert_success
+  assert_equal 2 ${#lines[@]}
+
+  run govc library.export my-mirror/library.bats/library.bats -
+  assert_success "$(cat library.bats)"
+
+  run govc library.mirror -target "$GOVC_URL" -json my-content my-mirror
+  assert_success
+  assert_equal 2 "$(jq '[.[] | select(.status == "unchanged")] | length' <<<"$output")"
+
+  run govc library.import my-content/library.bats test_helper.bash
+  assert_success
+
+  run govc library.mirror -target "$GOVC_URL" my-content my-mirror
+  assert_success
+  assert_matches "my-mirror/boot +iso +1 +unchanged"
+  assert_matches "my-mirror/library.bats +2 +updated"
+
+  run govc library.export my-mirror/library.bats/test_helper.bash -
+  assert_success "$(cat test_helper.bash)"
+
+  run govc library.clone -vm DC0_H0_VM0 my-content my-vmtx
+  assert_success
+
+  run govc library.mirror -target "$GOVC_URL" -scratch enoent my-content my-mirror
+  assert_failure # scratch library does not exist
+
+  run govc library.create my-scratch
+  assert_success
+
+  run govc library.mirror -target "$GOVC_URL" -scratch my-scratch my-content my-mirror
+  assert_success
+  assert_matches "my-mirror/my-vmtx +ovf .* +created"
+
+  run govc library.mirror -target "$GOVC_URL" -scratch my-scratch my-content my-mirror
+  assert_success
+  assert_matches "my-mirror/my-vmtx +ovf .* +unchanged"
+
+  run govc library.checkout my-content/my-vmtx my-vm-checkout
+  assert_success
+
+  run govc library.checkin -vm my-vm-checkout my-content/my-vmtx
+  assert_success
+
+  run govc library.mirror -target "$GOVC_URL" -scratch my-scratch my-content my-mirror
+  assert_success
+  assert_matches "my-mirror/my-vmtx +ovf .* +updated"
+
+  run govc library.ls my-scratch/
+  assert_success ""
+}
@@ -60,6 +60,69 @@ load test_helper
   assert_success
 }
 
+@test "metric.serve" {
+  vcsim_env
+
+  host=$(govc ls -t HostSystem ./... | head -n 1)
+
+  run govc metric.serve "$host" enoent
+  assert_failure
+
+  log="$BATS_TMPDIR/$(new_id)"
+  govc metric.serve -l 127.0.0.1:0 -refresh 1s "$host" cpu.usage.average mem.usage.average 2>"$log" &
+  pid=$!
+
+  while ! grep -q Serving "$log"; do
+    sleep 0.1
+  done
+
+  url=$(awk '{print $NF}' "$log")
+
+  run curl -sf "$url"
+  assert_success
+  assert_matches "# TYPE vsphere_cpu_usage_average gauge"
+  assert_matches "vsphere_mem_usage_average{entity=\"$host\",type=\"HostSystem\""
+  assert_equal "# EOF" "${lines[-1]}"
+
+  kill "$pid"
+  rm -f "$log"
+
+  # PATH is resolved on each refresh
+  govc metric.serve -l 127.0.0.1:0 -refresh 1s "/DC0/vm/*" cpu.usage.average 2>"$log" &
+  pid=$!
+
+  while ! grep -q Serving "$log"; do
+    sleep 0.1
+  done
+
+  url=$(awk '{print $NF}' "$log")
+
+  run curl -sf "$url"
+  assert_success
+  assert_equal 0 "$(grep -c 'entity="/DC0/vm/my-vm"' <<<"$output")"
+
+  run govc vm.create my-vm
+  assert_success
+
+  sleep 2
+
+  run curl -sf "$url"
+  assert_success
+  assert_matches 'entity="/DC0/vm/my-vm"'
+
+  run govc vm.destroy DC0_H0_VM0
+  assert_success
+
+  sleep 2
+
+  run curl -sf "$url"
+  assert_success
+  assert_equal 0 "$(grep -c 'entity="/DC0/vm/DC0_H0_VM0"' <<<"$output")"
+
+  kill "$pid"
+  rm -f "$log"
+}
+
 @test "metric.info" {
   vcsim_env
 
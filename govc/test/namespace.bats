@@ -113,4 +113,120 @@ load test_helper
     run govc namespace.service.info -json service2
     assert_matches DE-ACTIVATED
 
-}
\ No newline at end of file
+}
+@test "namespace.create" {
+    vcsim_env
+
+    run govc namespace.ls
+    assert_success ""
+
+    run govc namespace.create -cluster DC0_C0 -vmclass best-effort-small -storage "vSAN Default Storage Policy" test-ns
+    assert_success
+
+    run govc namespace.create -cluster DC0_C0 test-ns
+    assert_failure # already exists
+
+    run govc namespace.create -cluster DC0_C0 -vmclass enoent other-ns
+    assert_failure # invalid vm class
+
+    run govc namespace.create -cluster DC0_C0 Invalid_Name
+    assert_failure
+
+    run govc namespace.ls
+    assert_success test-ns
+
+    run govc namespace.ls -l
+    assert_success
+    assert_matches RUNNING
+
+    run govc namespace.info test-ns
+    assert_success
+    assert_matches best-effort-small
+
+    run govc namespace.info -json enoent
+    assert_failure
+}
+
+@test "namespace.update" {
+    vcsim_env
+
+    govc library.create images
+    govc namespace.create -cluster DC0_C0 test-ns
+
+    run govc namespace.update -description "team a" -library images -vmclass best-effort-medium test-ns
+    assert_success
+
+    id=$(govc library.info -json images | jq -r .[].id)
+
+    run govc namespace.info -json test-ns
+    assert_success
+    [ "$(jq -r .description <<<"$output")" = "team a" ]
+    [ "$(jq -r .vm_service_spec.content_libraries[0] <<<"$output")" = "$id" ]
+
+    run govc namespace.update -description "" test-ns
+    assert_success
+
+    run govc namespace.info -json test-ns
+    assert_success
+    [ "$(jq -r .description <<<"$output")" = "" ]
+    [ "$(jq -r .vm_service_spec.content_libraries[0] <<<"$output")" = "$id" ]
+
+    run govc namespace.rm test-ns
+    assert_success
+
+    run govc namespace.ls
+    assert_success ""
+
+    run govc namespace.rm test-ns
+    assert_failure
+}
+
+@test "namespace.vmclass" {
+    vcsim_env
+
+    run govc namespace.vmclass.ls
+    assert_success
+    assert_matches best-effort-small
+
+    run govc namespace.vmclass.create -cpus 2 -memory 2048 test-class
+    assert_success
+
+    run govc namespace.vmclass.create -cpus 2 -memory 2048 test-class
+    assert_failure
+
+    run govc namespace.vmclass.update -cpus 4 test-class
+    assert_success
+
+    run govc namespace.vmclass.info -json test-class
+    assert_success
+    [ "$(jq -r .cpu_count <<<"$output")" = "4" ]
+    [ "$(jq -r .memory_MB <<<"$output")" = "2048" ]
+
+    run govc namespace.vmclass.update -cpu-reservation 50 test-class
+    assert_success
+
+    run govc namespace.vmclass.update -cpu-reservation 0 test-class
+    assert_success
+
+    run govc namespace.vmclass.info -json test-class
+    assert_success
+    [ "$(jq -r '.cpu_reservation // 0' <<<"$output")" = "0" ]
+    [ "$(jq -r .cpu_count <<<"$output")" = "4" ]
+
+    govc namespace.create -cluster DC0_C0 -vmclass test-class test-ns
+
+    run govc namespace.vmclass.info test-class
+    assert_success
+    assert_matches test-ns
+
+    run govc namespace.vmclass.rm test-class
+    assert_failure # in use
+
+    govc namespace.rm test-ns
+
+    run govc namespace.vmclass.rm test-class
+    assert_success
+
+    run govc namespace.vmclass.info test-class
+    assert_failure
+}
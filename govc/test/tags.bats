@@ -211,3 +211,118 @@ load test_helper
   govc tags.attached.ls -r /DC1
   govc tags.attached.ls -r /DC1/host/DC1_C0
 }
+
+@test "tags.select" {
+  vcsim_env
+
+  govc tags.category.create env
+  govc tags.create -c env prod
+  govc tags.create -c env dev
+
+  govc tags.attach prod /DC0/vm/DC0_H0_VM0
+  govc tags.attach prod /DC0/vm/DC0_C0_RP0_VM1
+  govc tags.attach dev /DC0/vm/DC0_C0_RP0_VM1
+  govc tags.attach prod /DC0/host/DC0_H0/DC0_H0
+
+  run govc vm.info -tag env:prod
+  assert_success
+  assert_equal 2 "$(grep -c Name: <<<"$output")"
+
+  run govc vm.info -tag env:prod -tag dev
+  assert_success
+  assert_equal 1 "$(grep -c Name: <<<"$output")"
+  assert_matches DC0_C0_RP0_VM1
+
+  run govc vm.info -tag 'env:*'
+  assert_success
+  assert_equal 2 "$(grep -c Name: <<<"$output")"
+
+  run govc vm.info -tag prod '/DC0/vm/*VM0'
+  assert_success
+  assert_equal 1 "$(grep -c Name: <<<"$output")"
+  assert_matches DC0_H0_VM0
+
+  run govc vm.info -tag env:test
+  assert_failure
+
+  run govc vm.info -tag prod -vm.ipath /DC0/vm/DC0_H0_VM0
+  assert_failure
+
+  run govc vm.power -off -tag prod
+  assert_success
+  assert_equal 2 "$(grep -c "Powering off" <<<"$output")"
+
+  unset GOVC_HOST
+
+  run govc host.info -tag prod
+  assert_success
+  assert_equal 1 "$(grep -c Name: <<<"$output")"
+  assert_matches DC0_H0
+
+  run govc host.info -tag dev
+  assert_failure
+}
+
+@test "tags.apply" {
+  vcsim_env
+
+  govc tags.category.create env
+  govc tags.create -c env prod
+  govc tags.create -c env dev
+
+  vm0=/DC0/vm/DC0_H0_VM0
+  vm1=/DC0/vm/DC0_H0_VM1
+
+  govc tags.attach dev $vm0
+
+  run govc tags.apply -t env:prod -t team:web $vm0 $vm1
+  assert_failure # team:web does not exist
+
+  run govc tags.apply
+  assert_failure # usage
+
+  run govc tags.apply -t env-prod $vm0
+  assert_failure # invalid tag
+
+  run govc tags.apply -n -create -t env:prod -t team:web $vm0 $vm1
+  assert_success
+  assert_line "Create category team"
+  assert_line "Create tag team:web"
+  assert_matches "$vm0: +attach env:prod,team:web +detach env:dev"
+
+  run govc tags.category.info team
+  assert_failure # dry run
+
+  run govc tags.attached.ls -r $vm0
+  assert_success dev
+
+  run govc tags.apply -create -t env:prod -t team:web $vm0 $vm1
+  assert_success
+
+  run govc tags.attached.ls -r $vm0
+  assert_success
+  assert_equal 2 ${#lines[@]}
+  assert_line prod
+  assert_line web
+
+  run govc tags.attached.ls -r $vm1
+  assert_success
+  assert_equal 2 ${#lines[@]}
+
+  run govc tags.apply -t env:prod $vm0 $vm1
+  assert_success
+  [ ${#lines[@]} -eq 2 ]
+
+  run govc tags.apply -n -t env:prod $vm0 $vm1
+  assert_success ""
+
+  run govc tags.apply -json -f - <<<"{\"$vm0\": [\"env:dev\"], \"$vm1\": []}"
+  assert_success
+  assert_equal 2 "$(jq '.changes | length' <<<"$output")"
+
+  run govc tags.attached.ls -r $vm0
+  assert_success dev
+
+  run govc tags.attached.ls -r $vm1
+  assert_success ""
+}
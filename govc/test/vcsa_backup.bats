@@ -0,0 +1,54 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.backup.create" {
+  vcsim_env
+
+  run govc vcsa.backup.ls
+  assert_success ""
+
+  run govc vcsa.backup.parts
+  assert_success
+  assert_matches common
+  assert_matches seat
+
+  run govc vcsa.backup.create ftp://backup.example.com/vcsa
+  assert_success
+  id="$output"
+
+  run govc vcsa.backup.info "$id"
+  assert_success
+  assert_matches SUCCEEDED
+  assert_matches common,seat
+
+  run govc vcsa.backup.create -part common -wait=false -comment test sftp://backup.example.com/vcsa
+  assert_success
+  id="$output"
+
+  run govc vcsa.backup.cancel "$id"
+  assert_success
+
+  run govc vcsa.backup.cancel "$id"
+  assert_failure # no longer in progress
+  assert_matches NOT_ALLOWED_IN_CURRENT_STATE
+
+  state=$(govc vcsa.backup.info -json "$id" | jq -r .state)
+  assert_equal FAILED "$state"
+
+  run govc vcsa.backup.ls -l
+  assert_success
+  assert_matches SUCCEEDED
+  assert_matches FAILED
+
+  run govc vcsa.backup.create -part enoent ftp://backup.example.com/vcsa
+  assert_failure
+  assert_matches INVALID_ARGUMENT
+
+  run govc vcsa.backup.create file:///tmp/vcsa
+  assert_failure
+
+  run govc vcsa.backup.info enoent
+  assert_failure
+  assert_matches NOT_FOUND
+}
@@ -0,0 +1,23 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.health" {
+  vcsim_env
+
+  run govc vcsa.health
+  assert_success
+  assert_matches "system: *green"
+  assert_matches "swap: *green"
+
+  run govc vcsa.health storage
+  assert_success
+  assert_matches "storage: *green"
+  [ ${#lines[@]} -eq 6 ] # 5 summary lines + storage
+
+  level=$(govc vcsa.health -json mem | jq -r .health[].level)
+  assert_equal green "$level"
+
+  run govc vcsa.health enoent
+  assert_failure
+}
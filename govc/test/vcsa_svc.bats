@@ -0,0 +1,55 @@
+#!/usr/bin/env bats
+
+load test_helper
+
+@test "vcsa.svc.ls" {
+  vcsim_env
+
+  run govc vcsa.svc.ls
+  assert_success
+  assert_matches "vpxd *AUTOMATIC *STARTED *HEALTHY"
+
+  run govc vcsa.svc.ls -appliance
+  assert_success
+  assert_matches "ntpd *STARTED"
+}
+
+@test "vcsa.svc.stop" {
+  vcsim_env
+
+  run govc vcsa.svc.stop wcp
+  assert_success
+
+  state=$(govc vcsa.svc.ls -json | jq -r .wcp.state)
+  assert_equal STOPPED "$state"
+
+  run govc vcsa.svc.update -startup-type DISABLED wcp
+  assert_success
+
+  run govc vcsa.svc.start wcp
+  assert_failure # disabled
+  assert_matches NOT_ALLOWED_IN_CURRENT_STATE
+
+  run govc vcsa.svc.update -startup-type MANUAL wcp
+  assert_success
+
+  run govc vcsa.svc.restart wcp
+  assert_success
+
+  state=$(govc vcsa.svc.ls -json | jq -r .wcp.state)
+  assert_equal STARTED "$state"
+
+  run govc vcsa.svc.update -startup-type ENOENT wcp
+  assert_failure
+  assert_matches INVALID_ARGUMENT
+
+  run govc vcsa.svc.stop enoent
+  assert_failure
+  assert_matches NOT_FOUND
+
+  run govc vcsa.svc.stop -appliance sshd
+  assert_success
+
+  state=$(govc vcsa.svc.ls -appliance -json | jq -r .sshd.state)
+  assert_equal STOPPED "$state"
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+)
+
+type create struct {
+	*flags.ClientFlag
+
+	req   backup.Request
+	parts flags.StringList
+	wait  bool
+}
+
+func init() {
+	cli.Register("vcsa.backup.create", &create{})
+}
+
+func (cmd *create) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.req.LocationType, "type", "", "Location type, defaults to the LOCATION URL scheme (FTP|FTPS|HTTP|HTTPS|SCP|SFTP|NFS|SMB)")
+	f.StringVar(&cmd.req.LocationUser, "user", "", "Location username")
+	f.StringVar(&cmd.req.LocationPassword, "password", "", "Location password")
+	f.StringVar(&cmd.req.BackupPassword, "backup-password", "", "Password used to encrypt the backup")
+	f.StringVar(&cmd.req.Comment, "comment", "", "Backup comment")
+	f.Var(&cmd.parts, "part", "Part to include in the backup, see vcsa.backup.parts (default: parts selected by default)")
+	f.BoolVar(&cmd.wait, "wait", true, "Wait for the backup job to complete")
+}
+
+func (cmd *create) Usage() string {
+	return "LOCATION"
+}
+
+func (cmd *create) Description() string {
+	return `Create a VC Appliance file-based backup job.
+
+The backup is written to LOCATION, a URL such as sftp://backup.example.com/vcsa/2022-01-01.
+The ID of the backup job is written to stdout.
+
+Examples:
+  govc vcsa.backup.create -user backup -password secret sftp://backup.example.com/vcsa/$(date +%F)
+  govc vcsa.backup.create -part common -part seat -wait=false ftp://backup.example.com/vcsa`
+}
+
+func (cmd *create) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	cmd.req.Location = f.Arg(0)
+	cmd.req.Parts = cmd.parts
+
+	if cmd.req.LocationType == "" {
+		u, err := url.Parse(cmd.req.Location)
+		if err != nil {
+			return err
+		}
+		cmd.req.LocationType = strings.ToUpper(u.Scheme)
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := backup.NewManager(c)
+
+	job, err := m.Create(ctx, cmd.req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(job.ID)
+
+	if !cmd.wait {
+		return nil
+	}
+
+	for job.State == backup.StateInProgress {
+		if job, err = m.Get(ctx, job.ID); err != nil {
+			return err
+		}
+		if job.State == backup.StateInProgress {
+			time.Sleep(time.Second)
+		}
+	}
+
+	if job.State != backup.StateSucceeded {
+		msg := job.State
+		if n := len(job.Messages); n != 0 {
+			msg = job.Messages[n-1].DefaultMessage
+		}
+		return fmt.Errorf("backup job %s: %s", job.ID, msg)
+	}
+
+	return nil
+}
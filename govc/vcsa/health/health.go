@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/appliance/system"
+)
+
+type info struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+}
+
+func init() {
+	cli.Register("vcsa.health", &info{})
+}
+
+func (cmd *info) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+}
+
+func (cmd *info) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *info) Usage() string {
+	return "[COMPONENT]..."
+}
+
+func (cmd *info) Description() string {
+	return `Display VC Appliance version, uptime and health.
+
+The health level of each COMPONENT is one of green, yellow, orange, red, gray or unknown.
+Components: system, applmgmt, database, database-storage, load, mem, software-packages, storage, swap.
+If no COMPONENT is specified, the health of all components is displayed.
+The command fails if the overall system health is not green.
+
+Examples:
+  govc vcsa.health
+  govc vcsa.health storage swap
+  govc vcsa.health -json | jq -r .health[].level`
+}
+
+type healthResult struct {
+	Version   system.Version  `json:"version"`
+	Uptime    float64         `json:"uptime"`
+	LastCheck string          `json:"last_check"`
+	Health    []health.Status `json:"health"`
+}
+
+func (r *healthResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Product:\t%s\n", r.Version.Product)
+	fmt.Fprintf(tw, "Version:\t%s\n", r.Version.Version)
+	fmt.Fprintf(tw, "Build:\t%s\n", r.Version.Build)
+	fmt.Fprintf(tw, "Uptime:\t%s\n", time.Duration(r.Uptime)*time.Second)
+	fmt.Fprintf(tw, "Last check:\t%s\n", r.LastCheck)
+	for _, s := range r.Health {
+		fmt.Fprintf(tw, "%s:\t%s\n", s.Component, s.Level)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *info) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := health.NewManager(c)
+	s := system.NewManager(c)
+
+	var res healthResult
+
+	if res.Version, err = s.Version(ctx); err != nil {
+		return err
+	}
+	if res.Uptime, err = s.Uptime(ctx); err != nil {
+		return err
+	}
+	if res.LastCheck, err = m.LastCheck(ctx); err != nil {
+		return err
+	}
+
+	components := f.Args()
+	if len(components) == 0 {
+		components = health.Components
+	}
+
+	for _, component := range components {
+		level, err := m.Get(ctx, component)
+		if err != nil {
+			return fmt.Errorf("%s: %s", component, err)
+		}
+		res.Health = append(res.Health, health.Status{Component: component, Level: level})
+	}
+
+	if err = cmd.WriteResult(&res); err != nil {
+		return err
+	}
+
+	system, err := m.Get(ctx, health.System)
+	if err != nil {
+		return err
+	}
+	if system != health.Green {
+		return fmt.Errorf("system health is %s", system)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+)
+
+type control struct {
+	*flags.ClientFlag
+
+	action    string
+	appliance bool
+}
+
+func init() {
+	for _, action := range []string{services.Start, services.Stop, services.Restart} {
+		cli.Register("vcsa.svc."+action, &control{action: action})
+	}
+}
+
+func (cmd *control) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.appliance, "appliance", false, "Use appliance (systemd) services instead of vMon services")
+}
+
+func (cmd *control) Usage() string {
+	return "NAME..."
+}
+
+func (cmd *control) Description() string {
+	return fmt.Sprintf(`%s VC Appliance services.
+
+Examples:
+  govc vcsa.svc.%s vsphere-ui
+  govc vcsa.svc.%s -appliance ntpd`, map[string]string{
+		services.Start:   "Start",
+		services.Stop:    "Stop",
+		services.Restart: "Restart",
+	}[cmd.action], cmd.action, cmd.action)
+}
+
+func (cmd *control) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := services.NewManager(c)
+
+	actions := map[string]func(context.Context, string) error{
+		services.Start:   m.StartVmon,
+		services.Stop:    m.StopVmon,
+		services.Restart: m.RestartVmon,
+	}
+	if cmd.appliance {
+		actions = map[string]func(context.Context, string) error{
+			services.Start:   m.Start,
+			services.Stop:    m.Stop,
+			services.Restart: m.Restart,
+		}
+	}
+
+	for _, name := range f.Args() {
+		if err := actions[cmd.action](ctx, name); err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+	}
+
+	return nil
+}
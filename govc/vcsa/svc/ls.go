@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+)
+
+type ls struct {
+	*flags.ClientFlag
+	*flags.OutputFlag
+
+	appliance bool
+}
+
+func init() {
+	cli.Register("vcsa.svc.ls", &ls{})
+}
+
+func (cmd *ls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	cmd.OutputFlag, ctx = flags.NewOutputFlag(ctx)
+	cmd.OutputFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.appliance, "appliance", false, "List appliance (systemd) services instead of vMon services")
+}
+
+func (cmd *ls) Process(ctx context.Context) error {
+	if err := cmd.ClientFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.OutputFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *ls) Description() string {
+	return `List VC Appliance services.
+
+By default, the services managed by the vMon service lifecycle manager are listed,
+with their startup type, state and health.
+
+Examples:
+  govc vcsa.svc.ls
+  govc vcsa.svc.ls -appliance
+  govc vcsa.svc.ls -json | jq -r 'to_entries[] | select(.value.state != "STARTED") | .key'`
+}
+
+type vmonResult map[string]services.VmonInfo
+
+func (r vmonResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := r[name]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", name, s.StartupType, s.State, s.Health)
+	}
+
+	return tw.Flush()
+}
+
+type applianceResult map[string]services.Info
+
+func (r applianceResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := r[name]
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, s.State, s.Description)
+	}
+
+	return tw.Flush()
+}
+
+func (cmd *ls) Run(ctx context.Context, f *flag.FlagSet) error {
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := services.NewManager(c)
+
+	if cmd.appliance {
+		res, err := m.List(ctx)
+		if err != nil {
+			return err
+		}
+		return cmd.WriteResult(applianceResult(res))
+	}
+
+	res, err := m.ListVmon(ctx)
+	if err != nil {
+		return err
+	}
+	return cmd.WriteResult(vmonResult(res))
+}
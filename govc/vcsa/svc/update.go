@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package svc
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/govc/flags"
+	"github.com/vmware/govmomi/vapi/appliance/services"
+)
+
+type update struct {
+	*flags.ClientFlag
+
+	spec services.VmonUpdateSpec
+}
+
+func init() {
+	cli.Register("vcsa.svc.update", &update{})
+}
+
+func (cmd *update) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.ClientFlag, ctx = flags.NewClientFlag(ctx)
+	cmd.ClientFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.spec.StartupType, "startup-type", "", "Startup type (AUTOMATIC|MANUAL|DISABLED)")
+}
+
+func (cmd *update) Usage() string {
+	return "NAME..."
+}
+
+func (cmd *update) Description() string {
+	return `Update VC Appliance vMon services.
+
+Examples:
+  govc vcsa.svc.update -startup-type MANUAL wcp`
+}
+
+func (cmd *update) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() == 0 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.RestClient()
+	if err != nil {
+		return err
+	}
+
+	m := services.NewManager(c)
+
+	for _, name := range f.Args() {
+		if err := m.UpdateVmon(ctx, name, cmd.spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
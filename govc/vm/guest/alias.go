@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// AliasFlag is used by the guest.alias commands to specify the guest user and certificate.
+type AliasFlag struct {
+	user   string
+	signer string
+	any    bool
+}
+
+func newAliasFlag(ctx context.Context) (*AliasFlag, context.Context) {
+	return &AliasFlag{}, ctx
+}
+
+func (flag *AliasFlag) Register(ctx context.Context, f *flag.FlagSet) {
+	f.StringVar(&flag.user, "user", "", "Guest user name of the alias")
+	f.StringVar(&flag.signer, "signer", "", "Path to the PEM or base64 encoded certificate of the SAML token signer")
+	f.BoolVar(&flag.any, "any", false, "Alias applies to any subject with a token signed by the certificate")
+}
+
+func (flag *AliasFlag) Process(ctx context.Context) error {
+	return nil
+}
+
+// Certificate returns the base64 encoded DER certificate read from the -signer file.
+func (flag *AliasFlag) Certificate() (string, error) {
+	if flag.signer == "" {
+		return "", errors.New("-signer is required")
+	}
+
+	b, err := os.ReadFile(flag.signer)
+	if err != nil {
+		return "", err
+	}
+
+	if block, _ := pem.Decode(b); block != nil {
+		return base64.StdEncoding.EncodeToString(block.Bytes), nil
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Subject returns the alias subject for the given name, or the any subject if the -any flag is set.
+func (flag *AliasFlag) Subject(name string) (types.BaseGuestAuthSubject, error) {
+	if flag.any {
+		if name != "" {
+			return nil, errors.New("SUBJECT cannot be specified with -any")
+		}
+		return &types.GuestAuthAnySubject{}, nil
+	}
+	if name == "" {
+		return nil, nil
+	}
+	return &types.GuestAuthNamedSubject{Name: name}, nil
+}
+
+func aliasSubjectName(s types.BaseGuestAuthSubject) string {
+	if named, ok := s.(*types.GuestAuthNamedSubject); ok {
+		return named.Name
+	}
+	return "*"
+}
+
+func aliasThumbprint(cert string) string {
+	der, err := base64.StdEncoding.DecodeString(cert)
+	if err != nil {
+		return "-"
+	}
+	return soap.ThumbprintSHA1(&x509.Certificate{Raw: der})
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type aliasadd struct {
+	*GuestFlag
+	*AliasFlag
+
+	mapCert bool
+	comment string
+}
+
+func init() {
+	cli.Register("guest.alias.add", &aliasadd{})
+}
+
+func (cmd *aliasadd) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.AliasFlag, ctx = newAliasFlag(ctx)
+	cmd.AliasFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.mapCert, "map", false, "Add the certificate and subject to the global mapping file")
+	f.StringVar(&cmd.comment, "comment", "", "Alias comment")
+}
+
+func (cmd *aliasadd) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.AliasFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *aliasadd) Usage() string {
+	return "[SUBJECT]"
+}
+
+func (cmd *aliasadd) Description() string {
+	return `Add guest alias for SAML token SUBJECT, signed by the given certificate, to USER in VM.
+
+Either SUBJECT or the -any flag must be specified.
+
+Examples:
+  govc guest.alias.add -vm $name -user root -signer sts.pem -map administrator@vsphere.local
+  govc guest.alias.add -vm $name -user root -signer sts.pem -any`
+}
+
+func (cmd *aliasadd) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() > 1 || cmd.user == "" {
+		return flag.ErrHelp
+	}
+
+	subject, err := cmd.Subject(f.Arg(0))
+	if err != nil {
+		return err
+	}
+	if subject == nil {
+		return flag.ErrHelp
+	}
+
+	cert, err := cmd.Certificate()
+	if err != nil {
+		return err
+	}
+
+	m, err := cmd.AliasManager()
+	if err != nil {
+		return err
+	}
+
+	info := types.GuestAuthAliasInfo{
+		Subject: subject,
+		Comment: cmd.comment,
+	}
+
+	return m.AddAlias(ctx, cmd.Auth(), cmd.user, cmd.mapCert, cert, info)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+)
+
+type aliasls struct {
+	*GuestFlag
+
+	user   string
+	mapped bool
+}
+
+func init() {
+	cli.Register("guest.alias.ls", &aliasls{})
+}
+
+func (cmd *aliasls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.user, "user", "", "Guest user name")
+	f.BoolVar(&cmd.mapped, "mapped", false, "List the global certificate mappings")
+}
+
+func (cmd *aliasls) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *aliasls) Description() string {
+	return `List guest aliases of USER in VM.
+
+The certificate SHA1 thumbprint is displayed for each alias.
+With the -mapped flag, the aliases in the global mapping file are listed for all users.
+
+Examples:
+  govc guest.alias.ls -vm $name -user root
+  govc guest.alias.ls -vm $name -mapped`
+}
+
+func (cmd *aliasls) Run(ctx context.Context, f *flag.FlagSet) error {
+	if cmd.user == "" && !cmd.mapped {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.AliasManager()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	if cmd.mapped {
+		mapped, err := m.ListMappedAliases(ctx, cmd.Auth())
+		if err != nil {
+			return err
+		}
+
+		for _, a := range mapped {
+			for _, s := range a.Subjects {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", a.Username, aliasSubjectName(s), aliasThumbprint(a.Base64Cert))
+			}
+		}
+
+		return tw.Flush()
+	}
+
+	aliases, err := m.ListAliases(ctx, cmd.Auth(), cmd.user)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range aliases {
+		for _, info := range a.Aliases {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", aliasSubjectName(info.Subject), aliasThumbprint(a.Base64Cert), info.Comment)
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+)
+
+type aliasrm struct {
+	*GuestFlag
+	*AliasFlag
+}
+
+func init() {
+	cli.Register("guest.alias.rm", &aliasrm{})
+}
+
+func (cmd *aliasrm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.AliasFlag, ctx = newAliasFlag(ctx)
+	cmd.AliasFlag.Register(ctx, f)
+}
+
+func (cmd *aliasrm) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.AliasFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *aliasrm) Usage() string {
+	return "[SUBJECT]"
+}
+
+func (cmd *aliasrm) Description() string {
+	return `Remove guest alias for SAML token SUBJECT and certificate from USER in VM.
+
+If neither SUBJECT nor the -any flag is specified, all aliases for the certificate are removed.
+
+Examples:
+  govc guest.alias.rm -vm $name -user root -signer sts.pem administrator@vsphere.local
+  govc guest.alias.rm -vm $name -user root -signer sts.pem -any
+  govc guest.alias.rm -vm $name -user root -signer sts.pem`
+}
+
+func (cmd *aliasrm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() > 1 || cmd.user == "" {
+		return flag.ErrHelp
+	}
+
+	subject, err := cmd.Subject(f.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cmd.Certificate()
+	if err != nil {
+		return err
+	}
+
+	m, err := cmd.AliasManager()
+	if err != nil {
+		return err
+	}
+
+	if subject == nil {
+		return m.RemoveAliasByCert(ctx, cmd.Auth(), cmd.user, cert)
+	}
+
+	return m.RemoveAlias(ctx, cmd.Auth(), cmd.user, cert, subject)
+}
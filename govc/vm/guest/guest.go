@@ -82,6 +82,16 @@ func (flag *GuestFlag) Toolbox(ctx context.Context) (*toolbox.Client, error) {
 	return toolbox.NewClient(ctx, c, vm, flag.Auth())
 }
 
+// Session returns a guest.Session for running operations against multiple VMs with the flag's credentials
+func (flag *GuestFlag) Session() (*guest.Session, error) {
+	c, err := flag.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	return guest.NewSession(c, flag.Auth()), nil
+}
+
 func (flag *GuestFlag) FileManager() (*guest.FileManager, error) {
 	ctx := context.TODO()
 	c, err := flag.Client()
@@ -114,6 +124,38 @@ func (flag *GuestFlag) ProcessManager() (*guest.ProcessManager, error) {
 	return o.ProcessManager(ctx)
 }
 
+func (flag *GuestFlag) WindowsRegistryManager() (*guest.WindowsRegistryManager, error) {
+	ctx := context.TODO()
+	c, err := flag.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := flag.VirtualMachine()
+	if err != nil {
+		return nil, err
+	}
+
+	o := guest.NewOperationsManager(c, vm.Reference())
+	return o.WindowsRegistryManager(ctx)
+}
+
+func (flag *GuestFlag) AliasManager() (*guest.AliasManager, error) {
+	ctx := context.TODO()
+	c, err := flag.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := flag.VirtualMachine()
+	if err != nil {
+		return nil, err
+	}
+
+	o := guest.NewOperationsManager(c, vm.Reference())
+	return o.AliasManager(ctx)
+}
+
 func (flag *GuestFlag) ParseURL(urlStr string) (*url.URL, error) {
 	c, err := flag.Client()
 	if err != nil {
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// RegKeyFlag is used by the guest.reg commands to specify the registry view of KEY.
+type RegKeyFlag struct {
+	wow string
+}
+
+var regWowBitness = map[string]types.GuestRegKeyWowSpec{
+	"native": types.GuestRegKeyWowSpecWOWNative,
+	"32":     types.GuestRegKeyWowSpecWOW32,
+	"64":     types.GuestRegKeyWowSpecWOW64,
+}
+
+func newRegKeyFlag(ctx context.Context) (*RegKeyFlag, context.Context) {
+	return &RegKeyFlag{}, ctx
+}
+
+func (flag *RegKeyFlag) Register(ctx context.Context, f *flag.FlagSet) {
+	f.StringVar(&flag.wow, "wow", "native", "Registry view (native|32|64)")
+}
+
+func (flag *RegKeyFlag) Process(ctx context.Context) error {
+	if _, ok := regWowBitness[flag.wow]; !ok {
+		return fmt.Errorf("invalid registry view: %q", flag.wow)
+	}
+	return nil
+}
+
+// Key returns the registry key name spec for the given path, such as HKEY_LOCAL_MACHINE\SOFTWARE
+func (flag *RegKeyFlag) Key(path string) types.GuestRegKeyNameSpec {
+	return types.GuestRegKeyNameSpec{
+		RegistryPath: path,
+		WowBitness:   string(regWowBitness[flag.wow]),
+	}
+}
+
+// regValueTypes maps guest.reg.set -type values to the Windows registry value type names.
+var regValueTypes = map[string]string{
+	"string": "REG_SZ",
+	"expand": "REG_EXPAND_SZ",
+	"multi":  "REG_MULTI_SZ",
+	"dword":  "REG_DWORD",
+	"qword":  "REG_QWORD",
+	"binary": "REG_BINARY",
+}
+
+// regValueData converts args to value data of the given type, binary values are hex encoded.
+func regValueData(kind string, args []string) (types.BaseGuestRegValueDataSpec, error) {
+	if kind != "multi" && len(args) != 1 {
+		return nil, flag.ErrHelp
+	}
+
+	switch kind {
+	case "string":
+		return &types.GuestRegValueStringSpec{Value: args[0]}, nil
+	case "expand":
+		return &types.GuestRegValueExpandStringSpec{Value: args[0]}, nil
+	case "multi":
+		return &types.GuestRegValueMultiStringSpec{Value: args}, nil
+	case "dword":
+		n, err := strconv.ParseUint(args[0], 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		return &types.GuestRegValueDwordSpec{Value: int32(uint32(n))}, nil
+	case "qword":
+		n, err := strconv.ParseUint(args[0], 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &types.GuestRegValueQwordSpec{Value: int64(n)}, nil
+	case "binary":
+		b, err := hex.DecodeString(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &types.GuestRegValueBinarySpec{Value: b}, nil
+	}
+
+	return nil, fmt.Errorf("invalid value type: %q", kind)
+}
+
+// regValueString returns the registry type name and formatted data of a value.
+func regValueString(data types.BaseGuestRegValueDataSpec) (string, string) {
+	switch v := data.(type) {
+	case *types.GuestRegValueStringSpec:
+		return regValueTypes["string"], v.Value
+	case *types.GuestRegValueExpandStringSpec:
+		return regValueTypes["expand"], v.Value
+	case *types.GuestRegValueMultiStringSpec:
+		return regValueTypes["multi"], strings.Join(v.Value, `\0`)
+	case *types.GuestRegValueDwordSpec:
+		return regValueTypes["dword"], fmt.Sprintf("0x%x", uint32(v.Value))
+	case *types.GuestRegValueQwordSpec:
+		return regValueTypes["qword"], fmt.Sprintf("0x%x", uint64(v.Value))
+	case *types.GuestRegValueBinarySpec:
+		return regValueTypes["binary"], hex.EncodeToString(v.Value)
+	}
+	return "REG_NONE", ""
+}
@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+)
+
+type regget struct {
+	*GuestFlag
+	*RegKeyFlag
+
+	expand bool
+	match  string
+}
+
+func init() {
+	cli.Register("guest.reg.get", &regget{})
+}
+
+func (cmd *regget) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.RegKeyFlag, ctx = newRegKeyFlag(ctx)
+	cmd.RegKeyFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.expand, "x", false, "Expand environment variables in expand type values")
+	f.StringVar(&cmd.match, "match", "", "Only list values with names matching regular expression")
+}
+
+func (cmd *regget) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.RegKeyFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *regget) Usage() string {
+	return "KEY [NAME]"
+}
+
+func (cmd *regget) Description() string {
+	return `List values of Windows registry KEY in VM.
+
+If NAME is given, only the data of value NAME is printed.
+
+Examples:
+  govc guest.reg.get -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp'
+  govc guest.reg.get -vm $name -x 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp' InstallDir`
+}
+
+func (cmd *regget) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 1 || f.NArg() > 2 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.WindowsRegistryManager()
+	if err != nil {
+		return err
+	}
+
+	name := f.Arg(1)
+	match := cmd.match
+	if name != "" {
+		match = "^" + regexp.QuoteMeta(name) + "$"
+	}
+
+	values, err := m.ListValues(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.expand, match)
+	if err != nil {
+		return err
+	}
+
+	if name != "" {
+		if len(values) == 0 {
+			return fmt.Errorf("value %q not found", name)
+		}
+		_, data := regValueString(values[0].Data)
+		fmt.Println(data)
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 0, 2, ' ', 0)
+
+	for _, value := range values {
+		kind, data := regValueString(value.Data)
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", value.Name.Name, kind, data)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vmware/govmomi/govc/cli"
+)
+
+type regls struct {
+	*GuestFlag
+	*RegKeyFlag
+
+	recursive bool
+	match     string
+}
+
+func init() {
+	cli.Register("guest.reg.ls", &regls{})
+}
+
+func (cmd *regls) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.RegKeyFlag, ctx = newRegKeyFlag(ctx)
+	cmd.RegKeyFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.recursive, "r", false, "List subkeys recursively")
+	f.StringVar(&cmd.match, "match", "", "Only list subkeys with names matching regular expression")
+}
+
+func (cmd *regls) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.RegKeyFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *regls) Usage() string {
+	return "KEY"
+}
+
+func (cmd *regls) Description() string {
+	return `List subkeys of Windows registry KEY in VM.
+
+Examples:
+  govc guest.reg.ls -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE'
+  govc guest.reg.ls -vm $name -r -match '^VMware' 'HKEY_LOCAL_MACHINE\SOFTWARE'`
+}
+
+func (cmd *regls) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.WindowsRegistryManager()
+	if err != nil {
+		return err
+	}
+
+	keys, err := m.ListKeys(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.recursive, cmd.match)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		fmt.Println(key.Key.KeyName.RegistryPath)
+	}
+
+	return nil
+}
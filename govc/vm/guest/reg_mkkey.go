@@ -0,0 +1,84 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+)
+
+type regmkkey struct {
+	*GuestFlag
+	*RegKeyFlag
+
+	volatile bool
+	class    string
+}
+
+func init() {
+	cli.Register("guest.reg.mkkey", &regmkkey{})
+}
+
+func (cmd *regmkkey) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.RegKeyFlag, ctx = newRegKeyFlag(ctx)
+	cmd.RegKeyFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.volatile, "volatile", false, "Create a volatile key, which is not preserved on reboot")
+	f.StringVar(&cmd.class, "class", "", "User defined class type of the key")
+}
+
+func (cmd *regmkkey) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.RegKeyFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *regmkkey) Usage() string {
+	return "KEY"
+}
+
+func (cmd *regmkkey) Description() string {
+	return `Create Windows registry KEY in VM.
+
+The parent of KEY must exist.
+
+Examples:
+  govc guest.reg.mkkey -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp'
+  govc guest.reg.mkkey -vm $name -volatile -wow 32 'HKEY_CURRENT_USER\Volatile Environment\MyApp'`
+}
+
+func (cmd *regmkkey) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.WindowsRegistryManager()
+	if err != nil {
+		return err
+	}
+
+	return m.CreateKey(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.volatile, cmd.class)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type regrm struct {
+	*GuestFlag
+	*RegKeyFlag
+}
+
+func init() {
+	cli.Register("guest.reg.rm", &regrm{})
+}
+
+func (cmd *regrm) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.RegKeyFlag, ctx = newRegKeyFlag(ctx)
+	cmd.RegKeyFlag.Register(ctx, f)
+}
+
+func (cmd *regrm) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.RegKeyFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *regrm) Usage() string {
+	return "KEY NAME"
+}
+
+func (cmd *regrm) Description() string {
+	return `Delete Windows registry value NAME of KEY in VM.
+
+Examples:
+  govc guest.reg.rm -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp' InstallDir`
+}
+
+func (cmd *regrm) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.WindowsRegistryManager()
+	if err != nil {
+		return err
+	}
+
+	name := types.GuestRegValueNameSpec{
+		KeyName: cmd.Key(f.Arg(0)),
+		Name:    f.Arg(1),
+	}
+
+	return m.DeleteValue(ctx, cmd.Auth(), name)
+}
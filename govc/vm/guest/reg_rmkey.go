@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+)
+
+type regrmkey struct {
+	*GuestFlag
+	*RegKeyFlag
+
+	recursive bool
+}
+
+func init() {
+	cli.Register("guest.reg.rmkey", &regrmkey{})
+}
+
+func (cmd *regrmkey) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.RegKeyFlag, ctx = newRegKeyFlag(ctx)
+	cmd.RegKeyFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.recursive, "r", false, "Delete subkeys recursively")
+}
+
+func (cmd *regrmkey) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.RegKeyFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *regrmkey) Usage() string {
+	return "KEY"
+}
+
+func (cmd *regrmkey) Description() string {
+	return `Delete Windows registry KEY in VM.
+
+Examples:
+  govc guest.reg.rmkey -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp\Cache'
+  govc guest.reg.rmkey -vm $name -r 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp'`
+}
+
+func (cmd *regrmkey) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 1 {
+		return flag.ErrHelp
+	}
+
+	m, err := cmd.WindowsRegistryManager()
+	if err != nil {
+		return err
+	}
+
+	return m.DeleteKey(ctx, cmd.Auth(), cmd.Key(f.Arg(0)), cmd.recursive)
+}
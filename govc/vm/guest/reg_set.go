@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type regset struct {
+	*GuestFlag
+	*RegKeyFlag
+
+	kind string
+}
+
+func init() {
+	cli.Register("guest.reg.set", &regset{})
+}
+
+func (cmd *regset) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	cmd.RegKeyFlag, ctx = newRegKeyFlag(ctx)
+	cmd.RegKeyFlag.Register(ctx, f)
+
+	f.StringVar(&cmd.kind, "type", "string", "Value type (string|expand|multi|dword|qword|binary)")
+}
+
+func (cmd *regset) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	if err := cmd.RegKeyFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *regset) Usage() string {
+	return "KEY NAME VALUE..."
+}
+
+func (cmd *regset) Description() string {
+	return `Set Windows registry value NAME of KEY in VM.
+
+Multiple VALUE arguments can only be given for the 'multi' type.
+Numbers can be specified in decimal or with a 0x prefix for hex, binary values are hex encoded.
+
+Examples:
+  govc guest.reg.set -vm $name 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp' InstallDir 'C:\MyApp'
+  govc guest.reg.set -vm $name -type dword 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp' Enabled 1
+  govc guest.reg.set -vm $name -type multi 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp' Servers a.example.com b.example.com
+  govc guest.reg.set -vm $name -type binary 'HKEY_LOCAL_MACHINE\SOFTWARE\MyApp' Key deadbeef`
+}
+
+func (cmd *regset) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() < 3 {
+		return flag.ErrHelp
+	}
+
+	data, err := regValueData(cmd.kind, f.Args()[2:])
+	if err != nil {
+		return err
+	}
+
+	m, err := cmd.WindowsRegistryManager()
+	if err != nil {
+		return err
+	}
+
+	value := types.GuestRegValueSpec{
+		Name: types.GuestRegValueNameSpec{
+			KeyName: cmd.Key(f.Arg(0)),
+			Name:    f.Arg(1),
+		},
+		Data: data,
+	}
+
+	return m.SetValue(ctx, cmd.Auth(), value)
+}
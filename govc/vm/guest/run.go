@@ -20,18 +20,30 @@ import (
 	"bytes"
 	"context"
 	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 type run struct {
 	*GuestFlag
 
-	data string
-	dir  string
-	vars env
+	data     string
+	dir      string
+	vars     env
+	stream   bool
+	timeout  time.Duration
+	parallel int
 }
 
 func init() {
@@ -45,6 +57,9 @@ func (cmd *run) Register(ctx context.Context, f *flag.FlagSet) {
 	f.StringVar(&cmd.data, "d", "", "Input data string. A value of '-' reads from OS stdin")
 	f.StringVar(&cmd.dir, "C", "", "The absolute path of the working directory for the program to start")
 	f.Var(&cmd.vars, "e", "Set environment variables")
+	f.BoolVar(&cmd.stream, "stream", false, "Display output as it is produced, rather than once the program exits")
+	f.DurationVar(&cmd.timeout, "timeout", 0, "Terminate the program if it does not exit within the given duration")
+	f.IntVar(&cmd.parallel, "parallel", guest.DefaultSessionLimit, "Maximum number of VMs to run the program in concurrently")
 }
 
 func (cmd *run) Usage() string {
@@ -58,6 +73,15 @@ The guest.run command starts a program in the VM with i/o redirected, waits for
 propagates the exit code to the govc process exit code.  Note that stdout and stderr are redirected by default,
 stdin is only redirected when the '-d' flag is specified.
 
+With the '-stream' flag, output is polled and displayed while the program is running.
+Stdin given with the '-d' flag is also streamed, transferred to the VM as it is read and piped to the program by a
+bash loop in the VM.  Stdin is not streamed to Windows VMs, where it is read until EOF and copied to the VM before the
+program starts.
+The program is terminated if govc is interrupted or the '-timeout' duration expires.
+
+When the '-vm' flag matches multiple VMs, the program is run in up to '-parallel' VMs at a time,
+each line of output is prefixed with the VM name and the exit code is that of the first VM that failed.
+
 Note that vmware-tools requires program PATH to be absolute.
 If PATH is not absolute and vm guest family is Windows,
 guest.run changes the command to: 'c:\\Windows\\System32\\cmd.exe /c "PATH [ARG]..."'
@@ -71,7 +95,10 @@ Examples:
   govc guest.run -vm $name curl -s :invalid: || echo $? # exit code 6
   govc guest.run -vm $name -e FOO=bar -e BIZ=baz -C /tmp env
   govc guest.run -vm $name -l root:mypassword ntpdate -u pool.ntp.org
-  govc guest.run -vm $name powershell C:\\network_refresh.ps1`
+  govc guest.run -vm $name powershell C:\\network_refresh.ps1
+  govc guest.run -vm $name -stream -timeout 10m /usr/bin/apt-get -y upgrade
+  tail -f /var/log/app.log | govc guest.run -vm $name -stream -d - grep -i error
+  govc guest.run -vm '/DC0/vm/web-*' -parallel 4 uptime`
 }
 
 func (cmd *run) Run(ctx context.Context, f *flag.FlagSet) error {
@@ -80,7 +107,7 @@ func (cmd *run) Run(ctx context.Context, f *flag.FlagSet) error {
 	}
 	name := f.Arg(0)
 
-	c, err := cmd.Toolbox(ctx)
+	vms, err := cmd.VirtualMachineList()
 	if err != nil {
 		return err
 	}
@@ -102,5 +129,149 @@ func (cmd *run) Run(ctx context.Context, f *flag.FlagSet) error {
 		ecmd.Stdin = bytes.NewBuffer([]byte(cmd.data))
 	}
 
-	return c.Run(ctx, ecmd)
+	if len(vms) > 1 {
+		return cmd.runAll(ctx, vms, ecmd)
+	}
+
+	c, err := cmd.Toolbox(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.Stream = cmd.stream
+
+	return cmd.WithCancel(ctx, func(ctx context.Context) error {
+		if cmd.timeout != 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cmd.timeout)
+			defer cancel()
+		}
+
+		return c.Run(ctx, ecmd)
+	})
+}
+
+// runAll runs the command in each of the given VMs, sharing a guest.Session between them.
+func (cmd *run) runAll(ctx context.Context, vms []*object.VirtualMachine, ecmd *exec.Cmd) error {
+	var stdin []byte
+	if ecmd.Stdin != nil {
+		var err error
+		stdin, err = ioutil.ReadAll(ecmd.Stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	s, err := cmd.Session()
+	if err != nil {
+		return err
+	}
+	s.Limit = cmd.parallel
+
+	names := make(map[types.ManagedObjectReference]string, len(vms))
+	refs := make([]mo.Reference, len(vms))
+	for i, vm := range vms {
+		names[vm.Reference()] = vm.Name()
+		refs[i] = vm
+	}
+
+	var res []guest.SessionResult
+
+	err = cmd.WithCancel(ctx, func(ctx context.Context) error {
+		if cmd.timeout != 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cmd.timeout)
+			defer cancel()
+		}
+
+		res = s.Run(ctx, refs, func(ctx context.Context, vm types.ManagedObjectReference, auth types.BaseGuestAuthentication) error {
+			c, err := toolbox.NewSessionClient(ctx, s, vm)
+			if err != nil {
+				return err
+			}
+			c.Authentication = auth
+			c.Stream = cmd.stream
+
+			stdout := &prefixWriter{w: ecmd.Stdout, prefix: names[vm] + ": "}
+			stderr := &prefixWriter{w: ecmd.Stderr, prefix: names[vm] + ": "}
+			defer stdout.Flush()
+			defer stderr.Flush()
+
+			vcmd := &exec.Cmd{
+				Path:   ecmd.Path,
+				Args:   ecmd.Args,
+				Env:    ecmd.Env,
+				Dir:    ecmd.Dir,
+				Stdout: stdout,
+				Stderr: stderr,
+			}
+			if stdin != nil {
+				vcmd.Stdin = bytes.NewReader(stdin)
+			}
+
+			return c.Run(ctx, vcmd)
+		})
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var failed []guest.SessionResult
+	for _, r := range res {
+		if r.Err == nil {
+			continue
+		}
+		failed = append(failed, r)
+		if _, ok := r.Err.(interface{ ExitCode() int }); !ok {
+			fmt.Fprintf(ecmd.Stderr, "%s: %s\n", names[r.VM], r.Err)
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	if _, ok := failed[0].Err.(interface{ ExitCode() int }); ok {
+		return failed[0].Err
+	}
+
+	return fmt.Errorf("failed in %d of %d VMs", len(failed), len(vms))
+}
+
+// prefixWriter writes each line of output with the given prefix.
+// Each line is written with a single call to w, such that concurrent writers to an os.File do not interleave.
+type prefixWriter struct {
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+
+	for {
+		i := bytes.IndexByte(p.buf, '\n')
+		if i < 0 {
+			return len(b), nil
+		}
+		if err := p.write(p.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[i+1:]
+	}
+}
+
+// Flush writes any remaining partial line
+func (p *prefixWriter) Flush() {
+	if len(p.buf) != 0 {
+		_ = p.write(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) write(line []byte) error {
+	_, err := p.w.Write(append([]byte(p.prefix), line...))
+	return err
 }
@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/vmware/govmomi/govc/cli"
+	"github.com/vmware/govmomi/guest/toolbox"
+	"github.com/vmware/govmomi/units"
+)
+
+type sync struct {
+	*GuestFlag
+
+	download bool
+	force    bool
+	checksum bool
+	owner    bool
+	retries  int
+	verify   bool
+}
+
+func init() {
+	cli.Register("guest.sync", &sync{})
+}
+
+func (cmd *sync) Register(ctx context.Context, f *flag.FlagSet) {
+	cmd.GuestFlag, ctx = newGuestFlag(ctx)
+	cmd.GuestFlag.Register(ctx, f)
+
+	f.BoolVar(&cmd.download, "download", false, "Sync from guest directory SOURCE to local DEST")
+	f.BoolVar(&cmd.force, "f", false, "Copy all files, including unchanged files")
+	f.BoolVar(&cmd.checksum, "checksum", false, "Compare SHA-256 checksums rather than modification times to skip unchanged files")
+	f.BoolVar(&cmd.owner, "owner", false, "Preserve file owner and group IDs")
+	f.IntVar(&cmd.retries, "retries", 3, "Number of times a failed file transfer is retried")
+	f.BoolVar(&cmd.verify, "verify", false, "Read back each file copied and compare SHA-256 checksums")
+}
+
+func (cmd *sync) Usage() string {
+	return "SOURCE DEST"
+}
+
+func (cmd *sync) Description() string {
+	return `Sync local directory SOURCE to directory DEST in the guest VM.
+
+With -download, sync guest directory SOURCE to local directory DEST.
+A file is copied if it does not exist in DEST, if the size differs or if the SOURCE file is newer.
+With -checksum, a file of the same size is copied if the SHA-256 checksums differ, both files are read in full.
+File permissions and modification times are preserved.
+Failed transfers are retried after a growing delay.
+Failed transfers are resumed, including a partial transfer left by a previous sync.
+Files larger than 64MB are uploaded in chunks, which are joined by running 'cat' or 'copy' in the guest.
+The SHA-256 checksum of each file copied is reported, with -verify the file is read back
+from the guest and the transfer fails if its checksum does not match.
+
+Examples:
+  govc guest.sync -l user:pass -vm=my-vm ./config /etc/myapp
+  govc guest.sync -l user:pass -vm=my-vm -owner ./www /var/www
+  govc guest.sync -l user:pass -vm=my-vm -verify ./bin /opt/myapp/bin
+  govc guest.sync -l user:pass -vm=my-vm -checksum ./data /opt/myapp/data
+  govc guest.sync -l user:pass -vm=my-vm -download -json /var/log/myapp ./logs`
+}
+
+func (cmd *sync) Process(ctx context.Context) error {
+	if err := cmd.GuestFlag.Process(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cmd *sync) Run(ctx context.Context, f *flag.FlagSet) error {
+	if f.NArg() != 2 {
+		return flag.ErrHelp
+	}
+
+	c, err := cmd.Toolbox(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := c.NewDirectorySync()
+	s.Force = cmd.force
+	s.Checksum = cmd.checksum
+	s.Owner = cmd.owner
+	s.Retries = cmd.retries
+	s.Verify = cmd.verify
+
+	wait := func() {}
+	if cmd.OutputFlag.TTY {
+		logger := cmd.ProgressLogger("Syncing... ")
+		s.Progress = logger
+		wait = logger.Wait
+	}
+
+	src, dst := f.Arg(0), f.Arg(1)
+	sync := s.Upload
+	if cmd.download {
+		sync = s.Download
+	}
+
+	changes, err := sync(ctx, src, dst)
+	wait()
+	if err != nil {
+		return err
+	}
+
+	return cmd.WriteResult(&syncResult{Changes: changes})
+}
+
+type syncResult struct {
+	Changes []toolbox.DirectorySyncChange `json:"changes"`
+}
+
+func (r *syncResult) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 2, 0, 2, ' ', 0)
+
+	for _, c := range r.Changes {
+		size := ""
+		if c.Op != toolbox.DirectorySyncMkdir {
+			size = units.ByteSize(c.Size).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Op, size, c.Checksum, c.Path)
+	}
+
+	return tw.Flush()
+}
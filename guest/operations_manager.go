@@ -78,3 +78,25 @@ func (m OperationsManager) ProcessManager(ctx context.Context) (*ProcessManager,
 
 	return &ProcessManager{*g.ProcessManager, m.vm, m.c}, nil
 }
+
+func (m OperationsManager) WindowsRegistryManager(ctx context.Context) (*WindowsRegistryManager, error) {
+	var g mo.GuestOperationsManager
+
+	err := m.retrieveOne(ctx, "guestWindowsRegistryManager", &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WindowsRegistryManager{*g.GuestWindowsRegistryManager, m.vm, m.c}, nil
+}
+
+func (m OperationsManager) AliasManager(ctx context.Context) (*AliasManager, error) {
+	var g mo.GuestOperationsManager
+
+	err := m.retrieveOne(ctx, "aliasManager", &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AliasManager{*g.AliasManager, m.vm, m.c}, nil
+}
@@ -0,0 +1,316 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DefaultSessionLimit is the default number of concurrent operations run by Session.Run
+const DefaultSessionLimit = 8
+
+// Session shares guest operations manager references and credentials across operations against many VMs.
+// When Acquire is true, Authentication is exchanged once per VM for a ticket via AuthManager.AcquireCredentials,
+// which is reused by subsequent operations and acquired again if rejected by the guest.
+type Session struct {
+	Authentication types.BaseGuestAuthentication
+	Acquire        bool
+	Limit          int
+
+	c *vim25.Client
+
+	mu      sync.Mutex
+	m       *mo.GuestOperationsManager
+	tickets map[types.ManagedObjectReference]types.BaseGuestAuthentication
+	locks   map[types.ManagedObjectReference]*sync.Mutex
+}
+
+// SessionResult is the outcome of an operation run by Session.Run against a single VM
+type SessionResult struct {
+	VM  types.ManagedObjectReference
+	Err error
+}
+
+// NewSession returns a Session using the given guest credentials
+func NewSession(c *vim25.Client, auth types.BaseGuestAuthentication) *Session {
+	return &Session{
+		Authentication: auth,
+		Limit:          DefaultSessionLimit,
+		c:              c,
+		tickets:        make(map[types.ManagedObjectReference]types.BaseGuestAuthentication),
+		locks:          make(map[types.ManagedObjectReference]*sync.Mutex),
+	}
+}
+
+// NewTokenSession returns a Session that acquires guest tickets using the SAML token of the given sts.Signer,
+// mapped to the guest user name via the guest's AliasManager.
+func NewTokenSession(c *vim25.Client, signer *sts.Signer, username string) *Session {
+	s := NewSession(c, &types.SAMLTokenAuthentication{
+		Token:    signer.Token,
+		Username: username,
+	})
+	s.Acquire = true
+	return s
+}
+
+// Client returns the vim25.Client used by the Session
+func (s *Session) Client() *vim25.Client {
+	return s.c
+}
+
+func (s *Session) managers(ctx context.Context) (*mo.GuestOperationsManager, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.m == nil {
+		var m mo.GuestOperationsManager
+		pc := property.DefaultCollector(s.c)
+		props := []string{"authManager", "fileManager", "processManager"}
+		err := pc.RetrieveOne(ctx, *s.c.ServiceContent.GuestOperationsManager, props, &m)
+		if err != nil {
+			return nil, err
+		}
+		s.m = &m
+	}
+
+	return s.m, nil
+}
+
+// AuthManager returns the AuthManager for the given VM, without a round trip once the Session's managers are known.
+func (s *Session) AuthManager(ctx context.Context, vm mo.Reference) (*AuthManager, error) {
+	m, err := s.managers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthManager{*m.AuthManager, vm.Reference(), s.c}, nil
+}
+
+// FileManager returns the FileManager for the given VM, without a round trip once the Session's managers are known.
+func (s *Session) FileManager(ctx context.Context, vm mo.Reference) (*FileManager, error) {
+	m, err := s.managers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &FileManager{
+		ManagedObjectReference: *m.FileManager,
+		vm:                     vm.Reference(),
+		c:                      s.c,
+		mu:                     new(sync.Mutex),
+		hosts:                  make(map[string]string),
+	}, nil
+}
+
+// ProcessManager returns the ProcessManager for the given VM, without a round trip once the Session's managers are known.
+func (s *Session) ProcessManager(ctx context.Context, vm mo.Reference) (*ProcessManager, error) {
+	m, err := s.managers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &ProcessManager{*m.ProcessManager, vm.Reference(), s.c}, nil
+}
+
+// lock returns the mutex serializing ticket acquisition and release for the given VM
+func (s *Session) lock(vm types.ManagedObjectReference) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.locks[vm]
+	if !ok {
+		l = new(sync.Mutex)
+		s.locks[vm] = l
+	}
+
+	return l
+}
+
+// Credentials returns the guest credentials to use for operations against the given VM.
+// If Acquire is true, a ticket is acquired on first use and cached until Invalidate or Release is called.
+// Concurrent calls for the same VM acquire a single ticket.
+func (s *Session) Credentials(ctx context.Context, vm mo.Reference) (types.BaseGuestAuthentication, error) {
+	if !s.Acquire {
+		return s.Authentication, nil
+	}
+
+	ref := vm.Reference()
+
+	l := s.lock(ref)
+	l.Lock()
+	defer l.Unlock()
+
+	s.mu.Lock()
+	auth, ok := s.tickets[ref]
+	s.mu.Unlock()
+	if ok {
+		return auth, nil
+	}
+
+	m, err := s.AuthManager(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err = m.AcquireCredentials(ctx, s.Authentication, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.tickets[ref] = auth
+	s.mu.Unlock()
+
+	return auth, nil
+}
+
+// Invalidate releases and removes any cached ticket for the given VM, such that the next call to Credentials
+// acquires a new ticket.
+func (s *Session) Invalidate(ctx context.Context, vm mo.Reference) error {
+	return s.invalidate(ctx, vm.Reference(), nil)
+}
+
+// invalidate releases and removes the cached ticket for the given VM.
+// If rejected is not nil, the cached ticket is only removed if it is the rejected ticket,
+// such that a ticket acquired again by a concurrent operation is kept.
+func (s *Session) invalidate(ctx context.Context, vm types.ManagedObjectReference, rejected types.BaseGuestAuthentication) error {
+	l := s.lock(vm)
+	l.Lock()
+	defer l.Unlock()
+
+	s.mu.Lock()
+	auth, ok := s.tickets[vm]
+	s.mu.Unlock()
+	if !ok || (rejected != nil && auth != rejected) {
+		return nil
+	}
+
+	m, err := s.AuthManager(ctx, vm)
+	if err == nil {
+		err = m.ReleaseCredentials(ctx, auth)
+		if isInvalidGuestLogin(err) {
+			err = nil // expired or already released
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.tickets, vm)
+	s.mu.Unlock()
+
+	return err
+}
+
+// Release releases all tickets acquired by the Session
+func (s *Session) Release(ctx context.Context) error {
+	s.mu.Lock()
+	tickets := s.tickets
+	s.tickets = make(map[types.ManagedObjectReference]types.BaseGuestAuthentication)
+	s.mu.Unlock()
+
+	var rerr error
+
+	for vm, auth := range tickets {
+		m, err := s.AuthManager(ctx, vm)
+		if err == nil {
+			err = m.ReleaseCredentials(ctx, auth)
+		}
+		if err != nil && rerr == nil {
+			rerr = err
+		}
+	}
+
+	return rerr
+}
+
+func isInvalidGuestLogin(err error) bool {
+	if soap.IsSoapFault(err) {
+		_, ok := soap.ToSoapFault(err).VimFault().(types.InvalidGuestLogin)
+		return ok
+	}
+	return false
+}
+
+// Do calls f with the guest credentials for the given VM.
+// If an acquired ticket is rejected, such as when it has expired, a new ticket is acquired and f is called once more.
+func (s *Session) Do(ctx context.Context, vm mo.Reference, f func(context.Context, types.BaseGuestAuthentication) error) error {
+	auth, err := s.Credentials(ctx, vm)
+	if err != nil {
+		return err
+	}
+
+	err = f(ctx, auth)
+	if err == nil || !s.Acquire || !isInvalidGuestLogin(err) {
+		return err
+	}
+
+	if err = s.invalidate(ctx, vm.Reference(), auth); err != nil {
+		return err
+	}
+
+	auth, err = s.Credentials(ctx, vm)
+	if err != nil {
+		return err
+	}
+
+	return f(ctx, auth)
+}
+
+// Run calls Do for each of the given VMs, running up to Limit operations concurrently.
+// The results are returned in the same order as vms.
+func (s *Session) Run(ctx context.Context, vms []mo.Reference, f func(context.Context, types.ManagedObjectReference, types.BaseGuestAuthentication) error) []SessionResult {
+	limit := s.Limit
+	if limit <= 0 {
+		limit = DefaultSessionLimit
+	}
+
+	res := make([]SessionResult, len(vms))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i := range vms {
+		ref := vms[i].Reference()
+		res[i].VM = ref
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(r *SessionResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := ctx.Err(); err != nil {
+				r.Err = err
+				return
+			}
+
+			r.Err = s.Do(ctx, ref, func(ctx context.Context, auth types.BaseGuestAuthentication) error {
+				return f(ctx, ref, auth)
+			})
+		}(&res[i])
+	}
+
+	wg.Wait()
+
+	return res
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestSession(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vms, err := find.NewFinder(c).VirtualMachineList(ctx, "*")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		refs := make([]mo.Reference, len(vms))
+		for i := range vms {
+			refs[i] = vms[i]
+		}
+
+		s := guest.NewSession(c, &types.NamePasswordAuthentication{Username: "user", Password: "pass"})
+		s.Acquire = true
+		s.Limit = 2
+
+		var mu sync.Mutex
+		tickets := make(map[string]bool)
+
+		validate := func(ctx context.Context, vm types.ManagedObjectReference, auth types.BaseGuestAuthentication) error {
+			ticket, ok := auth.(*types.TicketedSessionAuthentication)
+			if !ok {
+				t.Fatalf("auth=%T", auth)
+			}
+			mu.Lock()
+			tickets[ticket.Ticket] = true
+			mu.Unlock()
+
+			m, err := s.AuthManager(ctx, vm)
+			if err != nil {
+				return err
+			}
+			return m.ValidateCredentials(ctx, auth)
+		}
+
+		for i := 0; i < 2; i++ { // 2nd time uses the cached tickets
+			for _, r := range s.Run(ctx, refs, validate) {
+				if r.Err != nil {
+					t.Errorf("%s: %s", r.VM, r.Err)
+				}
+			}
+			if len(tickets) != len(refs) {
+				t.Errorf("%d tickets for %d vms", len(tickets), len(refs))
+			}
+		}
+
+		// a ticket released outside of the session is acquired again
+		vm := refs[0]
+		auth, err := s.Credentials(ctx, vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := s.AuthManager(ctx, vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.ReleaseCredentials(ctx, auth); err != nil {
+			t.Fatal(err)
+		}
+
+		err = s.Do(ctx, vm, func(ctx context.Context, auth types.BaseGuestAuthentication) error {
+			return validate(ctx, vm.Reference(), auth)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tickets) != len(refs)+1 {
+			t.Errorf("%d tickets", len(tickets))
+		}
+
+		// concurrent operations against the same VM acquire a single ticket
+		if err = s.Invalidate(ctx, vm); err != nil {
+			t.Fatal(err)
+		}
+		auths := make([]types.BaseGuestAuthentication, 4)
+		var wg sync.WaitGroup
+		for i := range auths {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				auths[i], _ = s.Credentials(ctx, vm)
+			}(i)
+		}
+		wg.Wait()
+		for i := range auths {
+			if auths[i] == nil || auths[i] != auths[0] {
+				t.Errorf("auths[%d]=%#v", i, auths[i])
+			}
+		}
+
+		// an invalidated ticket is released
+		auth = auths[0]
+		if err = s.Invalidate(ctx, vm); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.ValidateCredentials(ctx, auth); err == nil {
+			t.Error("expected InvalidGuestLogin")
+		}
+
+		auth, err = s.Credentials(ctx, vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = s.Release(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.ValidateCredentials(ctx, auth); err == nil {
+			t.Error("expected InvalidGuestLogin")
+		}
+
+		// the given credentials are used as-is without Acquire
+		s = guest.NewSession(c, &types.NamePasswordAuthentication{Username: "user"})
+		res := s.Run(ctx, refs, func(ctx context.Context, vm types.ManagedObjectReference, auth types.BaseGuestAuthentication) error {
+			m, err := s.AuthManager(ctx, vm)
+			if err != nil {
+				return err
+			}
+			return m.ValidateCredentials(ctx, auth)
+		})
+		for _, r := range res {
+			if r.Err == nil {
+				t.Errorf("%s: expected InvalidGuestLogin", r.VM)
+			}
+		}
+	})
+}
@@ -21,10 +21,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,6 +45,12 @@ type Client struct {
 	FileManager    *guest.FileManager
 	Authentication types.BaseGuestAuthentication
 	GuestFamily    types.VirtualMachineGuestOsFamily
+
+	// Stream enables Run to write process output as it is produced, rather than once the process exits.
+	// Output files are polled at the same interval as the process exit status.
+	// Stdin is also streamed to non-Windows guests: as guest file transfers can only replace a file, not append to it,
+	// stdin is transferred as a sequence of files which a shell loop in the guest pipes to the process.
+	Stream bool
 }
 
 // NewClient initializes a Client's ProcessManager, FileManager and GuestFamily
@@ -58,12 +67,56 @@ func NewClient(ctx context.Context, c *vim25.Client, vm mo.Reference, auth types
 		return nil, err
 	}
 
+	family, err := guestFamily(ctx, c, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		ProcessManager: pm,
+		FileManager:    fm,
+		Authentication: auth,
+		GuestFamily:    family,
+	}, nil
+}
+
+// NewSessionClient initializes a Client using the guest.Session's managers and credentials for the given VM
+func NewSessionClient(ctx context.Context, s *guest.Session, vm mo.Reference) (*Client, error) {
+	pm, err := s.ProcessManager(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	fm, err := s.FileManager(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := s.Credentials(ctx, vm)
+	if err != nil {
+		return nil, err
+	}
+
+	family, err := guestFamily(ctx, s.Client(), vm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		ProcessManager: pm,
+		FileManager:    fm,
+		Authentication: auth,
+		GuestFamily:    family,
+	}, nil
+}
+
+func guestFamily(ctx context.Context, c *vim25.Client, vm mo.Reference) (types.VirtualMachineGuestOsFamily, error) {
 	family := ""
 	var props mo.VirtualMachine
 	pc := property.DefaultCollector(c)
-	err = pc.RetrieveOne(context.Background(), vm.Reference(), []string{"guest.guestFamily", "guest.toolsInstallType"}, &props)
+	err := pc.RetrieveOne(ctx, vm.Reference(), []string{"guest.guestFamily", "guest.toolsInstallType"}, &props)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	if props.Guest != nil {
@@ -76,21 +129,31 @@ func NewClient(ctx context.Context, c *vim25.Client, vm mo.Reference, auth types
 		}
 	}
 
-	return &Client{
-		ProcessManager: pm,
-		FileManager:    fm,
-		Authentication: auth,
-		GuestFamily:    types.VirtualMachineGuestOsFamily(family),
-	}, nil
+	return types.VirtualMachineGuestOsFamily(family), nil
 }
 
 func (c *Client) rm(ctx context.Context, path string) {
+	if ctx.Err() != nil {
+		ctx = context.Background() // cleanup after Run is canceled
+	}
+
 	err := c.FileManager.DeleteFile(ctx, c.Authentication, path)
 	if err != nil {
 		log.Printf("rm %q: %s", path, err)
 	}
 }
 
+func (c *Client) rmdir(ctx context.Context, path string) {
+	if ctx.Err() != nil {
+		ctx = context.Background() // cleanup after Run is canceled
+	}
+
+	err := c.FileManager.DeleteDirectory(ctx, c.Authentication, path, true)
+	if err != nil {
+		log.Printf("rmdir %q: %s", path, err)
+	}
+}
+
 func (c *Client) mktemp(ctx context.Context) (string, error) {
 	return c.FileManager.CreateTemporaryFile(ctx, c.Authentication, "govmomi-", "", "")
 }
@@ -105,8 +168,22 @@ func (e *exitError) ExitCode() int {
 }
 
 // Run implements exec.Cmd.Run over vmx guest RPC against standard vmware-tools or toolbox.
+// Stdin is read until EOF and copied to the guest before the process is started, unless Stream is enabled.
+// If ctx is canceled or its deadline expires before the process exits, the process is terminated.
 func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
-	if cmd.Stdin != nil {
+	var input *guestInput
+
+	if cmd.Stdin != nil && c.Stream && c.GuestFamily != types.VirtualMachineGuestOsFamilyWindowsGuest {
+		dir, err := c.FileManager.CreateTemporaryDirectory(ctx, c.Authentication, "govmomi-", "", "")
+		if err != nil {
+			return err
+		}
+
+		defer c.rmdir(ctx, dir)
+
+		input = newGuestInput(cmd.Stdin, dir)
+		defer input.close()
+	} else if cmd.Stdin != nil {
 		dst, err := c.mktemp(ctx)
 		if err != nil {
 			return err
@@ -132,16 +209,12 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 		cmd.Args = append(cmd.Args, "<", dst)
 	}
 
-	output := []struct {
-		io.Writer
-		fd   string
-		path string
-	}{
-		{cmd.Stdout, "1", ""},
-		{cmd.Stderr, "2", ""},
+	output := []*guestOutput{
+		{Writer: cmd.Stdout, fd: "1"},
+		{Writer: cmd.Stderr, fd: "2"},
 	}
 
-	for i, out := range output {
+	for _, out := range output {
 		if out.Writer == nil {
 			continue
 		}
@@ -154,7 +227,7 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 		defer c.rm(ctx, dst)
 
 		cmd.Args = append(cmd.Args, out.fd+">", dst)
-		output[i].path = dst
+		out.path = dst
 	}
 
 	path := cmd.Path
@@ -175,6 +248,12 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 		}
 	}
 
+	if input != nil {
+		path = "/bin/bash"
+		arg := "'" + input.pipe(strings.Join(append([]string{cmd.Path}, cmd.Args...), " ")) + "'"
+		args = []string{"-c", arg}
+	}
+
 	spec := types.GuestProgramSpec{
 		ProgramPath:      path,
 		Arguments:        strings.Join(args, " "),
@@ -191,44 +270,199 @@ func (c *Client) Run(ctx context.Context, cmd *exec.Cmd) error {
 	for {
 		procs, err := c.ProcessManager.ListProcesses(ctx, c.Authentication, []int64{pid})
 		if err != nil {
-			return err
+			return c.canceled(ctx, pid, err)
 		}
 
 		p := procs[0]
-		if p.EndTime == nil {
-			<-time.After(time.Second / 2)
-			continue
+		if p.EndTime != nil {
+			rc = int(p.ExitCode)
+			break
+		}
+
+		if input != nil {
+			if err = c.feed(ctx, input); err != nil {
+				return c.canceled(ctx, pid, err)
+			}
 		}
 
-		rc = int(p.ExitCode)
+		if c.Stream {
+			if err = c.tail(ctx, output); err != nil {
+				return c.canceled(ctx, pid, err)
+			}
+		}
 
-		break
+		select {
+		case <-ctx.Done():
+			return c.canceled(ctx, pid, ctx.Err())
+		case <-time.After(time.Second / 2):
+		}
 	}
 
+	if err = c.tail(ctx, output); err != nil {
+		return err
+	}
+
+	if rc != 0 {
+		return &exitError{fmt.Errorf("%s: exit %d", cmd.Path, rc), rc}
+	}
+
+	return nil
+}
+
+// canceled terminates the guest process pid if ctx is done, returning the ctx error in that case, otherwise err
+func (c *Client) canceled(ctx context.Context, pid int64, err error) error {
+	if ctx.Err() == nil {
+		return err
+	}
+
+	if terr := c.ProcessManager.TerminateProcess(context.Background(), c.Authentication, pid); terr != nil {
+		log.Printf("terminate %d: %s", pid, terr)
+	}
+
+	return ctx.Err()
+}
+
+// guestOutput is a process output file in the guest, copied to Writer
+type guestOutput struct {
+	io.Writer
+	fd     string
+	path   string
+	offset int64
+}
+
+// tail copies any new data written to the output files since the last call
+func (c *Client) tail(ctx context.Context, output []*guestOutput) error {
 	for _, out := range output {
 		if out.Writer == nil {
 			continue
 		}
 
-		f, _, err := c.Download(ctx, out.path)
+		f, err := c.DownloadOffset(ctx, out.path, out.offset)
 		if err != nil {
 			return err
 		}
 
-		_, err = io.Copy(out.Writer, f)
+		n, err := io.Copy(out.Writer, f)
 		_ = f.Close()
+		out.offset += n
 		if err != nil {
 			return err
 		}
 	}
 
-	if rc != 0 {
-		return &exitError{fmt.Errorf("%s: exit %d", cmd.Path, rc), rc}
+	return nil
+}
+
+// guestInput is process input, transferred to the guest as numbered files in dir as it is read
+type guestInput struct {
+	dir  string
+	data chan []byte
+	done chan struct{}
+	err  error
+	n    int
+	eof  bool
+}
+
+func newGuestInput(r io.Reader, dir string) *guestInput {
+	in := &guestInput{
+		dir:  dir,
+		data: make(chan []byte),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(in.data)
+
+		for {
+			buf := make([]byte, 32*1024)
+			n, err := r.Read(buf)
+			if n > 0 {
+				select {
+				case in.data <- buf[:n]:
+				case <-in.done:
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					in.err = err
+				}
+				return
+			}
+		}
+	}()
+
+	return in
+}
+
+func (in *guestInput) close() {
+	close(in.done)
+}
+
+// pipe returns the given shell command with its stdin piped from a loop which concatenates the input files
+// in order and removes them, until the eof file exists and all input files have been consumed.
+func (in *guestInput) pipe(command string) string {
+	loop := fmt.Sprintf(
+		`i=0; while :; do if [ -e %[1]s/$i ]; then cat %[1]s/$i; rm -f %[1]s/$i; i=$((i+1)); `+
+			`elif [ -e %[1]s/eof ]; then [ -e %[1]s/$i ] || break; else sleep 0.1; fi; done`, in.dir)
+
+	return "(" + loop + ") | " + command
+}
+
+// feed transfers any input read since the last call to the guest, followed by the eof file once the input is exhausted.
+// Each file is transferred to a temporary name and then renamed, such that the guest never reads a partial file.
+func (c *Client) feed(ctx context.Context, in *guestInput) error {
+	if in.eof {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	closed := false
+
+drain:
+	for {
+		select {
+		case data, ok := <-in.data:
+			if !ok {
+				closed = true
+				break drain
+			}
+			buf.Write(data)
+		default:
+			break drain
+		}
+	}
+
+	if buf.Len() != 0 {
+		if err := c.put(ctx, in, &buf, strconv.Itoa(in.n)); err != nil {
+			return err
+		}
+		in.n++
+	}
+
+	if closed {
+		if in.err != nil {
+			return in.err
+		}
+		in.eof = true
+		return c.put(ctx, in, new(bytes.Buffer), "eof")
 	}
 
 	return nil
 }
 
+func (c *Client) put(ctx context.Context, in *guestInput, buf *bytes.Buffer, name string) error {
+	tmp := in.dir + "/tmp"
+	p := soap.DefaultUpload
+	p.ContentLength = int64(buf.Len())
+
+	if err := c.Upload(ctx, buf, tmp, p, new(types.GuestPosixFileAttributes), true); err != nil {
+		return err
+	}
+
+	return c.FileManager.MoveFile(ctx, c.Authentication, tmp, in.dir+"/"+name, true)
+}
+
 // archiveReader wraps an io.ReadCloser to support streaming download
 // of a guest directory, stops reading once it sees the stream trailer.
 // This is only useful when guest tools is the Go toolbox.
@@ -296,6 +530,58 @@ func (c *Client) Download(ctx context.Context, src string) (io.ReadCloser, int64
 	return f, n, nil
 }
 
+// DownloadOffset initiates a file transfer from the guest, returning the file contents following offset.
+// A Range request is used to skip offset bytes when supported by the server, otherwise they are discarded.
+func (c *Client) DownloadOffset(ctx context.Context, src string, offset int64) (io.ReadCloser, error) {
+	vc := c.ProcessManager.Client()
+
+	info, err := c.FileManager.InitiateFileTransferFromGuest(ctx, c.Authentication, src)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != 0 && info.Size != 0 && info.Size <= offset {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil // no new data
+	}
+
+	u, err := c.FileManager.TransferURL(ctx, info.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	p := soap.DefaultDownload
+	if offset != 0 {
+		p.Headers = map[string]string{"Range": fmt.Sprintf("bytes=%d-", offset)}
+	}
+
+	res, err := vc.DownloadRequest(ctx, u, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	return offsetReader(res, offset)
+}
+
+// offsetReader returns the response body following offset, depending on the response status
+func offsetReader(res *http.Response, offset int64) (io.ReadCloser, error) {
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		return res.Body, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		_ = res.Body.Close()
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	case http.StatusOK:
+		if _, err := io.CopyN(ioutil.Discard, res.Body, offset); err != nil && err != io.EOF {
+			_ = res.Body.Close()
+			return nil, err
+		}
+		return res.Body, nil
+	default:
+		_ = res.Body.Close()
+		return nil, fmt.Errorf("download(%s): %s", res.Request.URL, res.Status)
+	}
+}
+
 // Upload transfers a file to the guest
 func (c *Client) Upload(ctx context.Context, src io.Reader, dst string, p soap.Upload, attr types.BaseGuestFileAttributes, force bool) error {
 	vc := c.ProcessManager.Client()
@@ -0,0 +1,138 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOffsetReader(t *testing.T) {
+	const data = "hello world"
+
+	ranges := true
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ranges {
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(data))
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		_, _ = w.Write([]byte(data))
+	}))
+	defer s.Close()
+
+	for _, ranges = range []bool{true, false} {
+		for _, offset := range []int64{0, 6, int64(len(data))} {
+			req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+			if offset != 0 {
+				req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := offsetReader(res, offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := ioutil.ReadAll(f)
+			_ = f.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(b) != data[offset:] {
+				t.Errorf("ranges=%t offset=%d: %q", ranges, offset, b)
+			}
+		}
+	}
+
+	res, err := http.Get(s.URL + "/enoent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.StatusCode = http.StatusNotFound
+	if _, err = offsetReader(res, 0); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGuestInputPipe(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	r, w := io.Pipe()
+	in := newGuestInput(r, dir)
+	defer in.close()
+
+	var out bytes.Buffer
+	cmd := exec.Command("bash", "-c", in.pipe("tr a-z A-Z"))
+	cmd.Stdout = &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// put input files as Client.feed does
+	put := func(name, data string) {
+		tmp := filepath.Join(dir, "tmp")
+		if err := os.WriteFile(tmp, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, data := range []string{"hello ", "stream"} {
+		go func(data string) { _, _ = w.Write([]byte(data)) }(data)
+		put(strconv.Itoa(i), string(<-in.data))
+		time.Sleep(200 * time.Millisecond) // input is consumed while the process is running
+		if _, err := os.Stat(filepath.Join(dir, strconv.Itoa(i))); !os.IsNotExist(err) {
+			t.Errorf("input file %d not consumed: %v", i, err)
+		}
+	}
+
+	_ = w.Close()
+	if _, ok := <-in.data; ok {
+		t.Error("expected EOF")
+	}
+	put("eof", "")
+
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "HELLO STREAM" {
+		t.Errorf("output=%q", out.String())
+	}
+}
@@ -0,0 +1,827 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DirectorySync operations
+const (
+	DirectorySyncMkdir    = "mkdir"
+	DirectorySyncUpload   = "upload"
+	DirectorySyncDownload = "download"
+)
+
+// partialSuffix is appended to the name of a local file while it is downloaded
+const partialSuffix = ".partial"
+
+// DirectorySync copies a directory tree between the local file system and the guest, in either direction.
+// A file is skipped if the destination file has the same size and a modification time that is not older
+// than the source file, or the same checksum when Checksum is set.  File permissions and modification times are preserved.
+// Failed transfers are retried after a growing delay, downloads are resumed from the last byte received, including a
+// partial download left by a previous sync.  As the guest file transfer API does not support appending to a file,
+// files larger than UploadChunkSize are uploaded in chunks, which are joined in the guest once all chunks are transferred.
+// Failed uploads are resumed from the first chunk not transferred, including chunks left by a previous sync.
+// An error is returned if the source directory does not exist.
+type DirectorySync struct {
+	Client *Client
+
+	// Force transfers all files, including unchanged files.
+	Force bool
+	// Checksum compares the SHA-256 checksums of files with the same size to decide whether a file is unchanged,
+	// rather than modification times.  Both the source and destination file are read in full.
+	Checksum bool
+	// Owner preserves the file owner and group IDs.
+	Owner bool
+	// Retries is the number of times a failed file transfer is retried.
+	Retries int
+	// RetryDelay is the delay before the first retry, doubled for each retry after that.
+	RetryDelay time.Duration
+	// UploadChunkSize is the size of the chunks used to upload larger files, zero disables chunked uploads.
+	// Joining chunks starts a process in the guest: cat on Linux, copy on Windows.
+	UploadChunkSize int64
+	// Verify reads back each transferred file and compares its checksum with the checksum of the data transferred.
+	// A mismatch fails the transfer, which is then retried from the start.
+	Verify bool
+	// Progress, if set, receives the aggregate progress of all file transfers.
+	Progress progress.Sinker
+}
+
+// DirectorySyncChange describes a change made to the destination.
+// Checksum is the SHA-256 checksum of the data transferred, which is compared with the checksum of
+// the file read back when DirectorySync.Verify is set.
+type DirectorySyncChange struct {
+	Op       string `json:"op"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// NewDirectorySync creates a new instance of DirectorySync
+func (c *Client) NewDirectorySync() *DirectorySync {
+	return &DirectorySync{
+		Client:          c,
+		Retries:         3,
+		RetryDelay:      time.Second,
+		UploadChunkSize: 64 * 1024 * 1024,
+	}
+}
+
+type syncFile struct {
+	size  int64
+	mtime time.Time
+	dir   bool
+	attr  types.BaseGuestFileAttributes
+	info  os.FileInfo
+}
+
+// changed returns true if f should replace the destination file dst.
+func (f syncFile) changed(dst syncFile) bool {
+	if f.size != dst.size {
+		return true
+	}
+	// Guest file times have a one second resolution
+	return f.mtime.Truncate(time.Second).After(dst.mtime.Truncate(time.Second))
+}
+
+// Upload copies local directory src to guest directory dst.
+func (s *DirectorySync) Upload(ctx context.Context, src string, dst string) ([]DirectorySyncChange, error) {
+	local, err := localFiles(src, false)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := s.guestFiles(ctx, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	if remote == nil {
+		if err = s.Client.FileManager.MakeDirectory(ctx, s.Client.Authentication, dst, true); err != nil {
+			return nil, err
+		}
+	}
+
+	var same func(string) (bool, error)
+	if s.Checksum {
+		same = func(name string) (bool, error) {
+			return s.sameChecksum(ctx, filepath.Join(src, filepath.FromSlash(name)), s.Client.guestPath(dst, name))
+		}
+	}
+
+	changes, err := s.changes(local, remote, DirectorySyncUpload, same)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.apply(ctx, changes, func(c *DirectorySyncChange, p progress.Sinker) error {
+		name := s.Client.guestPath(dst, c.Path)
+
+		if c.Op == DirectorySyncMkdir {
+			return s.Client.FileManager.MakeDirectory(ctx, s.Client.Authentication, name, true)
+		}
+
+		return s.upload(ctx, c, filepath.Join(src, filepath.FromSlash(c.Path)), name, local[c.Path], remote, p)
+	})
+
+	return changes, err
+}
+
+// Download copies guest directory src to local directory dst.
+func (s *DirectorySync) Download(ctx context.Context, src string, dst string) ([]DirectorySyncChange, error) {
+	remote, err := s.guestFiles(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+
+	if remote == nil {
+		return nil, &os.PathError{Op: "sync", Path: src, Err: os.ErrNotExist}
+	}
+
+	local, err := localFiles(dst, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(dst, 0755); err != nil {
+		return nil, err
+	}
+
+	var same func(string) (bool, error)
+	if s.Checksum {
+		same = func(name string) (bool, error) {
+			return s.sameChecksum(ctx, filepath.Join(dst, filepath.FromSlash(name)), s.Client.guestPath(src, name))
+		}
+	}
+
+	changes, err := s.changes(remote, local, DirectorySyncDownload, same)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.apply(ctx, changes, func(c *DirectorySyncChange, p progress.Sinker) error {
+		name := filepath.Join(dst, filepath.FromSlash(c.Path))
+
+		if c.Op == DirectorySyncMkdir {
+			return os.MkdirAll(name, 0755)
+		}
+
+		return s.download(ctx, c, s.Client.guestPath(src, c.Path), name, remote[c.Path], p)
+	})
+
+	return changes, err
+}
+
+// changes computes the changes to copy src to dst, using op for file transfers.
+// If same is not nil, it is used instead of modification times to compare files with the same size.
+// Directories are created first, parent before child, followed by file transfers.
+func (s *DirectorySync) changes(src, dst map[string]syncFile, op string, same func(string) (bool, error)) ([]DirectorySyncChange, error) {
+	var mkdirs, transfers []DirectorySyncChange
+
+	for name, f := range src {
+		if strings.HasSuffix(name, partialSuffix) {
+			continue // chunk of an incomplete upload
+		}
+
+		d, exists := dst[name]
+
+		if f.dir {
+			if !exists {
+				mkdirs = append(mkdirs, DirectorySyncChange{Op: DirectorySyncMkdir, Path: name})
+			}
+			continue
+		}
+
+		transfer := s.Force || !exists || d.dir || f.size != d.size
+
+		if !transfer {
+			if same == nil {
+				transfer = f.changed(d)
+			} else {
+				ok, err := same(name)
+				if err != nil {
+					return nil, fmt.Errorf("checksum %s: %s", name, err)
+				}
+				transfer = !ok
+			}
+		}
+
+		if transfer {
+			transfers = append(transfers, DirectorySyncChange{Op: op, Path: name, Size: f.size})
+		}
+	}
+
+	byPath := func(c []DirectorySyncChange) {
+		sort.Slice(c, func(i, j int) bool {
+			return c[i].Path < c[j].Path
+		})
+	}
+
+	byPath(mkdirs)
+	byPath(transfers)
+
+	return append(mkdirs, transfers...), nil
+}
+
+// apply makes the given changes in order, updating each change's Checksum as files are transferred.
+func (s *DirectorySync) apply(ctx context.Context, changes []DirectorySyncChange, fn func(*DirectorySyncChange, progress.Sinker) error) error {
+	var agg *progress.Transfers
+
+	if s.Progress != nil {
+		var files int
+		var size int64
+		for _, c := range changes {
+			if c.Op != DirectorySyncMkdir {
+				files++
+				size += c.Size
+			}
+		}
+		agg = progress.NewTransfers(s.Progress, files, size)
+		defer agg.Done()
+	}
+
+	for i := range changes {
+		c := &changes[i]
+
+		var sinker progress.Sinker
+		if agg != nil && c.Op != DirectorySyncMkdir {
+			sinker = agg.Sinker(c.Size)
+		}
+
+		if err := fn(c, sinker); err != nil {
+			return fmt.Errorf("%s %s: %s", c.Op, c.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// retry calls fn until it succeeds, ctx is done or Retries is exceeded.
+// The delay between attempts starts at RetryDelay and doubles after each retry.
+func (s *DirectorySync) retry(ctx context.Context, name string, fn func() error) error {
+	delay := s.RetryDelay
+
+	for i := 0; ; i++ {
+		err := fn()
+		if err == nil || i >= s.Retries || ctx.Err() != nil || errors.Is(err, errSizeMismatch) {
+			return err
+		}
+
+		log.Printf("retrying %s in %s: %s", name, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+var errChecksumMismatch = errors.New("checksum mismatch")
+
+// verify compares sum with the checksum of guest file name
+func (s *DirectorySync) verify(ctx context.Context, name string, sum string) error {
+	if !s.Verify {
+		return nil
+	}
+
+	guest, err := s.guestChecksum(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if guest != sum {
+		return fmt.Errorf("%w: transferred %s, guest file %s", errChecksumMismatch, sum, guest)
+	}
+
+	return nil
+}
+
+// guestChecksum returns the checksum of guest file name
+func (s *DirectorySync) guestChecksum(ctx context.Context, name string) (string, error) {
+	f, _, err := s.Client.Download(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sameChecksum returns true if local file name and guest file guest have the same checksum
+func (s *DirectorySync) sameChecksum(ctx context.Context, name string, guest string) (bool, error) {
+	local, err := checksum(name)
+	if err != nil {
+		return false, err
+	}
+
+	sum, err := s.guestChecksum(ctx, guest)
+	if err != nil {
+		return false, err
+	}
+
+	return local == sum, nil
+}
+
+func (s *DirectorySync) upload(ctx context.Context, c *DirectorySyncChange, src, dst string, file syncFile, remote map[string]syncFile, p progress.Sinker) error {
+	attr := s.Client.guestAttributes(file, s.Owner)
+
+	if s.UploadChunkSize > 0 && file.size > s.UploadChunkSize {
+		return s.uploadChunks(ctx, c, src, dst, file, attr, remote, p)
+	}
+
+	return s.retry(ctx, src, func() error {
+		f, err := os.Open(filepath.Clean(src))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+
+		param := soap.DefaultUpload
+		param.ContentLength = file.size
+		param.Progress = p
+
+		err = s.Client.Upload(ctx, io.TeeReader(f, h), dst, param, attr, true)
+		if err != nil {
+			return err
+		}
+
+		c.Checksum = hex.EncodeToString(h.Sum(nil))
+
+		return s.verify(ctx, dst, c.Checksum)
+	})
+}
+
+// uploadChunk is a range of a file uploaded to the guest as a separate file
+type uploadChunk struct {
+	path   string // relative to the sync destination
+	offset int64
+	size   int64
+}
+
+// splitChunks splits a file of the given size into chunks of at most size n.
+// Chunk files are named after the file, such that they are ignored when syncing.
+func splitChunks(name string, size int64, n int64) []uploadChunk {
+	var chunks []uploadChunk
+
+	for offset := int64(0); offset < size; offset += n {
+		chunk := uploadChunk{
+			path:   fmt.Sprintf("%s.%d%s", name, len(chunks), partialSuffix),
+			offset: offset,
+			size:   n,
+		}
+		if offset+n > size {
+			chunk.size = size - offset
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// uploadChunks uploads a file as a sequence of chunks, then joins the chunks in the guest.
+// Chunks are uploaded with the modification time of the file, chunks found in the guest with the expected size and
+// modification time, uploaded by a previous attempt or sync, are not uploaded again.
+func (s *DirectorySync) uploadChunks(ctx context.Context, c *DirectorySyncChange, src, dst string, file syncFile,
+	attr types.BaseGuestFileAttributes, remote map[string]syncFile, p progress.Sinker) error {
+	chunks := splitChunks(c.Path, file.size, s.UploadChunkSize)
+
+	names := make([]string, len(chunks))
+	done := make([]bool, len(chunks))
+	for i, chunk := range chunks {
+		names[i] = dst + strings.TrimPrefix(chunk.path, c.Path)
+		if f, ok := remote[chunk.path]; ok && !f.dir && f.size == chunk.size {
+			done[i] = f.mtime.Truncate(time.Second).Equal(file.mtime.Truncate(time.Second))
+		}
+	}
+
+	var ch chan<- progress.Report
+	if p != nil {
+		ch = p.Sink()
+		defer close(ch)
+	}
+
+	err := s.retry(ctx, src, func() error {
+		f, err := os.Open(filepath.Clean(src))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		for i, chunk := range chunks {
+			if done[i] {
+				continue
+			}
+
+			if _, err = f.Seek(chunk.offset, io.SeekStart); err != nil {
+				return err
+			}
+
+			var r io.Reader = io.LimitReader(f, chunk.size)
+			if ch != nil {
+				r = io.TeeReader(r, &transferProgress{Writer: ioutil.Discard, ch: ch, pos: chunk.offset, size: file.size})
+			}
+
+			param := soap.DefaultUpload
+			param.ContentLength = chunk.size
+
+			if err = s.Client.Upload(ctx, r, names[i], param, s.Client.guestAttributes(file, false), true); err != nil {
+				return err
+			}
+
+			done[i] = true
+		}
+
+		if err = s.join(ctx, names, dst); err != nil {
+			return err
+		}
+
+		if err = s.Client.FileManager.ChangeFileAttributes(ctx, s.Client.Authentication, dst, attr); err != nil {
+			return err
+		}
+
+		if c.Checksum, err = checksum(src); err != nil {
+			return err
+		}
+
+		if err = s.verify(ctx, dst, c.Checksum); errors.Is(err, errChecksumMismatch) {
+			for i := range done {
+				done[i] = false // uploaded chunks are stale, the retry starts over
+			}
+		}
+
+		return err
+	})
+	if err != nil {
+		if ch != nil {
+			ch <- transferReport{err: err}
+		}
+		return err
+	}
+
+	for _, name := range names {
+		if err = s.Client.FileManager.DeleteFile(ctx, s.Client.Authentication, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// join concatenates the guest files chunks to guest file dst
+func (s *DirectorySync) join(ctx context.Context, chunks []string, dst string) error {
+	quote := func(name string) string {
+		return `"` + name + `"`
+	}
+
+	cmd := &exec.Cmd{}
+
+	if s.Client.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest {
+		src := make([]string, len(chunks))
+		for i := range chunks {
+			src[i] = quote(chunks[i])
+		}
+		cmd.Path = "copy"
+		cmd.Args = []string{"/b", "/y", strings.Join(src, "+"), quote(dst)}
+	} else {
+		cmd.Path = "cat"
+		for i := range chunks {
+			cmd.Args = append(cmd.Args, quote(chunks[i]))
+		}
+		cmd.Args = append(cmd.Args, ">", quote(dst))
+	}
+
+	return s.Client.Run(ctx, cmd)
+}
+
+var errSizeMismatch = errors.New("file size mismatch")
+
+func (s *DirectorySync) download(ctx context.Context, c *DirectorySyncChange, src, dst string, file syncFile, p progress.Sinker) error {
+	partial := dst + partialSuffix
+
+	var ch chan<- progress.Report
+	if p != nil {
+		ch = p.Sink()
+		defer close(ch)
+	}
+
+	err := s.retry(ctx, src, func() error {
+		f, err := os.OpenFile(filepath.Clean(partial), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		offset := info.Size()
+
+		if offset > file.size {
+			// guest file was truncated since the partial download
+			if err = f.Truncate(0); err != nil {
+				return err
+			}
+			offset = 0
+		}
+
+		r, err := s.Client.DownloadOffset(ctx, src, offset)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		var w io.Writer = f
+		if ch != nil {
+			w = &transferProgress{Writer: f, ch: ch, pos: offset, size: file.size}
+		}
+
+		n, err := io.Copy(w, r)
+		if err != nil {
+			return err
+		}
+
+		if offset+n != file.size {
+			return fmt.Errorf("%w: expected %d bytes, received %d", errSizeMismatch, file.size, offset+n)
+		}
+
+		if err = f.Close(); err != nil {
+			return err
+		}
+
+		if c.Checksum, err = checksum(partial); err != nil {
+			return err
+		}
+
+		if err = s.verify(ctx, src, c.Checksum); errors.Is(err, errChecksumMismatch) {
+			_ = os.Remove(partial) // resumed data is stale, the retry starts over
+		}
+
+		return err
+	})
+	if err != nil {
+		if ch != nil {
+			ch <- transferReport{err: err}
+		}
+		if errors.Is(err, errSizeMismatch) {
+			_ = os.Remove(partial) // start over next time
+		}
+		return err
+	}
+
+	if err = setLocalAttributes(partial, file, s.Owner); err != nil {
+		return err
+	}
+
+	return os.Rename(partial, dst)
+}
+
+// transferProgress reports transfer progress to ch
+type transferProgress struct {
+	io.Writer
+	ch   chan<- progress.Report
+	pos  int64
+	size int64
+}
+
+func (p *transferProgress) Write(b []byte) (int, error) {
+	n, err := p.Writer.Write(b)
+	p.pos += int64(n)
+
+	if p.size > 0 {
+		p.ch <- transferReport{pct: 100 * float32(p.pos) / float32(p.size)}
+	}
+
+	return n, err
+}
+
+type transferReport struct {
+	pct float32
+	err error
+}
+
+func (r transferReport) Percentage() float32 {
+	return r.pct
+}
+
+func (r transferReport) Detail() string {
+	return ""
+}
+
+func (r transferReport) Error() error {
+	return r.err
+}
+
+func checksum(name string) (string, error) {
+	f, err := os.Open(filepath.Clean(name))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// setLocalAttributes applies the guest file permissions, modification time and optionally the owner to local file name
+func setLocalAttributes(name string, file syncFile, owner bool) error {
+	if attr, ok := file.attr.(*types.GuestPosixFileAttributes); ok {
+		if attr.Permissions != 0 {
+			if err := os.Chmod(name, os.FileMode(attr.Permissions).Perm()); err != nil {
+				return err
+			}
+		}
+
+		if owner && attr.OwnerId != nil && attr.GroupId != nil {
+			if err := os.Lchown(name, int(*attr.OwnerId), int(*attr.GroupId)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Preserve the modification time, such that the file is not transferred again
+	return os.Chtimes(name, file.mtime, file.mtime)
+}
+
+// guestAttributes returns the guest file attributes for the given local file
+func (c *Client) guestAttributes(file syncFile, owner bool) types.BaseGuestFileAttributes {
+	mtime := file.mtime
+
+	if c.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest {
+		return &types.GuestWindowsFileAttributes{
+			GuestFileAttributes: types.GuestFileAttributes{ModificationTime: &mtime},
+		}
+	}
+
+	attr := &types.GuestPosixFileAttributes{
+		GuestFileAttributes: types.GuestFileAttributes{ModificationTime: &mtime},
+		Permissions:         int64(file.info.Mode().Perm()),
+	}
+
+	if owner {
+		attr.OwnerId, attr.GroupId = fileOwner(file.info)
+	}
+
+	return attr
+}
+
+// guestPath joins the guest directory dir with the relative slash separated path name
+func (c *Client) guestPath(dir string, name string) string {
+	if c.GuestFamily == types.VirtualMachineGuestOsFamilyWindowsGuest {
+		return strings.TrimRight(dir, `\`) + `\` + strings.ReplaceAll(name, "/", `\`)
+	}
+
+	return path.Join(dir, name)
+}
+
+// localFiles returns the files in the local directory dir, keyed by relative path.
+// If missing is true, an empty map is returned if dir does not exist.  Partial downloads are ignored.
+func localFiles(dir string, missing bool) (map[string]syncFile, error) {
+	files := make(map[string]syncFile)
+
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			if missing && os.IsNotExist(err) && name == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		if rel == "." || strings.HasSuffix(rel, partialSuffix) {
+			return nil
+		}
+
+		files[filepath.ToSlash(rel)] = syncFile{
+			size:  info.Size(),
+			mtime: info.ModTime(),
+			dir:   info.IsDir(),
+			info:  info,
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// guestFiles returns the files in the guest directory dir, keyed by relative path.
+// A nil map is returned if dir does not exist.
+func (s *DirectorySync) guestFiles(ctx context.Context, dir string) (map[string]syncFile, error) {
+	files := make(map[string]syncFile)
+
+	var list func(rel string) error
+
+	list = func(rel string) error {
+		name := dir
+		if rel != "" {
+			name = s.Client.guestPath(dir, rel)
+		}
+
+		for index := int32(0); ; {
+			info, err := s.Client.FileManager.ListFiles(ctx, s.Client.Authentication, name, index, 0, "")
+			if err != nil {
+				return err
+			}
+
+			for _, f := range info.Files {
+				if f.Path == "." || f.Path == ".." {
+					continue
+				}
+
+				file := syncFile{
+					size: f.Size,
+					dir:  f.Type == string(types.GuestFileTypeDirectory),
+					attr: f.Attributes,
+				}
+
+				if f.Attributes != nil {
+					if mtime := f.Attributes.GetGuestFileAttributes().ModificationTime; mtime != nil {
+						file.mtime = *mtime
+					}
+				}
+
+				p := path.Join(rel, f.Path)
+				files[p] = file
+
+				if file.dir {
+					if err = list(p); err != nil {
+						return err
+					}
+				}
+			}
+
+			index += int32(len(info.Files))
+			if info.Remaining == 0 || len(info.Files) == 0 {
+				return nil
+			}
+		}
+	}
+
+	if err := list(""); err != nil {
+		if isFileNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func isFileNotFound(err error) bool {
+	if soap.IsSoapFault(err) {
+		_, ok := soap.ToSoapFault(err).VimFault().(types.FileNotFound)
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestDirectorySyncChanges(t *testing.T) {
+	now := time.Now()
+
+	src := map[string]syncFile{
+		"a":                     {dir: true},
+		"a/b":                   {dir: true},
+		"a/b/new":               {size: 1, mtime: now},
+		"same":                  {size: 2, mtime: now},
+		"newer":                 {size: 2, mtime: now.Add(time.Minute)},
+		"resized":               {size: 3, mtime: now},
+		"big.0" + partialSuffix: {size: 1, mtime: now},
+	}
+
+	dst := map[string]syncFile{
+		"a":       {dir: true},
+		"same":    {size: 2, mtime: now.Truncate(time.Second)},
+		"newer":   {size: 2, mtime: now},
+		"resized": {size: 2, mtime: now},
+		"extra":   {size: 1, mtime: now},
+	}
+
+	s := new(Client).NewDirectorySync()
+
+	changes, err := s.changes(src, dst, DirectorySyncUpload, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []DirectorySyncChange{
+		{Op: DirectorySyncMkdir, Path: "a/b"},
+		{Op: DirectorySyncUpload, Path: "a/b/new", Size: 1},
+		{Op: DirectorySyncUpload, Path: "newer", Size: 2},
+		{Op: DirectorySyncUpload, Path: "resized", Size: 3},
+	}
+
+	if !reflect.DeepEqual(changes, expect) {
+		t.Errorf("changes=%#v", changes)
+	}
+
+	// with checksums, only files of the same size are compared and modification times are ignored
+	var compared []string
+	same := func(name string) (bool, error) {
+		compared = append(compared, name)
+		return name == "newer", nil
+	}
+
+	changes, err = s.changes(src, dst, DirectorySyncUpload, same)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = []DirectorySyncChange{
+		{Op: DirectorySyncMkdir, Path: "a/b"},
+		{Op: DirectorySyncUpload, Path: "a/b/new", Size: 1},
+		{Op: DirectorySyncUpload, Path: "resized", Size: 3},
+		{Op: DirectorySyncUpload, Path: "same", Size: 2},
+	}
+
+	if !reflect.DeepEqual(changes, expect) {
+		t.Errorf("changes=%#v", changes)
+	}
+	if len(compared) != 2 {
+		t.Errorf("compared=%v", compared)
+	}
+
+	_, err = s.changes(src, dst, DirectorySyncUpload, func(string) (bool, error) { return false, os.ErrPermission })
+	if err == nil {
+		t.Errorf("err=%v", err)
+	}
+
+	s.Force = true
+	changes, err = s.changes(src, dst, DirectorySyncDownload, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 5 {
+		t.Errorf("changes=%#v", changes)
+	}
+}
+
+func TestDirectorySyncSplitChunks(t *testing.T) {
+	chunks := splitChunks("a/big", 10, 4)
+	expect := []uploadChunk{
+		{path: "a/big.0" + partialSuffix, offset: 0, size: 4},
+		{path: "a/big.1" + partialSuffix, offset: 4, size: 4},
+		{path: "a/big.2" + partialSuffix, offset: 8, size: 2},
+	}
+
+	if !reflect.DeepEqual(chunks, expect) {
+		t.Errorf("chunks=%#v", chunks)
+	}
+
+	if chunks = splitChunks("even", 8, 4); len(chunks) != 2 || chunks[1].size != 4 {
+		t.Errorf("chunks=%#v", chunks)
+	}
+}
+
+func TestDirectorySyncLocalFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govmomi-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err = localFiles(filepath.Join(dir, "enoent"), false); !os.IsNotExist(err) {
+		t.Errorf("err=%v", err)
+	}
+
+	files, err := localFiles(filepath.Join(dir, "enoent"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("files=%v", files)
+	}
+
+	if err = os.MkdirAll(filepath.Join(dir, "a", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a/file", "a/b/file", "a/b/file" + partialSuffix} {
+		if err = ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err = localFiles(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, isDir := range map[string]bool{"a": true, "a/b": true, "a/file": false, "a/b/file": false} {
+		f, ok := files[name]
+		if !ok {
+			t.Errorf("missing %s", name)
+			continue
+		}
+		if f.dir != isDir {
+			t.Errorf("%s dir=%t", name, f.dir)
+		}
+		if !isDir && f.size != int64(len(name)) {
+			t.Errorf("%s size=%d", name, f.size)
+		}
+	}
+
+	if len(files) != 4 {
+		t.Errorf("files=%v", files)
+	}
+}
+
+func TestDirectorySyncRetry(t *testing.T) {
+	s := new(Client).NewDirectorySync()
+	s.RetryDelay = 10 * time.Millisecond
+
+	var calls int
+	start := time.Now()
+	err := s.retry(context.Background(), "test", func() error {
+		calls++
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Error("expected error")
+	}
+	if calls != s.Retries+1 {
+		t.Errorf("calls=%d", calls)
+	}
+	if d := time.Since(start); d < 70*time.Millisecond {
+		t.Errorf("retried after %s", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	s.RetryDelay = time.Hour
+	err = s.retry(ctx, "test", func() error {
+		calls++
+		cancel()
+		return errors.New("failed")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("calls=%d err=%v", calls, err)
+	}
+}
+
+func TestDirectorySyncGuestPath(t *testing.T) {
+	c := new(Client)
+
+	if p := c.guestPath("/tmp/dir/", "a/b"); p != "/tmp/dir/a/b" {
+		t.Errorf("path=%s", p)
+	}
+
+	c.GuestFamily = types.VirtualMachineGuestOsFamilyWindowsGuest
+	if p := c.guestPath(`C:\dir\`, "a/b"); p != `C:\dir\a\b` {
+		t.Errorf("path=%s", p)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin
+// +build linux darwin
+
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the owner and group IDs of the given file
+func fileOwner(info os.FileInfo) (*int32, *int32) {
+	sys, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, nil
+	}
+
+	uid, gid := int32(sys.Uid), int32(sys.Gid)
+
+	return &uid, &gid
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guest
+
+import (
+	"context"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type WindowsRegistryManager struct {
+	types.ManagedObjectReference
+
+	vm types.ManagedObjectReference
+
+	c *vim25.Client
+}
+
+func (m WindowsRegistryManager) Client() *vim25.Client {
+	return m.c
+}
+
+func (m WindowsRegistryManager) Reference() types.ManagedObjectReference {
+	return m.ManagedObjectReference
+}
+
+func (m WindowsRegistryManager) CreateKey(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, volatile bool, class string) error {
+	req := types.CreateRegistryKeyInGuest{
+		This:       m.Reference(),
+		Vm:         m.vm,
+		Auth:       auth,
+		KeyName:    key,
+		IsVolatile: volatile,
+		ClassType:  class,
+	}
+
+	_, err := methods.CreateRegistryKeyInGuest(ctx, m.c, &req)
+	return err
+}
+
+func (m WindowsRegistryManager) ListKeys(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, recursive bool, pattern string) ([]types.GuestRegKeyRecordSpec, error) {
+	req := types.ListRegistryKeysInGuest{
+		This:         m.Reference(),
+		Vm:           m.vm,
+		Auth:         auth,
+		KeyName:      key,
+		Recursive:    recursive,
+		MatchPattern: pattern,
+	}
+
+	res, err := methods.ListRegistryKeysInGuest(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, err
+}
+
+func (m WindowsRegistryManager) DeleteKey(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, recursive bool) error {
+	req := types.DeleteRegistryKeyInGuest{
+		This:      m.Reference(),
+		Vm:        m.vm,
+		Auth:      auth,
+		KeyName:   key,
+		Recursive: recursive,
+	}
+
+	_, err := methods.DeleteRegistryKeyInGuest(ctx, m.c, &req)
+	return err
+}
+
+func (m WindowsRegistryManager) SetValue(ctx context.Context, auth types.BaseGuestAuthentication, value types.GuestRegValueSpec) error {
+	req := types.SetRegistryValueInGuest{
+		This:  m.Reference(),
+		Vm:    m.vm,
+		Auth:  auth,
+		Value: value,
+	}
+
+	_, err := methods.SetRegistryValueInGuest(ctx, m.c, &req)
+	return err
+}
+
+func (m WindowsRegistryManager) ListValues(ctx context.Context, auth types.BaseGuestAuthentication, key types.GuestRegKeyNameSpec, expand bool, pattern string) ([]types.GuestRegValueSpec, error) {
+	req := types.ListRegistryValuesInGuest{
+		This:          m.Reference(),
+		Vm:            m.vm,
+		Auth:          auth,
+		KeyName:       key,
+		ExpandStrings: expand,
+		MatchPattern:  pattern,
+	}
+
+	res, err := methods.ListRegistryValuesInGuest(ctx, m.c, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Returnval, err
+}
+
+func (m WindowsRegistryManager) DeleteValue(ctx context.Context, auth types.BaseGuestAuthentication, name types.GuestRegValueNameSpec) error {
+	req := types.DeleteRegistryValueInGuest{
+		This:      m.Reference(),
+		Vm:        m.vm,
+		Auth:      auth,
+		ValueName: name,
+	}
+
+	_, err := methods.DeleteRegistryValueInGuest(ctx, m.c, &req)
+	return err
+}
@@ -0,0 +1,503 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/progress"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// DatastoreSync operations
+const (
+	DatastoreSyncMkdir    = "mkdir"
+	DatastoreSyncUpload   = "upload"
+	DatastoreSyncDownload = "download"
+	DatastoreSyncDelete   = "delete"
+)
+
+// DatastoreSync synchronizes a local directory tree with a Datastore folder, in either direction.
+// A file is transferred if it does not exist in the destination, if the size differs
+// or if the source modification time is newer than the destination modification time.
+type DatastoreSync struct {
+	Datacenter  *Datacenter
+	Datastore   *Datastore
+	FileManager *FileManager
+
+	// Delete files and directories in the destination that do not exist in the source.
+	Delete bool
+	// DryRun returns the changes that would be made, without making any changes.
+	DryRun bool
+	// Include only files that match one of these patterns, all files are included by default.
+	Include []string
+	// Exclude files and directories that match one of these patterns, taking precedence over Include.
+	Exclude []string
+	// Parallel is the number of concurrent file transfers, defaults to 1.
+	Parallel int
+	// Progress, if set, receives the aggregate progress of all file transfers.
+	Progress progress.Sinker
+}
+
+// DatastoreSyncChange describes a change made (or to be made when DryRun is true) to the destination.
+type DatastoreSyncChange struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// NewSync creates a new instance of DatastoreSync
+func (d Datastore) NewSync(dc *Datacenter) *DatastoreSync {
+	return &DatastoreSync{
+		Datacenter:  dc,
+		Datastore:   &d,
+		FileManager: NewFileManager(d.Client()),
+	}
+}
+
+type syncFile struct {
+	size  int64
+	mtime time.Time
+	dir   bool
+}
+
+// newer returns true if f should replace the destination file dst.
+func (f syncFile) newer(dst syncFile) bool {
+	if f.size != dst.size {
+		return true
+	}
+	// Datastore file times have a one second resolution
+	return f.mtime.Truncate(time.Second).After(dst.mtime.Truncate(time.Second))
+}
+
+// Upload synchronizes local directory src with Datastore directory dst.
+// On error, the changes that were made before the error are returned along with the error.
+func (s *DatastoreSync) Upload(ctx context.Context, src string, dst string) ([]DatastoreSyncChange, error) {
+	local, err := s.localFiles(src, false)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := s.remoteFiles(ctx, dst, true)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := s.changes(local, remote, DatastoreSyncUpload)
+	if s.DryRun {
+		return changes, nil
+	}
+
+	dpath := func(name string) string {
+		return s.Datastore.Path(path.Join(dst, name))
+	}
+
+	return s.apply(ctx, changes, syncOps{
+		mkdir: func(name string) error {
+			return s.FileManager.MakeDirectory(ctx, dpath(name), s.Datacenter, true)
+		},
+		transfer: func(name string, p progress.Sinker) error {
+			param := soap.DefaultUpload
+			param.Progress = p
+			return s.Datastore.UploadFile(ctx, filepath.Join(src, filepath.FromSlash(name)), path.Join(dst, name), &param)
+		},
+		remove: func(name string) error {
+			task, err := s.FileManager.DeleteDatastoreFile(ctx, dpath(name), s.Datacenter)
+			if err != nil {
+				return err
+			}
+			return task.Wait(ctx)
+		},
+	})
+}
+
+// Download synchronizes Datastore directory src with local directory dst.
+// On error, the changes that were made before the error are returned along with the error.
+func (s *DatastoreSync) Download(ctx context.Context, src string, dst string) ([]DatastoreSyncChange, error) {
+	remote, err := s.remoteFiles(ctx, src, false)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := s.localFiles(dst, true)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := s.changes(remote, local, DatastoreSyncDownload)
+	if s.DryRun {
+		return changes, nil
+	}
+
+	lpath := func(name string) string {
+		return filepath.Join(dst, filepath.FromSlash(name))
+	}
+
+	return s.apply(ctx, changes, syncOps{
+		mkdir: func(name string) error {
+			return os.MkdirAll(lpath(name), 0755)
+		},
+		transfer: func(name string, p progress.Sinker) error {
+			param := soap.DefaultDownload
+			param.Progress = p
+			err := s.Datastore.DownloadFile(ctx, path.Join(src, name), lpath(name), &param)
+			if err != nil {
+				return err
+			}
+			// Preserve the modification time, such that the file is not transferred again
+			mtime := remote[name].mtime
+			return os.Chtimes(lpath(name), mtime, mtime)
+		},
+		remove: func(name string) error {
+			return os.Remove(lpath(name))
+		},
+	})
+}
+
+// excluded returns true if name or any of its parent directories match an Exclude pattern.
+func (s *DatastoreSync) excluded(name string) bool {
+	for p := name; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if matchAny(s.Exclude, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// included returns true if the file name is to be synchronized.
+func (s *DatastoreSync) included(name string, f syncFile) bool {
+	if s.excluded(name) {
+		return false
+	}
+	if f.dir || len(s.Include) == 0 {
+		return true
+	}
+	return matchAny(s.Include, name)
+}
+
+// matchAny returns true if the relative path name or its base name match one of the patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// changes computes the changes to sync dst with src, using op for file transfers.
+// Directories are created first, parent before child, followed by transfers and deletes, child before parent.
+func (s *DatastoreSync) changes(src, dst map[string]syncFile, op string) []DatastoreSyncChange {
+	var mkdirs, transfers, deletes []DatastoreSyncChange
+
+	for name, f := range src {
+		if !s.included(name, f) {
+			continue
+		}
+
+		d, exists := dst[name]
+
+		if f.dir {
+			if !exists {
+				mkdirs = append(mkdirs, DatastoreSyncChange{DatastoreSyncMkdir, name, 0})
+			}
+			continue
+		}
+
+		if !exists || d.dir || f.newer(d) {
+			transfers = append(transfers, DatastoreSyncChange{op, name, f.size})
+		}
+	}
+
+	if s.Delete {
+		// A directory is only deleted if none of its files are kept,
+		// such that excluded files and files that are not included are preserved.
+		keep := make(map[string]bool)
+		for name, f := range dst {
+			_, exists := src[name]
+			if exists || !s.included(name, f) {
+				for p := path.Dir(name); p != "."; p = path.Dir(p) {
+					keep[p] = true
+				}
+			}
+		}
+
+		for name, f := range dst {
+			if _, exists := src[name]; exists || keep[name] || !s.included(name, f) {
+				continue
+			}
+			deletes = append(deletes, DatastoreSyncChange{DatastoreSyncDelete, name, f.size})
+		}
+	}
+
+	byPath := func(c []DatastoreSyncChange) {
+		sort.Slice(c, func(i, j int) bool {
+			return c[i].Path < c[j].Path
+		})
+	}
+
+	byPath(mkdirs)
+	byPath(transfers)
+	byPath(deletes)
+
+	// Delete files before their parent directory
+	for i, j := 0, len(deletes)-1; i < j; i, j = i+1, j-1 {
+		deletes[i], deletes[j] = deletes[j], deletes[i]
+	}
+
+	return append(append(mkdirs, transfers...), deletes...)
+}
+
+type syncOps struct {
+	mkdir    func(string) error
+	transfer func(string, progress.Sinker) error
+	remove   func(string) error
+}
+
+// apply makes the given changes, running file transfers in parallel.
+// The changes that were made are returned, along with the first error, if any.
+func (s *DatastoreSync) apply(ctx context.Context, changes []DatastoreSyncChange, ops syncOps) ([]DatastoreSyncChange, error) {
+	var transfers []DatastoreSyncChange
+	var mu sync.Mutex
+	done := make(map[string]bool)
+
+	applied := func(err error) ([]DatastoreSyncChange, error) {
+		var res []DatastoreSyncChange
+		for _, c := range changes {
+			if done[c.Path] {
+				res = append(res, c)
+			}
+		}
+		return res, err
+	}
+
+	for _, c := range changes {
+		switch c.Op {
+		case DatastoreSyncMkdir:
+			if err := ops.mkdir(c.Path); err != nil {
+				return applied(err)
+			}
+			done[c.Path] = true
+		case DatastoreSyncUpload, DatastoreSyncDownload:
+			transfers = append(transfers, c)
+		}
+	}
+
+	err := s.transfer(ctx, transfers, func(name string, p progress.Sinker) error {
+		if err := ops.transfer(name, p); err != nil {
+			return err
+		}
+		mu.Lock()
+		done[name] = true
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return applied(err)
+	}
+
+	for _, c := range changes {
+		if c.Op == DatastoreSyncDelete {
+			if err := ops.remove(c.Path); err != nil {
+				return applied(err)
+			}
+			done[c.Path] = true
+		}
+	}
+
+	return changes, nil
+}
+
+func (s *DatastoreSync) transfer(ctx context.Context, changes []DatastoreSyncChange, fn func(string, progress.Sinker) error) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	n := s.Parallel
+	if n <= 0 {
+		n = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var agg *progress.Transfers
+	if s.Progress != nil {
+		var size int64
+		for _, c := range changes {
+			size += c.Size
+		}
+		agg = progress.NewTransfers(s.Progress, len(changes), size)
+		defer agg.Done()
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	limit := make(chan struct{}, n)
+
+	for _, c := range changes {
+		c := c
+		limit <- struct{}{}
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			var sinker progress.Sinker
+			if agg != nil {
+				sinker = agg.Sinker(c.Size)
+			}
+
+			if err := fn(c.Path, sinker); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s %s: %s", c.Op, c.Path, err))
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) != 0 {
+		return errs[0]
+	}
+
+	return ctx.Err()
+}
+
+// localFiles returns the files in the local directory dir, keyed by relative path.
+// Excluded files are included, such that changes can preserve their parent directories.
+// If dir does not exist, an empty map is returned when missing is true, otherwise an error.
+func (s *DatastoreSync) localFiles(dir string, missing bool) (map[string]syncFile, error) {
+	files := make(map[string]syncFile)
+
+	err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			if missing && os.IsNotExist(err) && name == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		files[rel] = syncFile{
+			size:  info.Size(),
+			mtime: info.ModTime(),
+			dir:   info.IsDir(),
+		}
+
+		if info.IsDir() && s.excluded(rel) {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// remoteFiles returns the files in the Datastore directory dir, keyed by relative path.
+// Excluded files are included, such that changes can preserve their parent directories.
+// If dir does not exist, an empty map is returned when missing is true, otherwise an error.
+func (s *DatastoreSync) remoteFiles(ctx context.Context, dir string, missing bool) (map[string]syncFile, error) {
+	files := make(map[string]syncFile)
+
+	b, err := s.Datastore.Browser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := types.HostDatastoreBrowserSearchSpec{
+		Details: &types.FileQueryFlags{
+			FileType:     true,
+			FileSize:     true,
+			Modification: true,
+			FileOwner:    types.NewBool(true),
+		},
+		MatchPattern: []string{"*"},
+	}
+
+	task, err := b.SearchDatastoreSubFolders(ctx, s.Datastore.Path(dir), &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		if missing && types.IsFileNotFound(err) {
+			return files, nil
+		}
+		return nil, err
+	}
+
+	root := strings.Trim(path.Clean("/"+dir), "/")
+
+	for _, r := range info.Result.(types.ArrayOfHostDatastoreBrowserSearchResults).HostDatastoreBrowserSearchResults {
+		var folder DatastorePath
+		folder.FromString(r.FolderPath)
+		folder.Path = strings.Trim(path.Clean("/"+folder.Path), "/")
+
+		for _, f := range r.File {
+			fi := f.GetFileInfo()
+
+			rel := strings.TrimPrefix(path.Join(folder.Path, fi.Path), root)
+			rel = strings.TrimPrefix(rel, "/")
+
+			sf := syncFile{size: fi.FileSize}
+			if fi.Modification != nil {
+				sf.mtime = *fi.Modification
+			}
+			if _, ok := f.(*types.FolderFileInfo); ok {
+				sf.dir = true
+			}
+
+			files[rel] = sf
+		}
+	}
+
+	return files, nil
+}
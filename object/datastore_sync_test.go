@@ -0,0 +1,180 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package object_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestDatastoreSync(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+
+		dc, err := finder.DefaultDatacenter(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+
+		ds, err := finder.DefaultDatastore(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		src := t.TempDir()
+		dst := t.TempDir()
+
+		files := map[string]string{
+			"a/one":     "one",
+			"a/b/two":   "two",
+			"a/b/x.tmp": "tmp",
+		}
+		for name, data := range files {
+			name = filepath.Join(src, filepath.FromSlash(name))
+			if err = os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(name, []byte(data), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sync := ds.NewSync(dc)
+		sync.Exclude = []string{"*.tmp"}
+		sync.Parallel = 2
+
+		changes, err := sync.Upload(ctx, src, "sync")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect := []object.DatastoreSyncChange{
+			{Op: object.DatastoreSyncMkdir, Path: "a"},
+			{Op: object.DatastoreSyncMkdir, Path: "a/b"},
+			{Op: object.DatastoreSyncUpload, Path: "a/b/two", Size: 3},
+			{Op: object.DatastoreSyncUpload, Path: "a/one", Size: 3},
+		}
+		if !reflect.DeepEqual(changes, expect) {
+			t.Errorf("changes=%#v", changes)
+		}
+
+		changes, err = sync.Upload(ctx, src, "sync")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("changes=%#v", changes)
+		}
+
+		if err = os.Remove(filepath.Join(src, "a", "one")); err != nil {
+			t.Fatal(err)
+		}
+
+		sync.Delete = true
+		changes, err = sync.Upload(ctx, src, "sync")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect = []object.DatastoreSyncChange{
+			{Op: object.DatastoreSyncDelete, Path: "a/one", Size: 3},
+		}
+		if !reflect.DeepEqual(changes, expect) {
+			t.Errorf("changes=%#v", changes)
+		}
+
+		changes, err = sync.Download(ctx, "sync", dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 3 {
+			t.Errorf("changes=%#v", changes)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dst, "a", "b", "two"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != "two" {
+			t.Errorf("data=%q", data)
+		}
+
+		changes, err = sync.Download(ctx, "sync", dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("changes=%#v", changes)
+		}
+
+		// a missing source is an error rather than an empty tree, which would delete all files
+		if _, err = sync.Upload(ctx, filepath.Join(src, "enoent"), "sync"); err == nil {
+			t.Error("expected error")
+		}
+		if _, err = sync.Download(ctx, "enoent", dst); err == nil {
+			t.Error("expected error")
+		}
+
+		changes, err = sync.Download(ctx, "sync", dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("changes=%#v", changes)
+		}
+
+		// excluded files are not deleted, nor is their parent directory
+		for _, name := range []string{"c/x.tmp", "c/d/three"} {
+			name = filepath.Join(dst, filepath.FromSlash(name))
+			if err = os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err = os.WriteFile(name, []byte("tmp"), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		changes, err = sync.Download(ctx, "sync", dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var deletes []string
+		for _, c := range changes {
+			if c.Op == object.DatastoreSyncDelete {
+				deletes = append(deletes, c.Path)
+			}
+		}
+		if !reflect.DeepEqual(deletes, []string{"c/d/three", "c/d"}) || len(changes) != len(deletes) {
+			t.Errorf("changes=%#v", changes)
+		}
+		if _, err = os.Stat(filepath.Join(dst, "c", "x.tmp")); err != nil {
+			t.Error(err)
+		}
+		if _, err = os.Stat(filepath.Join(dst, "c", "d")); !os.IsNotExist(err) {
+			t.Errorf("expected c/d to be deleted: %v", err)
+		}
+	})
+}
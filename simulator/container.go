@@ -121,12 +121,23 @@ func (c *container) inspect(vm *VirtualMachine) error {
 }
 
 func (c *container) prepareGuestOperation(
+	ctx *Context,
 	vm *VirtualMachine,
 	auth types.BaseGuestAuthentication) types.BaseMethodFault {
 
 	if c.id == "" {
 		return new(types.GuestOperationsUnavailable)
 	}
+	return validateGuestOperation(ctx, vm, auth)
+}
+
+// validateGuestOperation checks the VM power state and guest credentials,
+// for guest operations that do not require a container.
+func validateGuestOperation(
+	ctx *Context,
+	vm *VirtualMachine,
+	auth types.BaseGuestAuthentication) types.BaseMethodFault {
+
 	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
 		return &types.InvalidPowerState{
 			RequestedState: types.VirtualMachinePowerStatePoweredOn,
@@ -138,6 +149,18 @@ func (c *container) prepareGuestOperation(
 		if creds.Username == "" || creds.Password == "" {
 			return new(types.InvalidGuestLogin)
 		}
+	case *types.SAMLTokenAuthentication:
+		if creds.Token == "" {
+			return new(types.InvalidGuestLogin)
+		}
+	case *types.TicketedSessionAuthentication:
+		var ok bool
+		ctx.WithLock(vm, func() {
+			_, ok = vm.tickets[creds.Ticket]
+		})
+		if !ok {
+			return new(types.InvalidGuestLogin)
+		}
 	default:
 		return new(types.InvalidGuestLogin)
 	}
@@ -429,7 +452,7 @@ func (c *container) remove(vm *VirtualMachine) {
 }
 
 func (c *container) exec(ctx *Context, vm *VirtualMachine, auth types.BaseGuestAuthentication, args []string) (string, types.BaseMethodFault) {
-	fault := vm.run.prepareGuestOperation(vm, auth)
+	fault := vm.run.prepareGuestOperation(ctx, vm, auth)
 	if fault != nil {
 		return "", fault
 	}
@@ -0,0 +1,283 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"sync"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// guestAliasStore is the in-memory alias store of a VM.
+type guestAliasStore struct {
+	aliases map[string][]types.GuestAliases // by username
+	mapped  []types.GuestMappedAliases
+}
+
+type GuestAliasManager struct {
+	mo.GuestAliasManager
+
+	mu    sync.Mutex
+	store map[types.ManagedObjectReference]*guestAliasStore
+}
+
+// vmStore validates the guest operation request and returns the alias store of the given VM.
+func (m *GuestAliasManager) vmStore(ctx *Context, ref types.ManagedObjectReference, auth types.BaseGuestAuthentication) (*guestAliasStore, types.BaseMethodFault) {
+	vm := ctx.Map.Get(ref).(*VirtualMachine)
+
+	if fault := validateGuestOperation(ctx, vm, auth); fault != nil {
+		return nil, fault
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.store == nil {
+		m.store = make(map[types.ManagedObjectReference]*guestAliasStore)
+	}
+
+	s, ok := m.store[ref]
+	if !ok {
+		s = &guestAliasStore{aliases: make(map[string][]types.GuestAliases)}
+		m.store[ref] = s
+	}
+
+	return s, nil
+}
+
+func (*GuestAliasManager) PutObject(mo.Reference) {}
+
+func (*GuestAliasManager) UpdateObject(mo.Reference, []types.PropertyChange) {}
+
+func (m *GuestAliasManager) RemoveObject(_ *Context, ref types.ManagedObjectReference) {
+	m.mu.Lock()
+	delete(m.store, ref)
+	m.mu.Unlock()
+}
+
+func guestAuthSubjectEqual(a, b types.BaseGuestAuthSubject) bool {
+	switch x := a.(type) {
+	case *types.GuestAuthAnySubject:
+		_, ok := b.(*types.GuestAuthAnySubject)
+		return ok
+	case *types.GuestAuthNamedSubject:
+		y, ok := b.(*types.GuestAuthNamedSubject)
+		return ok && x.Name == y.Name
+	}
+	return false
+}
+
+func (s *guestAliasStore) find(username, cert string) int {
+	for i, a := range s.aliases[username] {
+		if a.Base64Cert == cert {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *guestAliasStore) findMapped(cert string) int {
+	for i, a := range s.mapped {
+		if a.Base64Cert == cert {
+			return i
+		}
+	}
+	return -1
+}
+
+// unmap removes subject from the certificate mapping, or all subjects if subject is nil.
+func (s *guestAliasStore) unmap(username, cert string, subject types.BaseGuestAuthSubject) {
+	i := s.findMapped(cert)
+	if i < 0 || s.mapped[i].Username != username {
+		return
+	}
+
+	m := &s.mapped[i]
+	var subjects []types.BaseGuestAuthSubject
+	for _, x := range m.Subjects {
+		if subject != nil && !guestAuthSubjectEqual(x, subject) {
+			subjects = append(subjects, x)
+		}
+	}
+	m.Subjects = subjects
+
+	if len(m.Subjects) == 0 {
+		s.mapped = append(s.mapped[:i], s.mapped[i+1:]...)
+	}
+}
+
+func (m *GuestAliasManager) AddGuestAlias(ctx *Context, req *types.AddGuestAlias) soap.HasFault {
+	body := new(methods.AddGuestAliasBody)
+
+	s, fault := m.vmStore(ctx, req.Vm, req.Auth)
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	if req.Username == "" || req.Base64Cert == "" || req.AliasInfo.Subject == nil {
+		body.Fault_ = Fault("", new(types.InvalidArgument))
+		return body
+	}
+
+	if req.MapCert {
+		if i := s.findMapped(req.Base64Cert); i >= 0 && s.mapped[i].Username != req.Username {
+			body.Fault_ = Fault("", new(types.GuestMultipleMappings))
+			return body
+		}
+	}
+
+	i := s.find(req.Username, req.Base64Cert)
+	if i < 0 {
+		s.aliases[req.Username] = append(s.aliases[req.Username], types.GuestAliases{Base64Cert: req.Base64Cert})
+		i = len(s.aliases[req.Username]) - 1
+	}
+
+	aliases := &s.aliases[req.Username][i]
+	exists := false
+	for _, a := range aliases.Aliases {
+		if guestAuthSubjectEqual(a.Subject, req.AliasInfo.Subject) {
+			exists = true
+		}
+	}
+	if !exists {
+		aliases.Aliases = append(aliases.Aliases, req.AliasInfo)
+	}
+
+	if req.MapCert {
+		i := s.findMapped(req.Base64Cert)
+		if i < 0 {
+			s.mapped = append(s.mapped, types.GuestMappedAliases{Base64Cert: req.Base64Cert, Username: req.Username})
+			i = len(s.mapped) - 1
+		}
+		mapped := &s.mapped[i]
+		exists = false
+		for _, x := range mapped.Subjects {
+			if guestAuthSubjectEqual(x, req.AliasInfo.Subject) {
+				exists = true
+			}
+		}
+		if !exists {
+			mapped.Subjects = append(mapped.Subjects, req.AliasInfo.Subject)
+		}
+	}
+
+	body.Res = new(types.AddGuestAliasResponse)
+
+	return body
+}
+
+func (m *GuestAliasManager) ListGuestAliases(ctx *Context, req *types.ListGuestAliases) soap.HasFault {
+	body := new(methods.ListGuestAliasesBody)
+
+	s, fault := m.vmStore(ctx, req.Vm, req.Auth)
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = &types.ListGuestAliasesResponse{
+		Returnval: s.aliases[req.Username],
+	}
+
+	return body
+}
+
+func (m *GuestAliasManager) ListGuestMappedAliases(ctx *Context, req *types.ListGuestMappedAliases) soap.HasFault {
+	body := new(methods.ListGuestMappedAliasesBody)
+
+	s, fault := m.vmStore(ctx, req.Vm, req.Auth)
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = &types.ListGuestMappedAliasesResponse{
+		Returnval: s.mapped,
+	}
+
+	return body
+}
+
+func (m *GuestAliasManager) RemoveGuestAlias(ctx *Context, req *types.RemoveGuestAlias) soap.HasFault {
+	body := new(methods.RemoveGuestAliasBody)
+
+	s, fault := m.vmStore(ctx, req.Vm, req.Auth)
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	i := s.find(req.Username, req.Base64Cert)
+	if i < 0 {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "base64Cert"})
+		return body
+	}
+
+	aliases := &s.aliases[req.Username][i]
+	found := false
+	var infos []types.GuestAuthAliasInfo
+	for _, a := range aliases.Aliases {
+		if guestAuthSubjectEqual(a.Subject, req.Subject) {
+			found = true
+			continue
+		}
+		infos = append(infos, a)
+	}
+
+	if !found {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "subject"})
+		return body
+	}
+
+	aliases.Aliases = infos
+	if len(infos) == 0 {
+		s.aliases[req.Username] = append(s.aliases[req.Username][:i], s.aliases[req.Username][i+1:]...)
+	}
+
+	s.unmap(req.Username, req.Base64Cert, req.Subject)
+
+	body.Res = new(types.RemoveGuestAliasResponse)
+
+	return body
+}
+
+func (m *GuestAliasManager) RemoveGuestAliasByCert(ctx *Context, req *types.RemoveGuestAliasByCert) soap.HasFault {
+	body := new(methods.RemoveGuestAliasByCertBody)
+
+	s, fault := m.vmStore(ctx, req.Vm, req.Auth)
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	i := s.find(req.Username, req.Base64Cert)
+	if i < 0 {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "base64Cert"})
+		return body
+	}
+
+	s.aliases[req.Username] = append(s.aliases[req.Username][:i], s.aliases[req.Username][i+1:]...)
+	s.unmap(req.Username, req.Base64Cert, nil)
+
+	body.Res = new(types.RemoveGuestAliasByCertResponse)
+
+	return body
+}
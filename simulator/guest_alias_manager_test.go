@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestGuestAliasManager(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := guest.NewOperationsManager(c, vm.Reference()).AliasManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+		cert := "MIIC-fake-cert"
+		subject := &types.GuestAuthNamedSubject{Name: "administrator@vsphere.local"}
+		info := types.GuestAuthAliasInfo{Subject: subject, Comment: "test"}
+
+		if err = m.AddAlias(ctx, auth, "root", true, cert, info); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.AddAlias(ctx, auth, "other", true, cert, info); err == nil {
+			t.Error("expected GuestMultipleMappings error")
+		}
+
+		if err = m.AddAlias(ctx, auth, "root", false, cert, types.GuestAuthAliasInfo{Subject: new(types.GuestAuthAnySubject)}); err != nil {
+			t.Fatal(err)
+		}
+
+		aliases, err := m.ListAliases(ctx, auth, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(aliases) != 1 || len(aliases[0].Aliases) != 2 {
+			t.Errorf("aliases=%#v", aliases)
+		}
+
+		mapped, err := m.ListMappedAliases(ctx, auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mapped) != 1 || mapped[0].Username != "root" || len(mapped[0].Subjects) != 1 {
+			t.Errorf("mapped=%#v", mapped)
+		}
+
+		if err = m.RemoveAlias(ctx, auth, "root", cert, subject); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.RemoveAlias(ctx, auth, "root", cert, subject); err == nil {
+			t.Error("expected error")
+		}
+
+		mapped, err = m.ListMappedAliases(ctx, auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mapped) != 0 {
+			t.Errorf("mapped=%#v", mapped)
+		}
+
+		if err = m.RemoveAliasByCert(ctx, auth, "root", cert); err != nil {
+			t.Fatal(err)
+		}
+
+		aliases, err = m.ListAliases(ctx, auth, "root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(aliases) != 0 {
+			t.Errorf("aliases=%#v", aliases)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+type GuestAuthManager struct {
+	mo.GuestAuthManager
+}
+
+func (m *GuestAuthManager) AcquireCredentialsInGuest(ctx *Context, req *types.AcquireCredentialsInGuest) soap.HasFault {
+	body := new(methods.AcquireCredentialsInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := validateGuestOperation(ctx, vm, req.RequestedAuth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	ticket := uuid.New().String()
+	username := guestAuthUsername(ctx, vm, req.RequestedAuth)
+
+	ctx.WithLock(vm, func() {
+		if vm.tickets == nil {
+			vm.tickets = make(map[string]string)
+		}
+		vm.tickets[ticket] = username
+	})
+
+	body.Res = &types.AcquireCredentialsInGuestResponse{
+		Returnval: &types.TicketedSessionAuthentication{
+			GuestAuthentication: types.GuestAuthentication{
+				InteractiveSession: req.RequestedAuth.GetGuestAuthentication().InteractiveSession,
+			},
+			Ticket: ticket,
+		},
+	}
+
+	return body
+}
+
+func (m *GuestAuthManager) ReleaseCredentialsInGuest(ctx *Context, req *types.ReleaseCredentialsInGuest) soap.HasFault {
+	body := new(methods.ReleaseCredentialsInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	auth, ok := req.Auth.(*types.TicketedSessionAuthentication)
+	if !ok {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "auth"})
+		return body
+	}
+
+	ctx.WithLock(vm, func() {
+		delete(vm.tickets, auth.Ticket)
+	})
+
+	body.Res = new(types.ReleaseCredentialsInGuestResponse)
+
+	return body
+}
+
+func (m *GuestAuthManager) ValidateCredentialsInGuest(ctx *Context, req *types.ValidateCredentialsInGuest) soap.HasFault {
+	body := new(methods.ValidateCredentialsInGuestBody)
+
+	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
+
+	if fault := validateGuestOperation(ctx, vm, req.Auth); fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = new(types.ValidateCredentialsInGuestResponse)
+
+	return body
+}
+
+// guestAuthUsername returns the guest user name of the given (validated) credentials.
+func guestAuthUsername(ctx *Context, vm *VirtualMachine, auth types.BaseGuestAuthentication) string {
+	switch creds := auth.(type) {
+	case *types.NamePasswordAuthentication:
+		return creds.Username
+	case *types.SAMLTokenAuthentication:
+		return creds.Username
+	case *types.TicketedSessionAuthentication:
+		var username string
+		ctx.WithLock(vm, func() {
+			username = vm.tickets[creds.Ticket]
+		})
+		return username
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestGuestAuthManager(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := guest.NewOperationsManager(c, vm.Reference()).AuthManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = m.AcquireCredentials(ctx, &types.NamePasswordAuthentication{Username: "user"}, 0); err == nil {
+			t.Error("expected InvalidGuestLogin")
+		}
+
+		saml := &types.SAMLTokenAuthentication{Token: "<saml/>", Username: "root"}
+		if err = m.ValidateCredentials(ctx, saml); err != nil {
+			t.Fatal(err)
+		}
+
+		auth, err := m.AcquireCredentials(ctx, saml, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := auth.(*types.TicketedSessionAuthentication); !ok {
+			t.Fatalf("auth=%T", auth)
+		}
+
+		if err = m.ValidateCredentials(ctx, auth); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.ReleaseCredentials(ctx, auth); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.ValidateCredentials(ctx, auth); err == nil {
+			t.Error("expected InvalidGuestLogin")
+		}
+	})
+}
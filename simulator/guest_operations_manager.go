@@ -57,6 +57,38 @@ func (m *GuestOperationsManager) init(r *Registry) {
 	pm.Self = *m.ProcessManager
 	pm.Manager = process.NewManager()
 	r.Put(pm)
+
+	rm := new(GuestWindowsRegistryManager)
+	if m.GuestWindowsRegistryManager == nil {
+		m.GuestWindowsRegistryManager = &types.ManagedObjectReference{
+			Type:  "GuestWindowsRegistryManager",
+			Value: "guestOperationsWindowsRegistryManager",
+		}
+	}
+	rm.Self = *m.GuestWindowsRegistryManager
+	r.Put(rm)
+	r.AddHandler(rm) // Remove the registry of destroyed VMs
+
+	gm := new(GuestAuthManager)
+	if m.AuthManager == nil {
+		m.AuthManager = &types.ManagedObjectReference{
+			Type:  "GuestAuthManager",
+			Value: "guestOperationsAuthManager",
+		}
+	}
+	gm.Self = *m.AuthManager
+	r.Put(gm)
+
+	am := new(GuestAliasManager)
+	if m.AliasManager == nil {
+		m.AliasManager = &types.ManagedObjectReference{
+			Type:  "GuestAliasManager",
+			Value: "guestOperationsAliasManager",
+		}
+	}
+	am.Self = *m.AliasManager
+	r.Put(am)
+	r.AddHandler(am) // Remove the alias store of destroyed VMs
 }
 
 type GuestFileManager struct {
@@ -79,7 +111,7 @@ func (m *GuestFileManager) InitiateFileTransferToGuest(ctx *Context, req *types.
 	body := new(methods.InitiateFileTransferToGuestBody)
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
-	err := vm.run.prepareGuestOperation(vm, req.Auth)
+	err := vm.run.prepareGuestOperation(ctx, vm, req.Auth)
 	if err != nil {
 		body.Fault_ = Fault("", err)
 		return body
@@ -96,7 +128,7 @@ func (m *GuestFileManager) InitiateFileTransferFromGuest(ctx *Context, req *type
 	body := new(methods.InitiateFileTransferFromGuestBody)
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
-	err := vm.run.prepareGuestOperation(vm, req.Auth)
+	err := vm.run.prepareGuestOperation(ctx, vm, req.Auth)
 	if err != nil {
 		body.Fault_ = Fault("", err)
 		return body
@@ -122,13 +154,13 @@ func (m *GuestProcessManager) StartProgramInGuest(ctx *Context, req *types.Start
 	body := new(methods.StartProgramInGuestBody)
 
 	spec := req.Spec.(*types.GuestProgramSpec)
-	auth := req.Auth.(*types.NamePasswordAuthentication)
 
 	vm := ctx.Map.Get(req.Vm).(*VirtualMachine)
 
-	fault := vm.run.prepareGuestOperation(vm, auth)
+	fault := vm.run.prepareGuestOperation(ctx, vm, req.Auth)
 	if fault != nil {
 		body.Fault_ = Fault("", fault)
+		return body
 	}
 
 	args := []string{"exec"}
@@ -152,7 +184,7 @@ func (m *GuestProcessManager) StartProgramInGuest(ctx *Context, req *types.Start
 	}
 
 	proc := process.New()
-	proc.Owner = auth.Username
+	proc.Owner = guestAuthUsername(ctx, vm, req.Auth)
 
 	pid, err := m.Start(start, proc)
 	if err != nil {
@@ -17,11 +17,15 @@ limitations under the License.
 package simulator
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"strings"
 	"testing"
 
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -76,3 +80,58 @@ func TestFileInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestGuestOperationsRemoveVM(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		om := guest.NewOperationsManager(c, vm.Reference())
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+
+		rm, err := om.WindowsRegistryManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := types.GuestRegKeyNameSpec{RegistryPath: `HKEY_LOCAL_MACHINE\SOFTWARE`, WowBitness: string(types.GuestRegKeyWowSpecWOWNative)}
+		if err = rm.CreateKey(ctx, auth, key, false, ""); err != nil {
+			t.Fatal(err)
+		}
+
+		am, err := om.AliasManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = am.ListMappedAliases(ctx, auth); err != nil {
+			t.Fatal(err)
+		}
+
+		registry := Map.Get(rm.Reference()).(*GuestWindowsRegistryManager)
+		aliases := Map.Get(am.Reference()).(*GuestAliasManager)
+
+		if len(registry.registry) != 1 || len(aliases.store) != 1 {
+			t.Fatalf("registry=%d aliases=%d", len(registry.registry), len(aliases.store))
+		}
+
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		task, err = vm.Destroy(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(registry.registry) != 0 || len(aliases.store) != 0 {
+			t.Errorf("registry=%d aliases=%d", len(registry.registry), len(aliases.store))
+		}
+	})
+}
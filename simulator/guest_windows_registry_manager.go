@@ -0,0 +1,369 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Windows system error codes used by GuestRegistryFault
+const (
+	errorFileNotFound  = 2
+	errorAccessDenied  = 5
+	errorAlreadyExists = 183
+)
+
+var guestRegistryHives = []string{
+	"HKEY_CLASSES_ROOT",
+	"HKEY_CURRENT_CONFIG",
+	"HKEY_CURRENT_USER",
+	"HKEY_LOCAL_MACHINE",
+	"HKEY_USERS",
+}
+
+type guestRegistryKey struct {
+	spec     types.GuestRegKeySpec
+	volatile bool
+	values   map[string]types.GuestRegValueSpec
+}
+
+// guestRegistry is the in-memory registry of a VM, keys and value names are case-insensitive.
+// The WOW bitness of key names is ignored, all views share the same keys.
+type guestRegistry map[string]*guestRegistryKey
+
+func newGuestRegistry() guestRegistry {
+	r := make(guestRegistry)
+	for _, hive := range guestRegistryHives {
+		r.add(types.GuestRegKeyNameSpec{RegistryPath: hive}, "", false)
+	}
+	return r
+}
+
+func guestRegistryPath(p string) string {
+	return strings.ToLower(strings.TrimRight(p, `\`))
+}
+
+func guestRegistryParent(p string) string {
+	if i := strings.LastIndex(p, `\`); i > 0 {
+		return p[:i]
+	}
+	return ""
+}
+
+func (r guestRegistry) add(name types.GuestRegKeyNameSpec, class string, volatile bool) {
+	name.RegistryPath = strings.TrimRight(name.RegistryPath, `\`)
+
+	r[guestRegistryPath(name.RegistryPath)] = &guestRegistryKey{
+		spec: types.GuestRegKeySpec{
+			KeyName:     name,
+			ClassType:   class,
+			LastWritten: time.Now(),
+		},
+		volatile: volatile,
+		values:   make(map[string]types.GuestRegValueSpec),
+	}
+}
+
+// children returns the subkeys of path p, sorted by name.
+func (r guestRegistry) children(p string, recursive bool) []*guestRegistryKey {
+	var keys []*guestRegistryKey
+	prefix := p + `\`
+
+	for name, key := range r {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if !recursive && strings.Contains(name[len(prefix):], `\`) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].spec.KeyName.RegistryPath < keys[j].spec.KeyName.RegistryPath
+	})
+
+	return keys
+}
+
+func (r guestRegistry) lookup(name types.GuestRegKeyNameSpec) (*guestRegistryKey, types.BaseMethodFault) {
+	key, ok := r[guestRegistryPath(name.RegistryPath)]
+	if !ok {
+		return nil, guestRegistryKeyFault(new(types.GuestRegistryKeyInvalid), name, errorFileNotFound)
+	}
+	return key, nil
+}
+
+func guestRegistryKeyFault(fault types.BaseGuestRegistryKeyFault, name types.GuestRegKeyNameSpec, code int64) types.BaseMethodFault {
+	f := fault.GetGuestRegistryKeyFault()
+	f.KeyName = name.RegistryPath
+	f.WindowsSystemErrorCode = code
+	return fault.(types.BaseMethodFault)
+}
+
+func guestRegistryMatch(pattern string) (*regexp.Regexp, types.BaseMethodFault) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &types.InvalidArgument{InvalidProperty: "matchPattern"}
+	}
+	return re, nil
+}
+
+type GuestWindowsRegistryManager struct {
+	mo.GuestWindowsRegistryManager
+
+	mu       sync.Mutex
+	registry map[types.ManagedObjectReference]guestRegistry
+}
+
+// vmRegistry validates the guest operation request and returns the registry of the given VM.
+func (m *GuestWindowsRegistryManager) vmRegistry(ctx *Context, ref types.ManagedObjectReference, auth types.BaseGuestAuthentication) (guestRegistry, types.BaseMethodFault) {
+	vm := ctx.Map.Get(ref).(*VirtualMachine)
+
+	if fault := validateGuestOperation(ctx, vm, auth); fault != nil {
+		return nil, fault
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.registry == nil {
+		m.registry = make(map[types.ManagedObjectReference]guestRegistry)
+	}
+
+	r, ok := m.registry[ref]
+	if !ok {
+		r = newGuestRegistry()
+		m.registry[ref] = r
+	}
+
+	return r, nil
+}
+
+func (*GuestWindowsRegistryManager) PutObject(mo.Reference) {}
+
+func (*GuestWindowsRegistryManager) UpdateObject(mo.Reference, []types.PropertyChange) {}
+
+func (m *GuestWindowsRegistryManager) RemoveObject(_ *Context, ref types.ManagedObjectReference) {
+	m.mu.Lock()
+	delete(m.registry, ref)
+	m.mu.Unlock()
+}
+
+func (m *GuestWindowsRegistryManager) CreateRegistryKeyInGuest(ctx *Context, req *types.CreateRegistryKeyInGuest) soap.HasFault {
+	body := new(methods.CreateRegistryKeyInGuestBody)
+
+	r, fault := m.vmRegistry(ctx, req.Vm, req.Auth)
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	p := guestRegistryPath(req.KeyName.RegistryPath)
+
+	if _, ok := r[p]; ok {
+		body.Fault_ = Fault("", guestRegistryKeyFault(new(types.GuestRegistryKeyAlreadyExists), req.KeyName, errorAlreadyExists))
+		return body
+	}
+
+	parent, ok := r[guestRegistryParent(p)]
+	if !ok {
+		body.Fault_ = Fault("", guestRegistryKeyFault(new(types.GuestRegistryKeyInvalid), req.KeyName, errorFileNotFound))
+		return body
+	}
+
+	if parent.volatile && !req.IsVolatile {
+		body.Fault_ = Fault("", guestRegistryKeyFault(new(types.GuestRegistryKeyParentVolatile), req.KeyName, 0))
+		return body
+	}
+
+	r.add(req.KeyName, req.ClassType, req.IsVolatile)
+
+	body.Res = new(types.CreateRegistryKeyInGuestResponse)
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) ListRegistryKeysInGuest(ctx *Context, req *types.ListRegistryKeysInGuest) soap.HasFault {
+	body := new(methods.ListRegistryKeysInGuestBody)
+
+	r, fault := m.vmRegistry(ctx, req.Vm, req.Auth)
+	if fault == nil {
+		_, fault = r.lookup(req.KeyName)
+	}
+	var re *regexp.Regexp
+	if fault == nil {
+		re, fault = guestRegistryMatch(req.MatchPattern)
+	}
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = new(types.ListRegistryKeysInGuestResponse)
+
+	for _, key := range r.children(guestRegistryPath(req.KeyName.RegistryPath), req.Recursive) {
+		p := key.spec.KeyName.RegistryPath
+		if re != nil && !re.MatchString(p[strings.LastIndex(p, `\`)+1:]) {
+			continue
+		}
+		body.Res.Returnval = append(body.Res.Returnval, types.GuestRegKeyRecordSpec{Key: key.spec})
+	}
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) DeleteRegistryKeyInGuest(ctx *Context, req *types.DeleteRegistryKeyInGuest) soap.HasFault {
+	body := new(methods.DeleteRegistryKeyInGuestBody)
+
+	r, fault := m.vmRegistry(ctx, req.Vm, req.Auth)
+	if fault == nil {
+		_, fault = r.lookup(req.KeyName)
+	}
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	p := guestRegistryPath(req.KeyName.RegistryPath)
+
+	if guestRegistryParent(p) == "" {
+		body.Fault_ = Fault("", &types.GuestPermissionDenied{})
+		return body
+	}
+
+	children := r.children(p, true)
+	if len(children) != 0 && !req.Recursive {
+		body.Fault_ = Fault("", guestRegistryKeyFault(new(types.GuestRegistryKeyHasSubkeys), req.KeyName, errorAccessDenied))
+		return body
+	}
+
+	for _, key := range children {
+		delete(r, guestRegistryPath(key.spec.KeyName.RegistryPath))
+	}
+	delete(r, p)
+
+	body.Res = new(types.DeleteRegistryKeyInGuestResponse)
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) SetRegistryValueInGuest(ctx *Context, req *types.SetRegistryValueInGuest) soap.HasFault {
+	body := new(methods.SetRegistryValueInGuestBody)
+
+	var key *guestRegistryKey
+	r, fault := m.vmRegistry(ctx, req.Vm, req.Auth)
+	if fault == nil {
+		key, fault = r.lookup(req.Value.Name.KeyName)
+	}
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	if req.Value.Data == nil {
+		body.Fault_ = Fault("", &types.InvalidArgument{InvalidProperty: "data"})
+		return body
+	}
+
+	key.values[strings.ToLower(req.Value.Name.Name)] = req.Value
+	key.spec.LastWritten = time.Now()
+
+	body.Res = new(types.SetRegistryValueInGuestResponse)
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) ListRegistryValuesInGuest(ctx *Context, req *types.ListRegistryValuesInGuest) soap.HasFault {
+	body := new(methods.ListRegistryValuesInGuestBody)
+
+	var key *guestRegistryKey
+	r, fault := m.vmRegistry(ctx, req.Vm, req.Auth)
+	if fault == nil {
+		key, fault = r.lookup(req.KeyName)
+	}
+	var re *regexp.Regexp
+	if fault == nil {
+		re, fault = guestRegistryMatch(req.MatchPattern)
+	}
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	body.Res = new(types.ListRegistryValuesInGuestResponse)
+
+	// ExpandStrings is not supported, as there is no guest environment to expand from
+	for _, value := range key.values {
+		if re != nil && !re.MatchString(value.Name.Name) {
+			continue
+		}
+		body.Res.Returnval = append(body.Res.Returnval, value)
+	}
+
+	sort.Slice(body.Res.Returnval, func(i, j int) bool {
+		return body.Res.Returnval[i].Name.Name < body.Res.Returnval[j].Name.Name
+	})
+
+	return body
+}
+
+func (m *GuestWindowsRegistryManager) DeleteRegistryValueInGuest(ctx *Context, req *types.DeleteRegistryValueInGuest) soap.HasFault {
+	body := new(methods.DeleteRegistryValueInGuestBody)
+
+	var key *guestRegistryKey
+	r, fault := m.vmRegistry(ctx, req.Vm, req.Auth)
+	if fault == nil {
+		key, fault = r.lookup(req.ValueName.KeyName)
+	}
+	if fault != nil {
+		body.Fault_ = Fault("", fault)
+		return body
+	}
+
+	name := strings.ToLower(req.ValueName.Name)
+
+	if _, ok := key.values[name]; !ok {
+		body.Fault_ = Fault("", &types.GuestRegistryValueNotFound{
+			GuestRegistryValueFault: types.GuestRegistryValueFault{
+				GuestRegistryFault: types.GuestRegistryFault{WindowsSystemErrorCode: errorFileNotFound},
+				KeyName:            req.ValueName.KeyName.RegistryPath,
+				ValueName:          req.ValueName.Name,
+			},
+		})
+		return body
+	}
+
+	delete(key.values, name)
+	key.spec.LastWritten = time.Now()
+
+	body.Res = new(types.DeleteRegistryValueInGuestResponse)
+
+	return body
+}
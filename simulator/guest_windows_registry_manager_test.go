@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/guest"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestGuestWindowsRegistryManager(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := guest.NewOperationsManager(c, vm.Reference()).WindowsRegistryManager(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		auth := &types.NamePasswordAuthentication{Username: "user", Password: "pass"}
+		key := func(path string) types.GuestRegKeyNameSpec {
+			return types.GuestRegKeyNameSpec{RegistryPath: path, WowBitness: string(types.GuestRegKeyWowSpecWOWNative)}
+		}
+
+		isFault := func(err error, fault types.BaseMethodFault) {
+			t.Helper()
+			if !soap.IsSoapFault(err) {
+				t.Fatalf("expected %T, got: %v", fault, err)
+			}
+			f := soap.ToSoapFault(err).VimFault()
+			if reflect.TypeOf(f) != reflect.TypeOf(fault).Elem() {
+				t.Fatalf("expected %T, got %T", fault, f)
+			}
+		}
+
+		err = m.CreateKey(ctx, &types.NamePasswordAuthentication{}, key(`HKEY_LOCAL_MACHINE\SOFTWARE`), false, "")
+		isFault(err, new(types.InvalidGuestLogin))
+
+		err = m.CreateKey(ctx, auth, key(`HKEY_LOCAL_MACHINE\SOFTWARE\govmomi`), false, "")
+		isFault(err, new(types.GuestRegistryKeyInvalid))
+
+		for _, p := range []string{`HKEY_LOCAL_MACHINE\SOFTWARE`, `HKEY_LOCAL_MACHINE\SOFTWARE\govmomi`, `HKEY_LOCAL_MACHINE\SOFTWARE\govmomi\vcsim`} {
+			if err = m.CreateKey(ctx, auth, key(p), false, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err = m.CreateKey(ctx, auth, key(`hkey_local_machine\software\GOVMOMI`), false, "")
+		isFault(err, new(types.GuestRegistryKeyAlreadyExists))
+
+		keys, err := m.ListKeys(ctx, auth, key(`HKEY_LOCAL_MACHINE\SOFTWARE`), false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 {
+			t.Errorf("keys=%d", len(keys))
+		}
+
+		keys, err = m.ListKeys(ctx, auth, key(`HKEY_LOCAL_MACHINE\SOFTWARE`), true, "^vc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0].Key.KeyName.RegistryPath != `HKEY_LOCAL_MACHINE\SOFTWARE\govmomi\vcsim` {
+			t.Errorf("keys=%#v", keys)
+		}
+
+		value := types.GuestRegValueSpec{
+			Name: types.GuestRegValueNameSpec{KeyName: key(`HKEY_LOCAL_MACHINE\SOFTWARE\govmomi`), Name: "Version"},
+			Data: &types.GuestRegValueDwordSpec{Value: 42},
+		}
+		if err = m.SetValue(ctx, auth, value); err != nil {
+			t.Fatal(err)
+		}
+
+		values, err := m.ListValues(ctx, auth, value.Name.KeyName, false, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 1 || values[0].Data.(*types.GuestRegValueDwordSpec).Value != 42 {
+			t.Errorf("values=%#v", values)
+		}
+
+		if err = m.DeleteValue(ctx, auth, value.Name); err != nil {
+			t.Fatal(err)
+		}
+		err = m.DeleteValue(ctx, auth, value.Name)
+		isFault(err, new(types.GuestRegistryValueNotFound))
+
+		err = m.DeleteKey(ctx, auth, key(`HKEY_LOCAL_MACHINE\SOFTWARE\govmomi`), false)
+		isFault(err, new(types.GuestRegistryKeyHasSubkeys))
+
+		if err = m.DeleteKey(ctx, auth, key(`HKEY_LOCAL_MACHINE\SOFTWARE\govmomi`), true); err != nil {
+			t.Fatal(err)
+		}
+
+		keys, err = m.ListKeys(ctx, auth, key(`HKEY_LOCAL_MACHINE\SOFTWARE`), true, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 0 {
+			t.Errorf("keys=%d", len(keys))
+		}
+	})
+}
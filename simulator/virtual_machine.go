@@ -35,6 +35,7 @@ import (
 
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/simulator/esx"
+	"github.com/vmware/govmomi/toolbox"
 	"github.com/vmware/govmomi/vim25/methods"
 	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/soap"
@@ -49,6 +50,8 @@ type VirtualMachine struct {
 	run container
 	uid uuid.UUID
 	imc *types.CustomizationSpec
+
+	tickets map[string]string // guest session tickets, to user name
 }
 
 func asVirtualMachineMO(obj mo.Reference) (*mo.VirtualMachine, bool) {
@@ -1623,16 +1626,84 @@ func (c *powerVMTask) Run(task *Task) (types.AnyType, types.BaseMethodFault) {
 		}
 	}
 
+	var disks []types.GuestDiskInfo
+	if c.state == types.VirtualMachinePowerStatePoweredOn {
+		disks = guestDiskInfo(c.VirtualMachine, devices)
+	}
+
 	c.ctx.Map.Update(c.VirtualMachine, []types.PropertyChange{
 		{Name: "runtime.powerState", Val: c.state},
 		{Name: "summary.runtime.powerState", Val: c.state},
 		{Name: "summary.runtime.bootTime", Val: boot},
 		{Name: "config.hardware.device", Val: devices},
+		{Name: "guest.guestId", Val: c.VirtualMachine.Config.GuestId},
+		{Name: "guest.guestFullName", Val: c.VirtualMachine.Config.GuestFullName},
+		{Name: "guest.disk", Val: disks},
+		{Name: "config.extraConfig", Val: guestAppInfo(c.VirtualMachine, c.state)},
 	})
 
 	return nil, nil
 }
 
+// guestAppInfo returns a copy of the VM's extraConfig with the guestinfo.appInfo variable published by tools,
+// listing vmtoolsd as the running application.  The variable is removed when the VM is not powered on.
+func guestAppInfo(vm *VirtualMachine, state types.VirtualMachinePowerState) []types.BaseOptionValue {
+	const key = "guestinfo.appInfo"
+	var config []types.BaseOptionValue
+
+	for _, opt := range vm.Config.ExtraConfig {
+		if opt.GetOptionValue().Key != key {
+			config = append(config, opt)
+		}
+	}
+
+	if state == types.VirtualMachinePowerStatePoweredOn {
+		h := &toolbox.AppInfoHandler{
+			Applications: func() ([]toolbox.AppInfo, error) {
+				return []toolbox.AppInfo{{Name: "vmtoolsd", Version: vm.Guest.ToolsVersion}}, nil
+			},
+		}
+
+		info, _ := h.Info()
+		config = append(config, &types.OptionValue{Key: key, Value: string(info)})
+	}
+
+	return config
+}
+
+// guestDiskInfo simulates the guest.disk info reported by tools, one file system per virtual disk,
+// with half of each disk's capacity in use.
+func guestDiskInfo(vm *VirtualMachine, devices object.VirtualDeviceList) []types.GuestDiskInfo {
+	var disks []types.GuestDiskInfo
+	windows := vm.Guest.GuestFamily == string(types.VirtualMachineGuestOsFamilyWindowsGuest)
+
+	for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+		disk := device.(*types.VirtualDisk)
+		n := len(disks)
+
+		info := types.GuestDiskInfo{
+			Capacity:  disk.CapacityInBytes,
+			FreeSpace: disk.CapacityInBytes / 2,
+			Mappings:  []types.GuestInfoVirtualDiskMapping{{Key: disk.Key}},
+		}
+
+		if windows {
+			info.DiskPath = fmt.Sprintf("%c:\\", 'C'+n)
+			info.FilesystemType = "NTFS"
+		} else {
+			info.DiskPath = "/"
+			if n != 0 {
+				info.DiskPath = fmt.Sprintf("/mnt/disk%d", n)
+			}
+			info.FilesystemType = "ext4"
+		}
+
+		disks = append(disks, info)
+	}
+
+	return disks
+}
+
 func (vm *VirtualMachine) PowerOnVMTask(ctx *Context, c *types.PowerOnVM_Task) soap.HasFault {
 	if vm.Config.Template {
 		return &methods.PowerOnVM_TaskBody{
@@ -1716,6 +1787,52 @@ func (vm *VirtualMachine) RebootGuest(ctx *Context, req *types.RebootGuest) soap
 	return body
 }
 
+func (vm *VirtualMachine) MountToolsInstaller(ctx *Context, req *types.MountToolsInstaller) soap.HasFault {
+	body := new(methods.MountToolsInstallerBody)
+
+	if vm.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
+		body.Fault_ = Fault("", &types.InvalidPowerState{
+			RequestedState: types.VirtualMachinePowerStatePoweredOn,
+			ExistingState:  vm.Runtime.PowerState,
+		})
+		return body
+	}
+
+	ctx.Map.Update(vm, []types.PropertyChange{{Name: "runtime.toolsInstallerMounted", Val: true}})
+	body.Res = new(types.MountToolsInstallerResponse)
+
+	return body
+}
+
+func (vm *VirtualMachine) UnmountToolsInstaller(ctx *Context, req *types.UnmountToolsInstaller) soap.HasFault {
+	ctx.Map.Update(vm, []types.PropertyChange{{Name: "runtime.toolsInstallerMounted", Val: false}})
+
+	return &methods.UnmountToolsInstallerBody{
+		Res: new(types.UnmountToolsInstallerResponse),
+	}
+}
+
+func (vm *VirtualMachine) UpgradeToolsTask(ctx *Context, req *types.UpgradeTools_Task) soap.HasFault {
+	task := CreateTask(vm, "upgradeTools", func(t *Task) (types.AnyType, types.BaseMethodFault) {
+		if vm.Guest.ToolsRunningStatus != string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
+			return nil, new(types.ToolsUnavailable)
+		}
+
+		ctx.Map.Update(vm, []types.PropertyChange{
+			{Name: "guest.toolsVersionStatus", Val: string(types.VirtualMachineToolsVersionStatusGuestToolsCurrent)},
+			{Name: "guest.toolsVersionStatus2", Val: string(types.VirtualMachineToolsVersionStatusGuestToolsCurrent)},
+		})
+
+		return nil, nil
+	})
+
+	return &methods.UpgradeTools_TaskBody{
+		Res: &types.UpgradeTools_TaskResponse{
+			Returnval: task.Run(ctx),
+		},
+	}
+}
+
 func (vm *VirtualMachine) ReconfigVMTask(ctx *Context, req *types.ReconfigVM_Task) soap.HasFault {
 	task := CreateTask(vm, "reconfigVm", func(t *Task) (types.AnyType, types.BaseMethodFault) {
 		ctx.postEvent(&types.VmReconfiguredEvent{
@@ -18,6 +18,7 @@ package simulator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"os"
@@ -31,6 +32,7 @@ import (
 	"github.com/vmware/govmomi/simulator/esx"
 	"github.com/vmware/govmomi/task"
 	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
@@ -1658,6 +1660,109 @@ func TestShutdownGuest(t *testing.T) {
 	}
 }
 
+func TestVmGuestDiskInfo(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map.Any("VirtualMachine").Reference())
+
+		var props mo.VirtualMachine
+		err := vm.Properties(ctx, vm.Reference(), []string{"guest", "config.guestId"}, &props)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if props.Guest.GuestId != props.Config.GuestId {
+			t.Errorf("guestId=%s", props.Guest.GuestId)
+		}
+
+		if len(props.Guest.Disk) == 0 {
+			t.Fatal("expected guest.disk")
+		}
+
+		disk := props.Guest.Disk[0]
+		if disk.DiskPath != "/" || disk.Capacity == 0 || disk.FreeSpace != disk.Capacity/2 || len(disk.Mappings) != 1 {
+			t.Errorf("disk=%#v", disk)
+		}
+
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		props = mo.VirtualMachine{}
+		err = vm.Properties(ctx, vm.Reference(), []string{"guest.disk"}, &props)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(props.Guest.Disk) != 0 {
+			t.Errorf("guest.disk=%d", len(props.Guest.Disk))
+		}
+	})
+}
+
+func TestVmGuestAppInfo(t *testing.T) {
+	Test(func(ctx context.Context, c *vim25.Client) {
+		vm := object.NewVirtualMachine(c, Map.Any("VirtualMachine").Reference())
+
+		appInfo := func() string {
+			var props mo.VirtualMachine
+			err := vm.Properties(ctx, vm.Reference(), []string{"config.extraConfig"}, &props)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var val string
+			for _, opt := range props.Config.ExtraConfig {
+				o := opt.GetOptionValue()
+				if o.Key == "guestinfo.appInfo" {
+					if val != "" {
+						t.Error("duplicate guestinfo.appInfo")
+					}
+					val = o.Value.(string)
+				}
+			}
+			return val
+		}
+
+		var info struct {
+			Version      string `json:"version"`
+			Applications []struct {
+				Name string `json:"a"`
+			} `json:"applications"`
+		}
+
+		if err := json.Unmarshal([]byte(appInfo()), &info); err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Version != "1" || len(info.Applications) != 1 || info.Applications[0].Name != "vmtoolsd" {
+			t.Errorf("appInfo=%#v", info)
+		}
+
+		for _, on := range []bool{false, true} {
+			power := vm.PowerOff
+			if on {
+				power = vm.PowerOn
+			}
+
+			task, err := power(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = task.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if val := appInfo(); (val != "") != on {
+				t.Errorf("on=%t appInfo=%q", on, val)
+			}
+		}
+	})
+}
+
 func TestVmSnapshot(t *testing.T) {
 	ctx := context.Background()
 
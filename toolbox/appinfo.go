@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AppInfo describes an application running in the guest
+type AppInfo struct {
+	Name    string `json:"a"`
+	Version string `json:"v"`
+}
+
+// appInfoUpdate is the JSON document published to the guestinfo.appInfo variable,
+// as defined in open-vm-tools/services/plugins/appInfo/appInfo.c
+type appInfoUpdate struct {
+	Version       string    `json:"version"`
+	UpdateCounter string    `json:"updateCounter"`
+	PublishTime   string    `json:"publishTime"`
+	Applications  []AppInfo `json:"applications"`
+}
+
+// AppInfoHandler publishes the list of running applications to the guestinfo.appInfo variable,
+// along with the guest info sent on reset.  Publishing is disabled when Applications is nil.
+type AppInfoHandler struct {
+	Applications func() ([]AppInfo, error)
+
+	mu      sync.Mutex
+	counter int
+}
+
+// Request returns the RPC request to publish the current list of applications
+func (h *AppInfoHandler) Request() ([]byte, error) {
+	b, err := h.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte("info-set guestinfo.appInfo "), b...), nil
+}
+
+// Info returns the JSON document with the current list of applications, as published to guestinfo.appInfo
+func (h *AppInfoHandler) Info() ([]byte, error) {
+	apps, err := h.Applications()
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	h.counter++
+	counter := h.counter
+	h.mu.Unlock()
+
+	info := appInfoUpdate{
+		Version:       "1",
+		UpdateCounter: strconv.Itoa(counter),
+		PublishTime:   time.Now().UTC().Format(time.RFC3339),
+		Applications:  apps,
+	}
+
+	if info.Applications == nil {
+		info.Applications = []AppInfo{}
+	}
+
+	return json.Marshal(info)
+}
+
+var procDir = "/proc"
+
+// RunningApplications returns an Applications function that lists the running processes matching the given names,
+// or all running processes if no names are given.  Processes are listed by their command name, without a version.
+// Only Linux is supported, other platforms report an empty list.
+func RunningApplications(names ...string) func() ([]AppInfo, error) {
+	include := make(map[string]bool)
+	for _, name := range names {
+		include[name] = true
+	}
+
+	return func() ([]AppInfo, error) {
+		comms, _ := filepath.Glob(filepath.Join(procDir, "[0-9]*", "comm"))
+
+		seen := make(map[string]bool)
+		var apps []AppInfo
+
+		for _, comm := range comms {
+			b, err := ioutil.ReadFile(filepath.Clean(comm))
+			if err != nil {
+				continue // process exited
+			}
+
+			name := strings.TrimSpace(string(b))
+			if name == "" || seen[name] || (len(include) != 0 && !include[name]) {
+				continue
+			}
+			seen[name] = true
+
+			apps = append(apps, AppInfo{Name: name})
+		}
+
+		sort.Slice(apps, func(i, j int) bool {
+			return apps[i].Name < apps[j].Name
+		})
+
+		return apps, nil
+	}
+}
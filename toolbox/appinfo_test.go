@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "toolbox-proc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for pid, comm := range map[string]string{"1": "systemd", "42": "nginx", "43": "nginx", "99": "sshd"} {
+		if err = os.Mkdir(filepath.Join(dir, pid), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, pid, "comm"), []byte(comm+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	proc := procDir
+	procDir = dir
+	defer func() { procDir = proc }()
+
+	apps, err := RunningApplications()()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []AppInfo{{Name: "nginx"}, {Name: "sshd"}, {Name: "systemd"}}
+	if !reflect.DeepEqual(apps, expect) {
+		t.Errorf("apps=%v", apps)
+	}
+
+	h := &AppInfoHandler{Applications: RunningApplications("nginx", "postgres")}
+
+	for _, counter := range []string{"1", "2"} {
+		req, err := h.Request()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prefix := []byte("info-set guestinfo.appInfo ")
+		if !bytes.HasPrefix(req, prefix) {
+			t.Fatalf("request=%q", req)
+		}
+
+		var info appInfoUpdate
+		if err = json.Unmarshal(bytes.TrimPrefix(req, prefix), &info); err != nil {
+			t.Fatal(err)
+		}
+
+		if info.UpdateCounter != counter {
+			t.Errorf("counter=%s", info.UpdateCounter)
+		}
+
+		if !reflect.DeepEqual(info.Applications, []AppInfo{{Name: "nginx"}}) {
+			t.Errorf("apps=%v", info.Applications)
+		}
+	}
+}
@@ -18,7 +18,9 @@ package toolbox
 
 import (
 	"errors"
+	"time"
 
+	"github.com/vmware/vmw-guestinfo/bdoor"
 	"github.com/vmware/vmw-guestinfo/message"
 	"github.com/vmware/vmw-guestinfo/vmcheck"
 )
@@ -26,10 +28,15 @@ import (
 const (
 	rpciProtocol uint32 = 0x49435052
 	tcloProtocol uint32 = 0x4f4c4354
+
+	// BDOOR_CMD_GETTIMEFULL as defined in open-vm-tools/lib/include/backdoor_def.h
+	bdoorCmdGetTimeFull uint32 = 46
 )
 
 var (
 	ErrNotVirtualWorld = errors.New("not in a virtual world")
+
+	ErrHostTimeUnavailable = errors.New("host time unavailable")
 )
 
 type backdoorChannel struct {
@@ -78,3 +85,23 @@ func NewBackdoorChannelIn() Channel {
 		protocol: tcloProtocol,
 	}
 }
+
+// backdoorHostTime returns the host clock time via the BDOOR_CMD_GETTIMEFULL backdoor command
+func backdoorHostTime() (time.Time, error) {
+	if !vmcheck.IsVirtualCPU() {
+		return time.Time{}, ErrNotVirtualWorld
+	}
+
+	p := &bdoor.BackdoorProto{}
+	p.CX.AsUInt32().SetWord(bdoorCmdGetTimeFull)
+
+	out := p.InOut()
+	if out.AX.AsUInt32().Word() != uint32(bdoor.BackdoorMagic) {
+		return time.Time{}, ErrHostTimeUnavailable
+	}
+
+	secs := int64(out.SI.AsUInt32().Word())<<32 | int64(out.DX.AsUInt32().Word())
+	usecs := int64(out.BX.AsUInt32().Word())
+
+	return time.Unix(secs, usecs*int64(time.Microsecond)), nil
+}
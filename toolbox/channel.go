@@ -19,6 +19,7 @@ package toolbox
 import (
 	"bytes"
 	"fmt"
+	"sync"
 )
 
 // Channel abstracts the guest<->vmx RPC transport
@@ -37,10 +38,16 @@ var (
 // ChannelOut extends Channel to provide RPCI protocol helpers
 type ChannelOut struct {
 	Channel
+
+	mu sync.Mutex
 }
 
-// Request sends an RPC command to the vmx and checks the return code for success or error
+// Request sends an RPC command to the vmx and checks the return code for success or error.
+// Request is safe for concurrent use, as handlers may send requests from other goroutines.
 func (c *ChannelOut) Request(request []byte) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if err := c.Send(request); err != nil {
 		return nil, err
 	}
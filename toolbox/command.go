@@ -360,7 +360,7 @@ func (c *CommandServer) DeleteFile(header vix.CommandRequestHeader, data []byte)
 		return nil, err
 	}
 
-	info, err := os.Stat(r.GuestPathName)
+	info, err := c.FileServer.Lstat(r.GuestPathName)
 	if err != nil {
 		return nil, err
 	}
@@ -369,7 +369,7 @@ func (c *CommandServer) DeleteFile(header vix.CommandRequestHeader, data []byte)
 		return nil, vix.Error(vix.NotAFile)
 	}
 
-	err = os.Remove(r.GuestPathName)
+	err = c.FileServer.Remove(r.GuestPathName)
 
 	return nil, err
 }
@@ -462,7 +462,7 @@ func (c *CommandServer) MoveFile(header vix.CommandRequestHeader, data []byte) (
 		return nil, err
 	}
 
-	info, err := os.Stat(r.OldPathName)
+	info, err := c.FileServer.Lstat(r.OldPathName)
 	if err != nil {
 		return nil, err
 	}
@@ -472,13 +472,13 @@ func (c *CommandServer) MoveFile(header vix.CommandRequestHeader, data []byte) (
 	}
 
 	if !r.Body.Overwrite {
-		_, err = os.Stat(r.NewPathName)
+		_, err = c.FileServer.Lstat(r.NewPathName)
 		if err == nil {
 			return nil, vix.Error(vix.FileAlreadyExists)
 		}
 	}
 
-	return nil, os.Rename(r.OldPathName, r.NewPathName)
+	return nil, c.FileServer.Rename(r.OldPathName, r.NewPathName)
 }
 
 func (c *CommandServer) ListFiles(header vix.CommandRequestHeader, data []byte) ([]byte, error) {
@@ -491,7 +491,7 @@ func (c *CommandServer) ListFiles(header vix.CommandRequestHeader, data []byte)
 		return nil, err
 	}
 
-	info, err := os.Lstat(r.GuestPathName)
+	info, err := c.FileServer.Lstat(r.GuestPathName)
 	if err != nil {
 		return nil, err
 	}
@@ -501,7 +501,7 @@ func (c *CommandServer) ListFiles(header vix.CommandRequestHeader, data []byte)
 
 	if info.IsDir() {
 		dir = r.GuestPathName
-		files, err = ioutil.ReadDir(r.GuestPathName)
+		files, err = c.FileServer.ReadDir(r.GuestPathName)
 		if err != nil {
 			return nil, err
 		}
@@ -887,6 +887,48 @@ func TestVixFiles(t *testing.T) {
 	}
 }
 
+func TestVixMemoryFiles(t *testing.T) {
+	c := NewCommandClient()
+
+	h := hgfs.NewMemoryHandler()
+	h.Add("/etc/motd", []byte("hello"))
+	c.Service.Command.FileServer.RegisterFileHandler("mem", h)
+
+	ls := &vix.ListFilesRequest{GuestPathName: "/mem/etc"}
+	reply := c.Request(vix.CommandListFiles, ls)
+	rc := vixRC(reply)
+	if rc != vix.OK {
+		t.Fatalf("rc: %d", rc)
+	}
+
+	if !bytes.Contains(reply, []byte("<Name>motd</Name>")) {
+		t.Errorf("ls: %q", reply)
+	}
+
+	mv := &vix.RenameFileRequest{
+		OldPathName: "/mem/etc/motd",
+		NewPathName: "/mem/etc/issue",
+	}
+
+	for _, expect := range []int{vix.OK, vix.FileNotFound} {
+		reply = c.Request(vix.CommandMoveGuestFileEx, mv)
+		rc = vixRC(reply)
+		if rc != expect {
+			t.Errorf("rc: %d", rc)
+		}
+	}
+
+	rm := &vix.FileRequest{GuestPathName: mv.NewPathName}
+
+	for _, expect := range []int{vix.OK, vix.FileNotFound} {
+		reply = c.Request(vix.CommandDeleteGuestFileEx, rm)
+		rc = vixRC(reply)
+		if rc != expect {
+			t.Errorf("rc: %d", rc)
+		}
+	}
+}
+
 func TestVixFileChangeAttributes(t *testing.T) {
 	if os.Getuid() == 0 {
 		t.Skip("running as root")
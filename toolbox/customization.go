@@ -0,0 +1,237 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LinuxCustomization is the Linux guest customization spec contained in a deploy package,
+// as generated by vCenter from a types.CustomizationSpec with types.CustomizationLinuxPrep identity.
+type LinuxCustomization struct {
+	HostName   string
+	DomainName string
+	NICs       []CustomizationNIC
+	DNS        CustomizationDNS
+	TimeZone   string
+	UTC        bool
+	Script     *CustomizationScript
+
+	// Config contains all key value pairs of the config file, keyed by section name
+	Config map[string]map[string]string
+}
+
+// CustomizationNIC is the network configuration of a guest NIC, identified by MAC address
+type CustomizationNIC struct {
+	Name     string
+	MAC      string
+	OnBoot   bool
+	DHCP     bool
+	IP       string
+	Netmask  string
+	Gateways []string
+	IPv6     []CustomizationIPv6
+}
+
+// CustomizationIPv6 is a static IPv6 address of a guest NIC
+type CustomizationIPv6 struct {
+	IP      string
+	Prefix  int
+	Gateway string
+}
+
+// CustomizationDNS is the guest DNS configuration
+type CustomizationDNS struct {
+	FromDHCP bool
+	Servers  []string
+	Suffixes []string
+}
+
+// CustomizationScript is the custom script included in the deploy package,
+// which is run with a "precustomization" argument before and "postcustomization" argument after customization.
+type CustomizationScript struct {
+	Name    string
+	Content []byte
+}
+
+// ParseLinuxCustomization parses a deploy package cust.cfg file
+func ParseLinuxCustomization(r io.Reader) (*LinuxCustomization, error) {
+	config, err := parseCustomizationConfig(r)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &LinuxCustomization{Config: config}
+
+	network := config["NETWORK"]
+	spec.HostName = network["HOSTNAME"]
+	spec.DomainName = network["DOMAINNAME"]
+
+	for _, name := range customizationList(config["NIC-CONFIG"]["NICS"]) {
+		nic, err := parseCustomizationNIC(name, config[name])
+		if err != nil {
+			return nil, err
+		}
+		spec.NICs = append(spec.NICs, nic)
+	}
+
+	dns := config["DNS"]
+	spec.DNS = CustomizationDNS{
+		FromDHCP: customizationBool(dns["DNSFROMDHCP"]),
+		Servers:  customizationIndexed(dns, "NAMESERVER"),
+		Suffixes: customizationIndexed(dns, "SUFFIX"),
+	}
+
+	datetime := config["DATETIME"]
+	spec.TimeZone = datetime["TIMEZONE"]
+	spec.UTC = customizationBool(datetime["UTC"])
+
+	if name := config["CUSTOM-SCRIPT"]["SCRIPT-NAME"]; name != "" {
+		spec.Script = &CustomizationScript{Name: name}
+	}
+
+	return spec, nil
+}
+
+func parseCustomizationNIC(name string, section map[string]string) (CustomizationNIC, error) {
+	nic := CustomizationNIC{Name: name}
+
+	if section == nil {
+		return nic, fmt.Errorf("NIC %q not found", name)
+	}
+
+	nic.MAC = section["MACADDR"]
+	if nic.MAC == "" {
+		return nic, fmt.Errorf("NIC %q: MACADDR not specified", name)
+	}
+
+	nic.OnBoot = customizationBool(section["ONBOOT"])
+	nic.DHCP = strings.EqualFold(section["BOOTPROTO"], "dhcp")
+	nic.IP = section["IPADDR"]
+	nic.Netmask = section["NETMASK"]
+	nic.Gateways = customizationList(section["GATEWAY"])
+
+	ips := customizationIndexed(section, "IPv6ADDR")
+	prefixes := customizationIndexed(section, "IPv6NETMASK")
+	gateways := customizationIndexed(section, "IPv6GATEWAY")
+
+	for i, ip := range ips {
+		addr := CustomizationIPv6{IP: ip}
+
+		if i < len(prefixes) {
+			n, err := strconv.Atoi(prefixes[i])
+			if err != nil {
+				return nic, fmt.Errorf("NIC %q: invalid IPv6NETMASK: %s", name, err)
+			}
+			addr.Prefix = n
+		}
+
+		if i < len(gateways) {
+			addr.Gateway = gateways[i]
+		}
+
+		nic.IPv6 = append(nic.IPv6, addr)
+	}
+
+	return nic, nil
+}
+
+// parseCustomizationConfig parses the ini style cust.cfg format
+func parseCustomizationConfig(r io.Reader) (map[string]map[string]string, error) {
+	config := make(map[string]map[string]string)
+	var section map[string]string
+
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "", strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			name := strings.TrimSpace(text[1 : len(text)-1])
+			section = make(map[string]string)
+			config[name] = section
+		default:
+			kv := strings.SplitN(text, "=", 2)
+			if len(kv) != 2 || section == nil {
+				return nil, fmt.Errorf("invalid config line %d: %q", line, text)
+			}
+			section[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	return config, scanner.Err()
+}
+
+// customizationIndexed returns the values of keys in the form of "NAME|N", ordered by N
+func customizationIndexed(section map[string]string, name string) []string {
+	type entry struct {
+		index int
+		value string
+	}
+
+	var entries []entry
+
+	for key, val := range section {
+		s := strings.SplitN(key, "|", 2)
+		if len(s) != 2 || s[0] != name {
+			continue
+		}
+
+		index, err := strconv.Atoi(s[1])
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, entry{index, val})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].index < entries[j].index
+	})
+
+	var values []string
+	for _, e := range entries {
+		values = append(values, e.value)
+	}
+
+	return values
+}
+
+// customizationList splits a comma separated list
+func customizationList(s string) []string {
+	var list []string
+
+	for _, val := range strings.Split(s, ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			list = append(list, val)
+		}
+	}
+
+	return list
+}
+
+func customizationBool(s string) bool {
+	return strings.EqualFold(s, "yes") || strings.EqualFold(s, "true")
+}
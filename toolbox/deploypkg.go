@@ -0,0 +1,336 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ToolsDeployPkgState as defined by the open-vm-tools deployPkg plugin
+const (
+	deployPkgStateIdle = iota
+	deployPkgStatePending
+	deployPkgStateCopying
+	deployPkgStateDeploying
+	deployPkgStateRunning
+	deployPkgStateDone
+)
+
+// Customization status codes reported with the deployPkg state
+const (
+	deployPkgErrorSuccess       = 0
+	deployPkgErrorCustomization = 100 // GUESTCUST_EVENT_CUSTOMIZE_FAILED
+)
+
+// VMwareDeployPkgHdr fields as defined in open-vm-tools/lib/include/deployPkgFormat.h
+const (
+	deployPkgSignature = "VMWAREDEPLOYPKG_"
+	deployPkgCmdLength = 464
+
+	deployPkgPayloadCab        = 0
+	deployPkgPayloadGzippedTar = 1
+)
+
+var (
+	// deployPkgConfig is the name of the customization config file within the deploy package
+	deployPkgConfig = "cust.cfg"
+
+	ErrDeployPkgSignature = errors.New("invalid deploy package signature")
+)
+
+// DeployPkgHeader is the deploy package header, followed by the package payload
+type DeployPkgHeader struct {
+	Signature     [16]byte
+	MajorVersion  uint8
+	MinorVersion  uint8
+	PayloadType   uint8
+	Reserved      uint8
+	PkgLength     uint64
+	PayloadOffset uint64
+	PayloadLength uint64
+	Command       [deployPkgCmdLength]byte
+}
+
+// DeployPkg is a guest customization package, as pushed by the VMX to the guest
+type DeployPkg struct {
+	Header DeployPkgHeader
+
+	// Files contains the contents of the package payload, keyed by path
+	Files map[string][]byte
+}
+
+// ReadDeployPkg reads a deploy package from r.  Only the gzipped tar payload used for Linux customization is supported.
+func ReadDeployPkg(r io.ReadSeeker) (*DeployPkg, error) {
+	pkg := &DeployPkg{Files: make(map[string][]byte)}
+
+	if err := binary.Read(r, binary.LittleEndian, &pkg.Header); err != nil {
+		return nil, err
+	}
+
+	if string(pkg.Header.Signature[:]) != deployPkgSignature {
+		return nil, ErrDeployPkgSignature
+	}
+
+	if pkg.Header.PayloadType != deployPkgPayloadGzippedTar {
+		return nil, fmt.Errorf("unsupported deploy package payload type: %d", pkg.Header.PayloadType)
+	}
+
+	if _, err := r.Seek(int64(pkg.Header.PayloadOffset), io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(io.LimitReader(r, int64(pkg.Header.PayloadLength)))
+	if err != nil {
+		return nil, err
+	}
+
+	tr := tar.NewReader(gz)
+
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		pkg.Files[path.Clean(h.Name)] = b
+	}
+
+	return pkg, nil
+}
+
+// WriteDeployPkg writes a deploy package to w, with a gzipped tar payload containing the given files.
+func WriteDeployPkg(w io.Writer, files map[string][]byte) error {
+	var payload bytes.Buffer
+	gz := gzip.NewWriter(&payload)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b := files[name]
+		h := &tar.Header{
+			Name: name,
+			Mode: 0600,
+			Size: int64(len(b)),
+		}
+
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(b); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	header := DeployPkgHeader{
+		MajorVersion:  1,
+		PayloadType:   deployPkgPayloadGzippedTar,
+		PayloadOffset: uint64(binary.Size(DeployPkgHeader{})),
+		PayloadLength: uint64(payload.Len()),
+	}
+	header.PkgLength = header.PayloadOffset + header.PayloadLength
+	copy(header.Signature[:], deployPkgSignature)
+
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	_, err := payload.WriteTo(w)
+	return err
+}
+
+// LinuxCustomization returns the Linux customization spec contained in the package
+func (pkg *DeployPkg) LinuxCustomization() (*LinuxCustomization, error) {
+	for name, b := range pkg.Files {
+		if path.Base(name) != deployPkgConfig {
+			continue
+		}
+
+		spec, err := ParseLinuxCustomization(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+
+		if spec.Script != nil {
+			script := path.Join(path.Dir(name), spec.Script.Name)
+			content, ok := pkg.Files[script]
+			if !ok {
+				return nil, fmt.Errorf("custom script %q not found in deploy package", spec.Script.Name)
+			}
+			spec.Script.Content = content
+		}
+
+		return spec, nil
+	}
+
+	return nil, fmt.Errorf("%s not found in deploy package", deployPkgConfig)
+}
+
+// DeployPkgHandler implements the deployPkg protocol, used by the VMX to push guest customization packages.
+// The VMX requests a file name via the deployPkg.begin RPC, copies the package to that file and then
+// requests deployment via the deployPkg.deploy RPC.  The package is unpacked and its LinuxCustomization spec
+// is passed to the Apply function in the background, with status reported to the VMX once Apply returns.
+// A nil Apply function causes customization to fail.
+type DeployPkgHandler struct {
+	Apply func(*LinuxCustomization) error
+
+	out   *ChannelOut
+	mu    sync.Mutex
+	dir   string
+	state int
+}
+
+func registerDeployPkgHandler(service *Service) *DeployPkgHandler {
+	handler := &DeployPkgHandler{out: service.out}
+
+	service.RegisterHandler("deployPkg.begin", handler.Begin)
+	service.RegisterHandler("deployPkg.deploy", handler.Deploy)
+
+	return handler
+}
+
+// Begin handles the deployPkg.begin RPC, returning the guest file name the VMX should copy the package to
+func (h *DeployPkgHandler) Begin([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state == deployPkgStateDeploying {
+		return nil, errors.New("deploy package already in progress")
+	}
+
+	if h.dir != "" {
+		_ = os.RemoveAll(h.dir)
+	}
+
+	dir, err := ioutil.TempDir("", "vmware-deploypkg")
+	if err != nil {
+		return nil, err
+	}
+
+	h.dir = dir
+	h.state = deployPkgStateCopying
+
+	return []byte(filepath.Join(dir, "deploypkg.tar")), nil
+}
+
+// Deploy handles the deployPkg.deploy RPC, unpacking and applying the given package in the background
+func (h *DeployPkgHandler) Deploy(args []byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := string(bytes.TrimRight(args, "\x00"))
+
+	if h.state != deployPkgStateCopying || filepath.Dir(name) != h.dir {
+		return nil, fmt.Errorf("invalid deploy package: %q", name)
+	}
+
+	h.state = deployPkgStateDeploying
+	h.status(deployPkgStateDeploying, deployPkgErrorSuccess, "")
+
+	go h.deploy(name)
+
+	return nil, nil
+}
+
+func (h *DeployPkgHandler) deploy(name string) {
+	err := h.apply(name)
+
+	h.mu.Lock()
+	_ = os.RemoveAll(h.dir)
+	h.dir = ""
+	h.state = deployPkgStateIdle
+	h.mu.Unlock()
+
+	if err != nil {
+		log.Printf("deployPkg: %s", err)
+		h.status(deployPkgStateDone, deployPkgErrorCustomization, err.Error())
+		return
+	}
+
+	h.status(deployPkgStateDone, deployPkgErrorSuccess, "")
+}
+
+func (h *DeployPkgHandler) apply(name string) error {
+	f, err := os.Open(filepath.Clean(name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	pkg, err := ReadDeployPkg(f)
+	if err != nil {
+		return err
+	}
+
+	spec, err := pkg.LinuxCustomization()
+	if err != nil {
+		return err
+	}
+
+	if h.Apply == nil {
+		return errors.New("guest customization not supported")
+	}
+
+	return h.Apply(spec)
+}
+
+func (h *DeployPkgHandler) status(state int, code int, msg string) {
+	msg = fmt.Sprintf("deployPkg.update.state %d %d %s", state, code, strings.ReplaceAll(msg, "\n", " "))
+
+	if _, err := h.out.Request([]byte(msg)); err != nil {
+		log.Printf("unable to send %q: %s", msg, err)
+	}
+}
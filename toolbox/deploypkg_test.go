@@ -0,0 +1,247 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCustomizationConfig = `
+[NETWORK]
+NETWORKING = yes
+BOOTPROTO = dhcp
+HOSTNAME = myhost1
+DOMAINNAME = example.com
+
+[NIC-CONFIG]
+NICS = NIC1,NIC2
+
+[NIC1]
+MACADDR = 00:50:56:a6:8c:08
+ONBOOT = yes
+IPv4_MODE = BACKWARDS_COMPATIBLE
+BOOTPROTO = static
+IPADDR = 10.20.87.154
+NETMASK = 255.255.252.0
+GATEWAY = 10.20.87.253, 10.20.87.105
+IPv6ADDR|1 = fc00:10:20:87::154
+IPv6NETMASK|1 = 64
+IPv6GATEWAY|1 = fc00:10:20:87::253
+
+[NIC2]
+MACADDR = 00:50:56:a6:ef:7d
+ONBOOT = yes
+BOOTPROTO = dhcp
+
+# DNS
+[DNS]
+DNSFROMDHCP=no
+SUFFIX|1 = example.com
+NAMESERVER|2 = 10.20.145.2
+NAMESERVER|1 = 10.20.145.1
+
+[DATETIME]
+TIMEZONE=Africa/Abidjan
+UTC=yes
+
+[CUSTOM-SCRIPT]
+SCRIPT-NAME = customize.sh
+`
+
+func TestParseLinuxCustomization(t *testing.T) {
+	spec, err := ParseLinuxCustomization(strings.NewReader(testCustomizationConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spec.HostName != "myhost1" || spec.DomainName != "example.com" {
+		t.Errorf("hostname=%q domain=%q", spec.HostName, spec.DomainName)
+	}
+
+	nics := []CustomizationNIC{
+		{
+			Name:     "NIC1",
+			MAC:      "00:50:56:a6:8c:08",
+			OnBoot:   true,
+			IP:       "10.20.87.154",
+			Netmask:  "255.255.252.0",
+			Gateways: []string{"10.20.87.253", "10.20.87.105"},
+			IPv6:     []CustomizationIPv6{{"fc00:10:20:87::154", 64, "fc00:10:20:87::253"}},
+		},
+		{
+			Name:   "NIC2",
+			MAC:    "00:50:56:a6:ef:7d",
+			OnBoot: true,
+			DHCP:   true,
+		},
+	}
+
+	if !reflect.DeepEqual(spec.NICs, nics) {
+		t.Errorf("nics=%#v", spec.NICs)
+	}
+
+	dns := CustomizationDNS{
+		Servers:  []string{"10.20.145.1", "10.20.145.2"},
+		Suffixes: []string{"example.com"},
+	}
+
+	if !reflect.DeepEqual(spec.DNS, dns) {
+		t.Errorf("dns=%#v", spec.DNS)
+	}
+
+	if spec.TimeZone != "Africa/Abidjan" || !spec.UTC {
+		t.Errorf("tz=%q utc=%t", spec.TimeZone, spec.UTC)
+	}
+
+	if spec.Script == nil || spec.Script.Name != "customize.sh" {
+		t.Errorf("script=%#v", spec.Script)
+	}
+
+	invalid := []string{
+		"HOSTNAME = nosection",
+		"[NETWORK]\nHOSTNAME",
+		"[NIC-CONFIG]\nNICS = NIC1",
+		"[NIC-CONFIG]\nNICS = NIC1\n[NIC1]\nONBOOT = yes",
+	}
+
+	for _, config := range invalid {
+		if _, err := ParseLinuxCustomization(strings.NewReader(config)); err == nil {
+			t.Errorf("expected error for %q", config)
+		}
+	}
+}
+
+func TestDeployPkg(t *testing.T) {
+	files := map[string][]byte{
+		"cust.cfg":     []byte(testCustomizationConfig),
+		"customize.sh": []byte("#!/bin/sh\n"),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDeployPkg(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := ReadDeployPkg(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(pkg.Files, files) {
+		t.Errorf("files=%v", pkg.Files)
+	}
+
+	spec, err := pkg.LinuxCustomization()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(spec.Script.Content) != "#!/bin/sh\n" {
+		t.Errorf("script=%q", spec.Script.Content)
+	}
+
+	delete(pkg.Files, "customize.sh")
+	if _, err = pkg.LinuxCustomization(); err == nil {
+		t.Error("expected error")
+	}
+
+	delete(pkg.Files, "cust.cfg")
+	if _, err = pkg.LinuxCustomization(); err == nil {
+		t.Error("expected error")
+	}
+
+	b := buf.Bytes()
+	b[0] = 'X'
+	if _, err = ReadDeployPkg(bytes.NewReader(b)); err != ErrDeployPkgSignature {
+		t.Errorf("err=%v", err)
+	}
+}
+
+func TestDeployPkgHandler(t *testing.T) {
+	out := new(eventChannelOut)
+	service := NewService(new(mockChannelIn), out)
+
+	specs := make(chan *LinuxCustomization, 1)
+	service.Deploy.Apply = func(spec *LinuxCustomization) error {
+		specs <- spec
+		if spec.HostName == "fail" {
+			return errors.New("apply failed")
+		}
+		return nil
+	}
+
+	deploy := func(config string) {
+		t.Helper()
+
+		reply := service.Dispatch([]byte("deployPkg.begin"))
+		if !bytes.HasPrefix(reply, []byte("OK ")) {
+			t.Fatalf("reply=%q", reply)
+		}
+		name := string(reply[3:])
+
+		// the VMX copies the package to the given file name
+		f, err := os.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = WriteDeployPkg(f, map[string][]byte{"cust.cfg": []byte(config)}); err != nil {
+			t.Fatal(err)
+		}
+		_ = f.Close()
+
+		reply = service.Dispatch([]byte("deployPkg.deploy " + name))
+		if string(reply) != "OK " {
+			t.Fatalf("reply=%q", reply)
+		}
+
+		out.waitFor(t, "deployPkg.update.state 3 0")
+	}
+
+	deploy("[NETWORK]\nHOSTNAME = myhost1")
+	out.waitFor(t, "deployPkg.update.state 5 0")
+
+	if spec := <-specs; spec.HostName != "myhost1" {
+		t.Errorf("hostname=%q", spec.HostName)
+	}
+
+	deploy("[NETWORK]\nHOSTNAME = fail")
+	msg := out.waitFor(t, "deployPkg.update.state 5 100")
+	if !strings.HasSuffix(msg, "apply failed") {
+		t.Errorf("msg=%q", msg)
+	}
+	<-specs
+
+	deploy("invalid")
+	out.waitFor(t, "deployPkg.update.state 5 100")
+
+	reply := service.Dispatch([]byte("deployPkg.deploy /tmp/deploypkg.tar"))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+
+	_ = service.Dispatch([]byte("deployPkg.begin"))
+	defer os.RemoveAll(service.Deploy.dir)
+	reply = service.Dispatch([]byte("deployPkg.deploy /tmp/deploypkg.tar"))
+	if !bytes.HasPrefix(reply, []byte("ERR")) {
+		t.Errorf("reply=%q", reply)
+	}
+}
@@ -17,9 +17,16 @@ limitations under the License.
 package toolbox
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
 
 	xdr "github.com/rasky/go-xdr/xdr2"
 )
@@ -141,6 +148,14 @@ func (nic *GuestNicV3) AddIP(addr net.Addr) {
 	nic.IPs = append(nic.IPs, e)
 }
 
+// GuestInfo types as defined in open-vm-tools/lib/include/guestInfo.h
+const (
+	GuestInfoDiskFreeSpace = 3
+	GuestInfoOSNameFull    = 5
+	GuestInfoOSName        = 6
+	GuestInfoIPAddressV3   = 9
+)
+
 func GuestInfoCommand(kind int, req []byte) []byte {
 	request := fmt.Sprintf("SetGuestInfo  %d ", kind)
 	return append([]byte(request), req...)
@@ -198,5 +213,219 @@ func GuestInfoNicInfoRequest() ([]byte, error) {
 		return nil, err
 	}
 
-	return GuestInfoCommand(9 /*INFO_IPADDRESS_V3*/, r), nil
+	return GuestInfoCommand(GuestInfoIPAddressV3, r), nil
+}
+
+// GuestDiskInfo describes a guest file system, encoded as JSON in the INFO_DISK_FREE_SPACE (version 1) request
+type GuestDiskInfo struct {
+	Name   string `json:"name"`
+	Free   uint64 `json:"free,string"`
+	Size   uint64 `json:"size,string"`
+	FSType string `json:"fstype,omitempty"`
+}
+
+type guestDiskInfoV1 struct {
+	Version string          `json:"version"`
+	Disks   []GuestDiskInfo `json:"disks"`
+}
+
+var (
+	mountsFile = "/proc/self/mounts"
+	maxDisks   = 64
+)
+
+// parseMounts returns the mount points of block device file systems from /proc/mounts formatted input
+func parseMounts(r io.Reader) []GuestDiskInfo {
+	var disks []GuestDiskInfo
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		dev, dir, fstype := fields[0], unescapeMount(fields[1]), fields[2]
+
+		if !strings.HasPrefix(dev, "/dev/") || strings.HasPrefix(dev, "/dev/loop") || seen[dir] {
+			continue // pseudo file systems, snaps and over mounts
+		}
+		seen[dir] = true
+
+		disks = append(disks, GuestDiskInfo{Name: dir, FSType: fstype})
+	}
+
+	return disks
+}
+
+// unescapeMount decodes the octal escapes used in /proc/mounts for space, tab, newline and backslash
+func unescapeMount(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// DefaultGuestDiskInfo returns the size and free space of the guest's mounted file systems
+func DefaultGuestDiskInfo() []GuestDiskInfo {
+	f, err := os.Open(mountsFile)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var disks []GuestDiskInfo
+
+	for _, disk := range parseMounts(f) {
+		disk.Size, disk.Free, err = filesystemUsage(disk.Name)
+		if err != nil || disk.Size == 0 {
+			continue
+		}
+
+		disks = append(disks, disk)
+
+		if len(disks) >= maxDisks {
+			break
+		}
+	}
+
+	return disks
+}
+
+func GuestInfoDiskRequest() ([]byte, error) {
+	r, err := json.Marshal(guestDiskInfoV1{Version: "1", Disks: DefaultGuestDiskInfo()})
+	if err != nil {
+		return nil, err
+	}
+
+	return GuestInfoCommand(GuestInfoDiskFreeSpace, r), nil
+}
+
+// GuestOSInfo as reported by the INFO_OS_NAME_FULL and INFO_OS_NAME requests.
+// The vmx maps Name to a guest ID, for example "ubuntu-64" to "ubuntu64Guest".
+type GuestOSInfo struct {
+	FullName string
+	Name     string
+}
+
+var osReleaseFiles = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// ParseOSRelease parses the os-release(5) format
+func ParseOSRelease(r io.Reader) map[string]string {
+	release := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		val := kv[1]
+		if u, err := strconv.Unquote(val); err == nil {
+			val = u
+		} else {
+			val = strings.Trim(val, `'"`)
+		}
+
+		release[kv[0]] = val
+	}
+
+	return release
+}
+
+// osShortNames maps os-release ID to the vmx short name, with the major version appended if true
+var osShortNames = map[string]struct {
+	name    string
+	version bool
+}{
+	"almalinux": {"almalinux", false},
+	"amzn":      {"amazonlinux", true},
+	"centos":    {"centos", true},
+	"debian":    {"debian", true},
+	"fedora":    {"fedora", false},
+	"photon":    {"vmware-photon", false},
+	"rhel":      {"rhel", true},
+	"rocky":     {"rockylinux", false},
+	"sles":      {"sles", true},
+	"ubuntu":    {"ubuntu", false},
+}
+
+// NewGuestOSInfo returns the GuestOSInfo for the given os-release(5) key value pairs
+func NewGuestOSInfo(release map[string]string) GuestOSInfo {
+	info := GuestOSInfo{
+		FullName: release["PRETTY_NAME"],
+		Name:     "other",
+	}
+
+	if info.FullName == "" {
+		info.FullName = strings.TrimSpace(release["NAME"] + " " + release["VERSION"])
+	}
+
+	if runtime.GOOS == "linux" {
+		info.Name = "otherlinux"
+	}
+
+	if short, ok := osShortNames[release["ID"]]; ok {
+		info.Name = short.name
+		if short.version {
+			info.Name += strings.SplitN(release["VERSION_ID"], ".", 2)[0]
+		}
+	}
+
+	if strconv.IntSize == 64 {
+		info.Name += "-64"
+	}
+
+	return info
+}
+
+// DefaultGuestOSInfo returns the GuestOSInfo for the running guest
+func DefaultGuestOSInfo() GuestOSInfo {
+	release := make(map[string]string)
+
+	for _, name := range osReleaseFiles {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		release = ParseOSRelease(f)
+		_ = f.Close()
+		break
+	}
+
+	info := NewGuestOSInfo(release)
+	if info.FullName == "" {
+		info.FullName = runtime.GOOS
+	}
+
+	return info
+}
+
+func GuestInfoOSNameFullRequest() ([]byte, error) {
+	return GuestInfoCommand(GuestInfoOSNameFull, []byte(DefaultGuestOSInfo().FullName)), nil
+}
+
+func GuestInfoOSNameRequest() ([]byte, error) {
+	return GuestInfoCommand(GuestInfoOSName, []byte(DefaultGuestOSInfo().Name)), nil
 }
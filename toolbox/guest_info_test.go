@@ -17,8 +17,12 @@ limitations under the License.
 package toolbox
 
 import (
+	"bytes"
+	"encoding/json"
 	"net"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -71,3 +75,101 @@ func TestMaxGuestNic(t *testing.T) {
 		t.Errorf("Nics=%d", l)
 	}
 }
+
+func TestParseMounts(t *testing.T) {
+	mounts := `sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0
+proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+/dev/sda1 / ext4 rw,relatime 0 0
+/dev/sda15 /boot/efi vfat rw,relatime 0 0
+/dev/loop0 /snap/core/1 squashfs ro,nodev,relatime 0 0
+/dev/sdb1 /mnt/my\040data xfs rw,relatime 0 0
+/dev/sda1 / ext4 rw,relatime 0 0
+`
+
+	disks := parseMounts(strings.NewReader(mounts))
+
+	expect := []GuestDiskInfo{
+		{Name: "/", FSType: "ext4"},
+		{Name: "/boot/efi", FSType: "vfat"},
+		{Name: "/mnt/my data", FSType: "xfs"},
+	}
+
+	if !reflect.DeepEqual(disks, expect) {
+		t.Errorf("disks=%#v", disks)
+	}
+}
+
+func TestGuestInfoDiskRequest(t *testing.T) {
+	req, err := GuestInfoDiskRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := []byte("SetGuestInfo  3 ")
+	if !bytes.HasPrefix(req, prefix) {
+		t.Fatalf("request=%q", req)
+	}
+
+	var info struct {
+		Version string `json:"version"`
+		Disks   []struct {
+			Name string `json:"name"`
+			Size string `json:"size"`
+			Free string `json:"free"`
+		} `json:"disks"`
+	}
+
+	if err = json.Unmarshal(bytes.TrimPrefix(req, prefix), &info); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Version != "1" {
+		t.Errorf("version=%s", info.Version)
+	}
+
+	for _, disk := range info.Disks {
+		size, err := strconv.ParseUint(disk.Size, 10, 64)
+		if err != nil || size == 0 {
+			t.Errorf("%s size=%q", disk.Name, disk.Size)
+		}
+	}
+}
+
+func TestGuestOSInfo(t *testing.T) {
+	release := ParseOSRelease(strings.NewReader(`# comment
+NAME="Ubuntu"
+VERSION="22.04.1 LTS (Jammy Jellyfish)"
+ID=ubuntu
+PRETTY_NAME="Ubuntu 22.04.1 LTS"
+VERSION_ID='22.04'
+`))
+
+	if release["VERSION_ID"] != "22.04" {
+		t.Errorf("release=%v", release)
+	}
+
+	suffix := ""
+	if strconv.IntSize == 64 {
+		suffix = "-64"
+	}
+
+	tests := []struct {
+		release map[string]string
+		expect  GuestOSInfo
+	}{
+		{release, GuestOSInfo{"Ubuntu 22.04.1 LTS", "ubuntu" + suffix}},
+		{map[string]string{"ID": "debian", "VERSION_ID": "11", "NAME": "Debian", "VERSION": "11"}, GuestOSInfo{"Debian 11", "debian11" + suffix}},
+		{map[string]string{"ID": "rhel", "VERSION_ID": "8.6", "PRETTY_NAME": "RHEL 8.6"}, GuestOSInfo{"RHEL 8.6", "rhel8" + suffix}},
+	}
+
+	for _, test := range tests {
+		info := NewGuestOSInfo(test.release)
+		if info != test.expect {
+			t.Errorf("%v: %#v", test.release, info)
+		}
+	}
+
+	if info := DefaultGuestOSInfo(); info.FullName == "" || info.Name == "" {
+		t.Errorf("%#v", info)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hgfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// FSHandler implements a read-only FileHandler for an fs.FS, such as an embed.FS or os.DirFS.
+type FSHandler struct {
+	FS fs.FS
+}
+
+// NewFSHandler returns a FileHandler implementation serving files from fsys.
+func NewFSHandler(fsys fs.FS) FileHandler {
+	return &FSHandler{FS: fsys}
+}
+
+// fsName converts the URL path to an fs.ValidPath name
+func fsName(u *url.URL) string {
+	name := strings.TrimPrefix(path.Clean("/"+u.Path), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// Stat implements FileHandler.Stat
+func (h *FSHandler) Stat(u *url.URL) (os.FileInfo, error) {
+	return fs.Stat(h.FS, fsName(u))
+}
+
+// Open implements FileHandler.Open
+func (h *FSHandler) Open(u *url.URL, mode int32) (File, error) {
+	if mode != OpenModeReadOnly {
+		return nil, &Status{Code: StatusAccessDenied, Err: errors.New("read-only file system")}
+	}
+
+	name := fsName(u)
+
+	f, err := h.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if info.IsDir() {
+		_ = f.Close()
+		return nil, &Status{Code: StatusOperationNotPermitted, Err: errors.New("is a directory")}
+	}
+
+	return &fsFile{File: f, name: name}, nil
+}
+
+// ReadDir implements DirHandler.ReadDir
+func (h *FSHandler) ReadDir(u *url.URL) ([]os.FileInfo, error) {
+	entries, err := fs.ReadDir(h.FS, fsName(u))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]os.FileInfo, len(entries))
+
+	for i, entry := range entries {
+		if files[i], err = entry.Info(); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// fsFile implements the File and io.Seeker interfaces.
+type fsFile struct {
+	fs.File
+	name string
+	pos  int64
+}
+
+func (f *fsFile) Name() string {
+	return f.name
+}
+
+func (f *fsFile) Read(b []byte) (int, error) {
+	n, err := f.File.Read(b)
+	f.pos += int64(n)
+	return n, err
+}
+
+func (f *fsFile) Write([]byte) (int, error) {
+	return 0, os.ErrPermission
+}
+
+// Seek delegates to the underlying fs.File if it implements io.Seeker,
+// otherwise only sequential reads are supported.
+func (f *fsFile) Seek(offset int64, whence int) (int64, error) {
+	if s, ok := f.File.(io.Seeker); ok {
+		pos, err := s.Seek(offset, whence)
+		if err == nil {
+			f.pos = pos
+		}
+		return pos, err
+	}
+
+	if whence == io.SeekStart && offset == f.pos {
+		return f.pos, nil
+	}
+
+	return f.pos, &Status{Code: StatusOperationNotSupported, Err: errors.New("seek not supported")}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hgfs
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFSHandler(t *testing.T) {
+	Trace = testing.Verbose()
+
+	c := NewClient()
+	c.CreateSession()
+
+	c.s.RegisterFileHandler("config", NewFSHandler(fstest.MapFS{
+		"app.conf":         {Data: []byte("key=value")},
+		"conf.d/extra.cfg": {Data: []byte("extra")},
+	}))
+
+	attr, status := c.GetAttr("/config/app.conf")
+	if status != StatusSuccess || attr.Size != 9 {
+		t.Fatalf("status=%d", status)
+	}
+
+	if _, status = c.GetAttr("/config/enoent"); status != StatusNoSuchFileOrDir {
+		t.Errorf("status=%d", status)
+	}
+
+	handle, status := c.Open("/config/app.conf")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	data, _ := c.Read(handle, 4, 5)
+	if data != "value" {
+		t.Errorf("data=%q", data)
+	}
+
+	if status = c.Write(handle, 0, "x"); status != StatusOperationNotPermitted {
+		t.Errorf("status=%d", status)
+	}
+	c.Close(handle)
+
+	if _, status = c.Open("/config/app.conf", true); status != StatusAccessDenied {
+		t.Errorf("status=%d", status)
+	}
+
+	if _, status = c.Open("/config/conf.d"); status != StatusOperationNotPermitted {
+		t.Errorf("status=%d", status)
+	}
+
+	names, status := c.Search("/config")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	if !reflect.DeepEqual(names, []string{"app.conf", "conf.d"}) {
+		t.Errorf("names=%v", names)
+	}
+
+	// not a RemoveHandler, falls back to the guest file system
+	if status = c.Delete("/config/app.conf", false); status != StatusNoSuchFileOrDir {
+		t.Errorf("status=%d", status)
+	}
+
+	c.DestroySession()
+}
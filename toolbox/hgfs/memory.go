@@ -0,0 +1,400 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hgfs
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryHandler implements a FileHandler for in-memory files, such as generated configuration or logs.
+// Directories are implicit, a directory exists as long as it contains a file.
+// Files opened for writing are stored when closed.
+type MemoryHandler struct {
+	mu    sync.Mutex
+	files map[string]*memoryEntry
+}
+
+type memoryEntry struct {
+	data  []byte
+	gen   func() ([]byte, error)
+	mode  os.FileMode
+	mtime time.Time
+}
+
+// NewMemoryHandler returns an empty MemoryHandler
+func NewMemoryHandler() *MemoryHandler {
+	return &MemoryHandler{
+		files: make(map[string]*memoryEntry),
+	}
+}
+
+func memoryPath(name string) string {
+	return path.Clean("/" + name)
+}
+
+// Add adds or replaces file name with the given content
+func (h *MemoryHandler) Add(name string, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.files[memoryPath(name)] = &memoryEntry{data: data, mode: 0644, mtime: time.Now()}
+}
+
+// AddFunc adds or replaces file name with content generated by fn each time the file is opened for reading
+func (h *MemoryHandler) AddFunc(name string, fn func() ([]byte, error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.files[memoryPath(name)] = &memoryEntry{gen: fn, mode: 0644, mtime: time.Now()}
+}
+
+// Content returns the content of file name
+func (h *MemoryHandler) Content(name string) ([]byte, error) {
+	h.mu.Lock()
+	e, ok := h.files[memoryPath(name)]
+	h.mu.Unlock()
+
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	return e.content()
+}
+
+func (e *memoryEntry) content() ([]byte, error) {
+	if e.gen != nil {
+		return e.gen()
+	}
+	return e.data, nil
+}
+
+// isDir returns true if any file is contained in directory name
+func (h *MemoryHandler) isDir(name string) bool {
+	if name == "/" {
+		return true
+	}
+
+	prefix := name + "/"
+	for file := range h.files {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *MemoryHandler) stat(name string) (os.FileInfo, error) {
+	if e, ok := h.files[name]; ok {
+		size := int64(len(e.data))
+		if e.gen != nil {
+			size = LargePacketMax // actual size is not known until opened
+		}
+		return &memoryFileInfo{name: path.Base(name), size: size, mode: e.mode, mtime: e.mtime}, nil
+	}
+
+	if h.isDir(name) {
+		return &memoryFileInfo{name: path.Base(name), mode: os.ModeDir | 0755, mtime: time.Now()}, nil
+	}
+
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// Stat implements FileHandler.Stat
+func (h *MemoryHandler) Stat(u *url.URL) (os.FileInfo, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.stat(memoryPath(u.Path))
+}
+
+// Open implements FileHandler.Open
+func (h *MemoryHandler) Open(u *url.URL, mode int32) (File, error) {
+	name := memoryPath(u.Path)
+
+	h.mu.Lock()
+	e, ok := h.files[name]
+	dir := !ok && h.isDir(name)
+	h.mu.Unlock()
+
+	if dir {
+		return nil, &Status{Code: StatusOperationNotPermitted, Err: errors.New("is a directory")}
+	}
+
+	f := &memoryFile{h: h, name: name, mode: mode}
+
+	switch mode {
+	case OpenModeReadOnly, OpenModeReadWrite:
+		if !ok {
+			if mode == OpenModeReadOnly {
+				return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+			}
+			break
+		}
+		data, err := e.content()
+		if err != nil {
+			return nil, err
+		}
+		f.data = append([]byte(nil), data...)
+	case OpenModeWriteOnly:
+	default:
+		return nil, &Status{Code: StatusAccessDenied}
+	}
+
+	return f, nil
+}
+
+// ReadDir implements DirHandler.ReadDir
+func (h *MemoryHandler) ReadDir(u *url.URL) ([]os.FileInfo, error) {
+	name := memoryPath(u.Path)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.files[name]; ok || !h.isDir(name) {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: os.ErrNotExist}
+	}
+
+	prefix := strings.TrimSuffix(name, "/") + "/"
+	seen := make(map[string]bool)
+	var files []os.FileInfo
+
+	for file := range h.files {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+
+		child := strings.SplitN(strings.TrimPrefix(file, prefix), "/", 2)[0]
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+
+		info, err := h.stat(prefix + child)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, info)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+
+	return files, nil
+}
+
+// Remove implements RemoveHandler.Remove
+func (h *MemoryHandler) Remove(u *url.URL) error {
+	name := memoryPath(u.Path)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.files[name]; !ok {
+		if h.isDir(name) {
+			return &Status{Code: StatusDirNotEmpty}
+		}
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+
+	delete(h.files, name)
+
+	return nil
+}
+
+// Rename implements RenameHandler.Rename, renaming files and directories within the same scheme.
+func (h *MemoryHandler) Rename(oldname *url.URL, newname *url.URL) error {
+	if oldname.Scheme != newname.Scheme {
+		return &Status{Code: StatusNotSameDevice}
+	}
+
+	src, dst := memoryPath(oldname.Path), memoryPath(newname.Path)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if e, ok := h.files[src]; ok {
+		delete(h.files, src)
+		h.files[dst] = e
+		return nil
+	}
+
+	if !h.isDir(src) {
+		return &os.PathError{Op: "rename", Path: src, Err: os.ErrNotExist}
+	}
+
+	prefix := src + "/"
+	for file, e := range h.files {
+		if strings.HasPrefix(file, prefix) {
+			delete(h.files, file)
+			h.files[dst+"/"+strings.TrimPrefix(file, prefix)] = e
+		}
+	}
+
+	return nil
+}
+
+// Setattr implements SetattrHandler.Setattr, only permissions are applied.
+func (h *MemoryHandler) Setattr(u *url.URL, attr *AttrV2) error {
+	name := memoryPath(u.Path)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e, ok := h.files[name]
+	if !ok {
+		if h.isDir(name) {
+			return nil
+		}
+		return &os.PathError{Op: "setattr", Path: name, Err: os.ErrNotExist}
+	}
+
+	if attr.Mask&AttrValidOwnerPerms == AttrValidOwnerPerms {
+		e.mode = e.mode&^0700 | os.FileMode(attr.OwnerPerms)<<6
+	}
+	if attr.Mask&AttrValidGroupPerms == AttrValidGroupPerms {
+		e.mode = e.mode&^0070 | os.FileMode(attr.GroupPerms)<<3
+	}
+	if attr.Mask&AttrValidOtherPerms == AttrValidOtherPerms {
+		e.mode = e.mode&^0007 | os.FileMode(attr.OtherPerms)
+	}
+
+	return nil
+}
+
+// memoryFile implements the File and io.Seeker interfaces.
+type memoryFile struct {
+	h    *MemoryHandler
+	name string
+	mode int32
+	data []byte
+	pos  int64
+}
+
+func (f *memoryFile) Name() string {
+	return f.name
+}
+
+func (f *memoryFile) Read(b []byte) (int, error) {
+	if f.mode == OpenModeWriteOnly {
+		return 0, os.ErrPermission
+	}
+
+	if f.pos >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(b, f.data[f.pos:])
+	f.pos += int64(n)
+
+	return n, nil
+}
+
+func (f *memoryFile) Write(b []byte) (int, error) {
+	if f.mode == OpenModeReadOnly {
+		return 0, os.ErrPermission
+	}
+
+	end := f.pos + int64(len(b))
+	if end > int64(len(f.data)) {
+		data := make([]byte, end)
+		copy(data, f.data)
+		f.data = data
+	}
+
+	n := copy(f.data[f.pos:], b)
+	f.pos += int64(n)
+
+	return n, nil
+}
+
+func (f *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.pos
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	}
+
+	if offset < 0 {
+		return 0, &Status{Code: StatusInvalidParameter}
+	}
+
+	f.pos = offset
+
+	return offset, nil
+}
+
+// Close stores the file content if opened for writing
+func (f *memoryFile) Close() error {
+	if f.mode == OpenModeReadOnly {
+		return nil
+	}
+
+	f.h.mu.Lock()
+	defer f.h.mu.Unlock()
+
+	mode := os.FileMode(0644)
+	if e, ok := f.h.files[f.name]; ok {
+		mode = e.mode
+	}
+
+	f.h.files[f.name] = &memoryEntry{data: f.data, mode: mode, mtime: time.Now()}
+
+	return nil
+}
+
+// memoryFileInfo implements the os.FileInfo interface.
+type memoryFileInfo struct {
+	name  string
+	size  int64
+	mode  os.FileMode
+	mtime time.Time
+}
+
+func (i *memoryFileInfo) Name() string {
+	return i.name
+}
+
+func (i *memoryFileInfo) Size() int64 {
+	return i.size
+}
+
+func (i *memoryFileInfo) Mode() os.FileMode {
+	return i.mode
+}
+
+func (i *memoryFileInfo) ModTime() time.Time {
+	return i.mtime
+}
+
+func (i *memoryFileInfo) IsDir() bool {
+	return i.mode.IsDir()
+}
+
+func (i *memoryFileInfo) Sys() interface{} {
+	return nil
+}
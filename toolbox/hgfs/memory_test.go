@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hgfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMemoryHandler(t *testing.T) {
+	Trace = testing.Verbose()
+
+	c := NewClient()
+	c.CreateSession()
+
+	h := NewMemoryHandler()
+	c.s.RegisterFileHandler("mem", h)
+
+	h.Add("/etc/motd", []byte("hello world"))
+	calls := 0
+	h.AddFunc("/var/log/app.log", func() ([]byte, error) {
+		calls++
+		return []byte("generated"), nil
+	})
+
+	attr, status := c.GetAttr("/mem/etc")
+	if status != StatusSuccess || attr.Type != FileTypeDirectory {
+		t.Fatalf("status=%d", status)
+	}
+
+	attr, status = c.GetAttr("/mem/etc/motd")
+	if status != StatusSuccess || attr.Type != FileTypeRegular || attr.Size != 11 {
+		t.Fatalf("status=%d", status)
+	}
+
+	if _, status = c.GetAttr("/mem/enoent"); status != StatusNoSuchFileOrDir {
+		t.Errorf("status=%d", status)
+	}
+
+	handle, status := c.Open("/mem/etc/motd")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	// reads are positioned at the request offset
+	for _, offset := range []uint64{6, 0} {
+		data, status := c.Read(handle, offset, 5)
+		if status != StatusSuccess {
+			t.Fatalf("status=%d", status)
+		}
+		expect := "hello world"[offset : offset+5]
+		if data != expect {
+			t.Errorf("offset %d: %q", offset, data)
+		}
+	}
+	c.Close(handle)
+
+	handle, status = c.Open("/mem/var/log/app.log")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	data, _ := c.Read(handle, 0, LargePacketMax)
+	if data != "generated" || calls != 1 {
+		t.Errorf("data=%q calls=%d", data, calls)
+	}
+	c.Close(handle)
+
+	handle, status = c.OpenWrite("/mem/tmp/new.txt")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	c.Write(handle, 0, "one two")
+	c.Write(handle, 4, "2")
+	if status = c.Close(handle); status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+
+	content, err := h.Content("/tmp/new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "one 2wo" {
+		t.Errorf("content=%q", content)
+	}
+
+	names, status := c.Search("/mem")
+	if status != StatusSuccess {
+		t.Fatalf("status=%d", status)
+	}
+	if !reflect.DeepEqual(names, []string{"etc", "tmp", "var"}) {
+		t.Errorf("names=%v", names)
+	}
+
+	if status = c.Rename("/mem/tmp/new.txt", "/mem/etc/motd", RenameHintNoReplaceExisting); status != StatusFileExists {
+		t.Errorf("status=%d", status)
+	}
+
+	if status = c.Rename("/mem/tmp", "/mem/etc/tmp", 0); status != StatusSuccess {
+		t.Errorf("status=%d", status)
+	}
+
+	names, _ = c.Search("/mem/etc")
+	if !reflect.DeepEqual(names, []string{"motd", "tmp"}) {
+		t.Errorf("names=%v", names)
+	}
+
+	if status = c.Rename("/mem/etc/motd", "/tmp/motd", 0); status != StatusNotSameDevice {
+		t.Errorf("status=%d", status)
+	}
+
+	if status = c.Delete("/mem/etc/tmp", false); status != StatusOperationNotPermitted {
+		t.Errorf("status=%d", status)
+	}
+
+	if status = c.Delete("/mem/etc/tmp", true); status != StatusDirNotEmpty {
+		t.Errorf("status=%d", status)
+	}
+
+	if status = c.Delete("/mem/etc/motd", true); status != StatusNotDirectory {
+		t.Errorf("status=%d", status)
+	}
+
+	if status = c.Delete("/mem/etc/tmp/new.txt", false); status != StatusSuccess {
+		t.Errorf("status=%d", status)
+	}
+
+	if _, status = c.GetAttr("/mem/etc/tmp"); status != StatusNoSuchFileOrDir {
+		t.Errorf("status=%d", status)
+	}
+
+	attr, _ = c.GetAttr("/mem/etc/motd")
+	attr.Mask = AttrValidOwnerPerms | AttrValidGroupPerms | AttrValidOtherPerms
+	attr.OwnerPerms, attr.GroupPerms, attr.OtherPerms = PermRead, 0, 0
+	if status = c.SetAttr("/mem/etc/motd", *attr); status != StatusSuccess {
+		t.Errorf("status=%d", status)
+	}
+
+	info, _ := c.s.Lstat("/mem/etc/motd")
+	if info.Mode().Perm() != 0400 {
+		t.Errorf("mode=%s", info.Mode())
+	}
+
+	c.DestroySession()
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -845,3 +846,153 @@ type ReplyWriteV3 struct {
 	ActualSize uint32
 	Reserved   uint64
 }
+
+// RequestSearchOpenV3 as defined in hgfsProto.h:HgfsRequestSearchOpenV3
+type RequestSearchOpenV3 struct {
+	Reserved uint64
+	DirName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestSearchOpenV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Reserved, &r.DirName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestSearchOpenV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Reserved, &r.DirName)
+}
+
+// ReplySearchOpenV3 as defined in hgfsProto.h:HgfsReplySearchOpenV3
+type ReplySearchOpenV3 struct {
+	Search   uint32
+	Reserved uint64
+}
+
+// RequestSearchReadV3 as defined in hgfsProto.h:HgfsRequestSearchReadV3
+type RequestSearchReadV3 struct {
+	Search   uint32
+	Offset   uint32
+	Flags    uint32
+	Reserved uint64
+}
+
+// DirEntry as defined in hgfsProto.h:HgfsDirEntry
+type DirEntry struct {
+	NextEntry uint32
+	Attr      AttrV2
+	FileName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (e *DirEntry) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&e.NextEntry, &e.Attr, &e.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (e *DirEntry) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &e.NextEntry, &e.Attr, &e.FileName)
+}
+
+// ReplySearchReadV3 as defined in hgfsProto.h:HgfsReplySearchReadV3
+// The Entry field is only valid when Count is 1, a Count of 0 indicates the end of the search.
+type ReplySearchReadV3 struct {
+	Count    uint64
+	Reserved uint64
+	Entry    DirEntry
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *ReplySearchReadV3) MarshalBinary() ([]byte, error) {
+	if r.Count == 0 {
+		return MarshalBinary(&r.Count, &r.Reserved)
+	}
+	return MarshalBinary(&r.Count, &r.Reserved, &r.Entry)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *ReplySearchReadV3) UnmarshalBinary(data []byte) error {
+	if len(data) <= 16 {
+		return UnmarshalBinary(data, &r.Count, &r.Reserved)
+	}
+	return UnmarshalBinary(data, &r.Count, &r.Reserved, &r.Entry)
+}
+
+// RequestSearchCloseV3 as defined in hgfsProto.h:HgfsRequestSearchCloseV3
+type RequestSearchCloseV3 struct {
+	Search   uint32
+	Reserved uint64
+}
+
+// ReplySearchCloseV3 as defined in hgfsProto.h:HgfsReplySearchCloseV3
+type ReplySearchCloseV3 struct {
+	Reserved uint64
+}
+
+// RequestDeleteV3 as defined in hgfsProto.h:HgfsRequestDeleteV3
+type RequestDeleteV3 struct {
+	Hints    uint64
+	Reserved uint64
+	FileName FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestDeleteV3) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(&r.Hints, &r.Reserved, &r.FileName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestDeleteV3) UnmarshalBinary(data []byte) error {
+	return UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.FileName)
+}
+
+// ReplyDeleteV3 as defined in hgfsProto.h:HgfsReplyDeleteV3
+type ReplyDeleteV3 struct {
+	Reserved uint64
+}
+
+// Rename hints
+const (
+	RenameHintUseSrcFileDesc = 1 << iota
+	RenameHintUseTargetFileDesc
+	RenameHintNoReplaceExisting
+	RenameHintNoCopyAllowed
+)
+
+// RequestRenameV3 as defined in hgfsProto.h:HgfsRequestRenameV3
+type RequestRenameV3 struct {
+	Hints    uint64
+	Reserved uint64
+	OldName  FileNameV3
+	NewName  FileNameV3
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (r *RequestRenameV3) MarshalBinary() ([]byte, error) {
+	old := &r.OldName
+	old.Length = uint32(len(old.Name))
+
+	// NewName follows the nul terminated OldName
+	return MarshalBinary(&r.Hints, &r.Reserved,
+		&old.Length, &old.Flags, &old.CaseType, &old.ID, old.Name+"\x00", &r.NewName)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (r *RequestRenameV3) UnmarshalBinary(data []byte) error {
+	err := UnmarshalBinary(data, &r.Hints, &r.Reserved, &r.OldName)
+	if err != nil {
+		return err
+	}
+
+	offset := 8 + 8 + 16 + int(r.OldName.Length) + 1
+	if offset > len(data) {
+		return ProtocolError(io.ErrUnexpectedEOF)
+	}
+
+	return r.NewName.UnmarshalBinary(data[offset:])
+}
+
+// ReplyRenameV3 as defined in hgfsProto.h:HgfsReplyRenameV3
+type ReplyRenameV3 struct {
+	Reserved uint64
+}
@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"math/rand"
 	"net/url"
@@ -76,6 +77,12 @@ func NewServer() *Server {
 		OpOpenV3:           s.OpenV3,
 		OpReadV3:           s.ReadV3,
 		OpWriteV3:          s.WriteV3,
+		OpSearchOpenV3:     s.SearchOpenV3,
+		OpSearchReadV3:     s.SearchReadV3,
+		OpSearchCloseV3:    s.SearchCloseV3,
+		OpDeleteFileV3:     s.DeleteFileV3,
+		OpDeleteDirV3:      s.DeleteDirV3,
+		OpRenameV3:         s.RenameV3,
 	}
 
 	for op := range s.handlers {
@@ -124,6 +131,7 @@ func (s *Server) Dispatch(packet []byte) ([]byte, error) {
 
 // File interface abstracts standard i/o methods to support transfer
 // of regular files and archives of directories.
+// A File that also implements io.Seeker is positioned at the request offset before each read or write.
 type File interface {
 	io.Reader
 	io.Writer
@@ -133,11 +141,34 @@ type File interface {
 }
 
 // FileHandler is the plugin interface for hgfs file transport.
+// A FileHandler can optionally implement the DirHandler, RemoveHandler, RenameHandler and SetattrHandler interfaces.
+// Any handler method can return os.ErrNotExist to fall back to the guest file system.
 type FileHandler interface {
 	Stat(*url.URL) (os.FileInfo, error)
 	Open(*url.URL, int32) (File, error)
 }
 
+// DirHandler is the FileHandler extension for directory listing.
+type DirHandler interface {
+	ReadDir(*url.URL) ([]os.FileInfo, error)
+}
+
+// RemoveHandler is the FileHandler extension for removing files and empty directories.
+type RemoveHandler interface {
+	Remove(*url.URL) error
+}
+
+// RenameHandler is the FileHandler extension for renaming files and directories.
+// The new name is not required to have the same scheme as the old name.
+type RenameHandler interface {
+	Rename(*url.URL, *url.URL) error
+}
+
+// SetattrHandler is the FileHandler extension for changing file attributes.
+type SetattrHandler interface {
+	Setattr(*url.URL, *AttrV2) error
+}
+
 // urlParse attempts to convert the given name to a URL with scheme for use as FileHandler dispatch.
 func urlParse(name string) *url.URL {
 	var info os.FileInfo
@@ -151,22 +182,45 @@ func urlParse(name string) *url.URL {
 		}
 	}
 
-	u, err = url.Parse(strings.TrimPrefix(name, "/")) // must appear to be an absolute path or hgfs errors
+	return schemeParse(name)
+}
+
+// schemeParse converts the given name to a URL, using the first path element as the scheme if not specified.
+func schemeParse(name string) *url.URL {
+	u, err := url.Parse(strings.TrimPrefix(name, "/")) // must appear to be an absolute path or hgfs errors
 	if err != nil {
 		u = &url.URL{Path: name}
 	}
 
 	if u.Scheme == "" {
 		ix := strings.Index(u.Path, "/")
-		if ix > 0 {
+		switch {
+		case ix > 0:
 			u.Scheme = u.Path[:ix]
 			u.Path = u.Path[ix:]
+		case ix < 0 && u.Path != "":
+			u.Scheme = u.Path // root of the scheme
+			u.Path = "/"
 		}
 	}
 
 	return u
 }
 
+// handlerParse converts the given name to a URL for FileHandler dispatch by the file operations that can act on
+// directories.  As with urlParse, the real path takes precedence: a name without a scheme that exists in the guest
+// file system is not dispatched to the FileHandler registered for its first path element.
+func handlerParse(name string) *url.URL {
+	u, err := url.Parse(name)
+	if err == nil && u.Scheme == "" {
+		if _, err = os.Lstat(name); err == nil {
+			return u
+		}
+	}
+
+	return schemeParse(name)
+}
+
 // OpenFile selects the File implementation based on file type and mode.
 func (s *Server) OpenFile(name string, mode int32) (File, error) {
 	u := urlParse(name)
@@ -214,9 +268,70 @@ func (s *Server) Stat(name string) (os.FileInfo, error) {
 	return os.Stat(name)
 }
 
+// Lstat returns the FileInfo for name, using the FileHandler registered for its scheme, if any, otherwise os.Lstat.
+// Unlike Stat, directories are reported as such.
+func (s *Server) Lstat(name string) (os.FileInfo, error) {
+	u := handlerParse(name)
+
+	if h, ok := s.schemes[u.Scheme]; ok {
+		info, err := h.Stat(u)
+		if err != os.ErrNotExist {
+			return info, err
+		}
+	}
+
+	return os.Lstat(name)
+}
+
+// ReadDir returns the contents of directory name, using the DirHandler registered for its scheme, if any,
+// otherwise ioutil.ReadDir.
+func (s *Server) ReadDir(name string) ([]os.FileInfo, error) {
+	u := handlerParse(name)
+
+	if h, ok := s.schemes[u.Scheme].(DirHandler); ok {
+		files, err := h.ReadDir(u)
+		if err != os.ErrNotExist {
+			return files, err
+		}
+	}
+
+	return ioutil.ReadDir(name)
+}
+
+// Remove removes the file or empty directory name, using the RemoveHandler registered for its scheme, if any,
+// otherwise os.Remove.
+func (s *Server) Remove(name string) error {
+	u := handlerParse(name)
+
+	if h, ok := s.schemes[u.Scheme].(RemoveHandler); ok {
+		err := h.Remove(u)
+		if err != os.ErrNotExist {
+			return err
+		}
+	}
+
+	return os.Remove(name)
+}
+
+// Rename renames oldname to newname, using the RenameHandler registered for the scheme of oldname, if any,
+// otherwise os.Rename.
+func (s *Server) Rename(oldname, newname string) error {
+	u := handlerParse(oldname)
+
+	if h, ok := s.schemes[u.Scheme].(RenameHandler); ok {
+		err := h.Rename(u, handlerParse(newname))
+		if err != os.ErrNotExist {
+			return err
+		}
+	}
+
+	return os.Rename(oldname, newname)
+}
+
 type session struct {
-	files map[uint32]File
-	mu    sync.Mutex
+	files    map[uint32]File
+	searches map[uint32][]os.FileInfo
+	mu       sync.Mutex
 }
 
 // TODO: we currently depend on the VMX to close files and remove sessions,
@@ -224,7 +339,8 @@ type session struct {
 // adding session expiration when implementing OpenModeWriteOnly support.
 func newSession() *session {
 	return &session{
-		files: make(map[uint32]File),
+		files:    make(map[uint32]File),
+		searches: make(map[uint32][]os.FileInfo),
 	}
 }
 
@@ -356,6 +472,17 @@ func (s *Server) SetattrV2(p *Packet) (interface{}, error) {
 
 	name := req.FileName.Path()
 
+	u := handlerParse(name)
+	if h, ok := s.schemes[u.Scheme].(SetattrHandler); ok {
+		err = h.Setattr(u, &req.Attr)
+		if err != os.ErrNotExist {
+			if err != nil {
+				return nil, err
+			}
+			return res, nil
+		}
+	}
+
 	_, err = os.Stat(name)
 	if err != nil && os.IsNotExist(err) {
 		// assuming this is a virtual file
@@ -532,6 +659,12 @@ func (s *Server) ReadV3(p *Packet) (interface{}, error) {
 		return nil, &Status{Code: StatusInvalidHandle}
 	}
 
+	if seeker, ok := file.(io.Seeker); ok {
+		if _, err = seeker.Seek(int64(req.Offset), io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
 	buf := make([]byte, req.RequiredSize)
 
 	// Use ReadFull as Read() of an archive io.Pipe may return much smaller chunks,
@@ -572,6 +705,16 @@ func (s *Server) WriteV3(p *Packet) (interface{}, error) {
 		return nil, &Status{Code: StatusInvalidHandle}
 	}
 
+	if seeker, ok := file.(io.Seeker); ok {
+		offset, whence := int64(req.Offset), io.SeekStart
+		if req.WriteFlags&WriteAppend == WriteAppend {
+			offset, whence = 0, io.SeekEnd
+		}
+		if _, err = seeker.Seek(offset, whence); err != nil {
+			return nil, err
+		}
+	}
+
 	n, err := file.Write(req.Payload)
 	if err != nil {
 		return nil, err
@@ -583,3 +726,151 @@ func (s *Server) WriteV3(p *Packet) (interface{}, error) {
 
 	return res, nil
 }
+
+// SearchOpenV3 handles OpSearchOpenV3 requests
+func (s *Server) SearchOpenV3(p *Packet) (interface{}, error) {
+	req := new(RequestSearchOpenV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.ReadDir(req.DirName.Path())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ReplySearchOpenV3{
+		Search: s.newHandle(),
+	}
+
+	session.mu.Lock()
+	session.searches[res.Search] = files
+	session.mu.Unlock()
+
+	return res, nil
+}
+
+// SearchReadV3 handles OpSearchReadV3 requests, replying with the directory entry at the request offset.
+// A reply with a Count of 0 indicates the end of the search.
+func (s *Server) SearchReadV3(p *Packet) (interface{}, error) {
+	req := new(RequestSearchReadV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	files, ok := session.searches[req.Search]
+	session.mu.Unlock()
+
+	if !ok {
+		return nil, &Status{Code: StatusInvalidHandle}
+	}
+
+	res := new(ReplySearchReadV3)
+
+	if int(req.Offset) < len(files) {
+		info := files[req.Offset]
+		res.Count = 1
+		res.Entry.Attr.Stat(info)
+		res.Entry.FileName.Name = info.Name()
+	}
+
+	return res, nil
+}
+
+// SearchCloseV3 handles OpSearchCloseV3 requests
+func (s *Server) SearchCloseV3(p *Packet) (interface{}, error) {
+	req := new(RequestSearchCloseV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getSession(p)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	_, ok := session.searches[req.Search]
+	delete(session.searches, req.Search)
+	session.mu.Unlock()
+
+	if !ok {
+		return nil, &Status{Code: StatusInvalidHandle}
+	}
+
+	return &ReplySearchCloseV3{}, nil
+}
+
+func (s *Server) remove(p *Packet, dir bool) (interface{}, error) {
+	req := new(RequestDeleteV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.FileName.Path()
+
+	info, err := s.Lstat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() != dir {
+		code := uint32(StatusNotDirectory)
+		if info.IsDir() {
+			code = StatusOperationNotPermitted
+		}
+		return nil, &Status{Code: code}
+	}
+
+	if err = s.Remove(name); err != nil {
+		return nil, err
+	}
+
+	return &ReplyDeleteV3{}, nil
+}
+
+// DeleteFileV3 handles OpDeleteFileV3 requests
+func (s *Server) DeleteFileV3(p *Packet) (interface{}, error) {
+	return s.remove(p, false)
+}
+
+// DeleteDirV3 handles OpDeleteDirV3 requests
+func (s *Server) DeleteDirV3(p *Packet) (interface{}, error) {
+	return s.remove(p, true)
+}
+
+// RenameV3 handles OpRenameV3 requests
+func (s *Server) RenameV3(p *Packet) (interface{}, error) {
+	req := new(RequestRenameV3)
+	err := UnmarshalBinary(p.Payload, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Hints&RenameHintNoReplaceExisting == RenameHintNoReplaceExisting {
+		if _, err = s.Lstat(req.NewName.Path()); err == nil {
+			return nil, &Status{Code: StatusFileExists}
+		}
+	}
+
+	if err = s.Rename(req.OldName.Path(), req.NewName.Path()); err != nil {
+		return nil, err
+	}
+
+	return &ReplyRenameV3{}, nil
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -189,6 +190,89 @@ func (c *Client) Close(handle uint32) uint32 {
 	return c.Dispatch(OpClose, req, res).Status
 }
 
+func (c *Client) Read(handle uint32, offset uint64, size uint32) (string, uint32) {
+	req := &RequestReadV3{
+		Handle:       handle,
+		Offset:       offset,
+		RequiredSize: size,
+	}
+	res := new(ReplyReadV3)
+
+	p := c.Dispatch(OpReadV3, req, res)
+	if p.Status != StatusSuccess {
+		return "", p.Status
+	}
+
+	return string(res.Payload), p.Status
+}
+
+func (c *Client) Write(handle uint32, offset uint64, data string) uint32 {
+	req := &RequestWriteV3{
+		Handle:       handle,
+		Offset:       offset,
+		RequiredSize: uint32(len(data)),
+		Payload:      []byte(data),
+	}
+
+	return c.Dispatch(OpWriteV3, req, new(ReplyWriteV3)).Status
+}
+
+func (c *Client) Search(name string) ([]string, uint32) {
+	req := new(RequestSearchOpenV3)
+	res := new(ReplySearchOpenV3)
+
+	req.DirName.FromString(name)
+
+	p := c.Dispatch(OpSearchOpenV3, req, res)
+	if p.Status != StatusSuccess {
+		return nil, p.Status
+	}
+
+	var names []string
+
+	for offset := uint32(0); ; offset++ {
+		rreq := &RequestSearchReadV3{Search: res.Search, Offset: offset}
+		rres := new(ReplySearchReadV3)
+
+		p = c.Dispatch(OpSearchReadV3, rreq, rres)
+		if p.Status != StatusSuccess {
+			return nil, p.Status
+		}
+
+		if rres.Count == 0 {
+			break
+		}
+
+		names = append(names, rres.Entry.FileName.Name)
+	}
+
+	creq := &RequestSearchCloseV3{Search: res.Search}
+
+	return names, c.Dispatch(OpSearchCloseV3, creq, new(ReplySearchCloseV3)).Status
+}
+
+func (c *Client) Rename(oldname, newname string, hints uint64) uint32 {
+	req := &RequestRenameV3{Hints: hints}
+
+	req.OldName.FromString(oldname)
+	req.NewName.FromString(newname)
+
+	return c.Dispatch(OpRenameV3, req, new(ReplyRenameV3)).Status
+}
+
+func (c *Client) Delete(name string, dir bool) uint32 {
+	req := new(RequestDeleteV3)
+
+	req.FileName.FromString(name)
+
+	op := int32(OpDeleteFileV3)
+	if dir {
+		op = OpDeleteDirV3
+	}
+
+	return c.Dispatch(op, req, new(ReplyDeleteV3)).Status
+}
+
 func TestStaleSession(t *testing.T) {
 	c := NewClient()
 
@@ -198,6 +282,13 @@ func TestStaleSession(t *testing.T) {
 		func() uint32 { return c.Dispatch(OpReadV3, new(RequestReadV3), new(ReplyReadV3)).Status },
 		func() uint32 { return c.Dispatch(OpWriteV3, new(RequestWriteV3), new(ReplyWriteV3)).Status },
 		func() uint32 { return c.Close(0) },
+		func() uint32 { _, status := c.Search("/etc"); return status },
+		func() uint32 {
+			return c.Dispatch(OpSearchReadV3, new(RequestSearchReadV3), new(ReplySearchReadV3)).Status
+		},
+		func() uint32 {
+			return c.Dispatch(OpSearchCloseV3, new(RequestSearchCloseV3), new(ReplySearchCloseV3)).Status
+		},
 		c.DestroySession,
 	}
 
@@ -446,3 +537,67 @@ func TestWriteV3(t *testing.T) {
 
 	_ = os.Remove(name)
 }
+
+func TestHandlerRealPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "govmomi-hgfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := path.Join(dir, "file")
+	if err = ioutil.WriteFile(name, []byte("real"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// register a handler for the first element of the real path, which takes precedence
+	scheme := strings.SplitN(strings.TrimPrefix(dir, "/"), "/", 2)[0]
+	h := NewMemoryHandler()
+	h.Add(strings.TrimPrefix(name, "/"+scheme), []byte("memory"))
+	h.Add(strings.TrimPrefix(name, "/"+scheme)+"-memory", []byte("memory"))
+	h.Add("govmomi-hgfs-enoent", []byte("memory"))
+
+	s := NewServer()
+	s.RegisterFileHandler(scheme, h)
+
+	info, err := s.Lstat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 4 {
+		t.Errorf("size=%d", info.Size())
+	}
+
+	files, err := s.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "file" {
+		t.Errorf("files=%v", files)
+	}
+
+	// the handler is used when the scheme is explicit
+	info, err = s.Lstat(scheme + ":" + strings.TrimPrefix(name, "/"+scheme))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 6 {
+		t.Errorf("size=%d", info.Size())
+	}
+
+	if err = s.Remove(name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("err=%v", err)
+	}
+
+	// or when the real path does not exist
+	info, err = s.Lstat(path.Join("/", scheme, "govmomi-hgfs-enoent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 6 {
+		t.Errorf("size=%d", info.Size())
+	}
+}
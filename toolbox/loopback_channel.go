@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// LoopbackVMX emulates the VMX side of the toolbox RPC channels in-process,
+// allowing a Service to be tested end-to-end without a hypervisor.
+// The Channels returned by ChannelIn and ChannelOut are passed to NewService,
+// TCLO requests are sent to the Service via Request and RPCI requests from the Service are passed to RPCI.
+type LoopbackVMX struct {
+	// RPCI handles requests sent by the Service, the default replies OK to all requests.
+	RPCI func(request []byte) []byte
+
+	// Timeout for Request replies
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	tclo net.Conn
+}
+
+// NewLoopbackVMX creates a new LoopbackVMX instance
+func NewLoopbackVMX() *LoopbackVMX {
+	return &LoopbackVMX{
+		RPCI: func([]byte) []byte {
+			return rpciOK
+		},
+		Timeout: streamReplyTimeout,
+	}
+}
+
+// ChannelIn returns a Channel for use with the TCLO protocol, connected to the LoopbackVMX
+func (vmx *LoopbackVMX) ChannelIn() Channel {
+	return &streamChannel{
+		dial: func() (io.ReadWriteCloser, error) {
+			guest, host := net.Pipe()
+
+			vmx.mu.Lock()
+			if vmx.tclo != nil {
+				_ = vmx.tclo.Close()
+			}
+			vmx.tclo = host
+			vmx.mu.Unlock()
+
+			return guest, nil
+		},
+	}
+}
+
+// ChannelOut returns a Channel for use with the RPCI protocol, connected to the LoopbackVMX
+func (vmx *LoopbackVMX) ChannelOut() Channel {
+	return &streamChannel{
+		dial: func() (io.ReadWriteCloser, error) {
+			guest, host := net.Pipe()
+
+			go vmx.serveRPCI(host)
+
+			return guest, nil
+		},
+		wait: streamReplyTimeout,
+	}
+}
+
+// Request sends a TCLO request to the Service and returns its reply
+func (vmx *LoopbackVMX) Request(request []byte) ([]byte, error) {
+	vmx.mu.Lock()
+	defer vmx.mu.Unlock()
+
+	if vmx.tclo == nil {
+		return nil, errors.New("loopback channel not started")
+	}
+
+	if err := vmx.tclo.SetDeadline(time.Now().Add(vmx.Timeout)); err != nil {
+		return nil, err
+	}
+
+	if err := writePacket(vmx.tclo, request); err != nil {
+		return nil, err
+	}
+
+	return readPacket(vmx.tclo)
+}
+
+func (vmx *LoopbackVMX) serveRPCI(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		request, err := readPacket(conn)
+		if err != nil {
+			if err != io.EOF && !errors.Is(err, io.ErrClosedPipe) {
+				log.Printf("loopback rpci: %s", err)
+			}
+			return
+		}
+
+		if err = writePacket(conn, vmx.RPCI(request)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoopbackVMX(t *testing.T) {
+	vmx := NewLoopbackVMX()
+
+	var mu sync.Mutex
+	var requests []string
+	reply := rpciOK
+
+	vmx.RPCI = func(request []byte) []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, string(request))
+		return reply
+	}
+
+	if _, err := vmx.Request([]byte("ping")); err == nil {
+		t.Error("expected error")
+	}
+
+	service := NewService(vmx.ChannelIn(), vmx.ChannelOut())
+	service.PrimaryIP = func() string {
+		return "10.0.0.42"
+	}
+
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		request string
+		expect  string
+		rpci    string
+	}{
+		{"reset", "OK ATR toolbox", "SetGuestInfo"},
+		{"ping", "OK ", ""},
+		{"Capabilities_Register", "OK ", "tools.capability.statechange"},
+		{"Set_Option broadcastIP 1", "OK ", "info-set guestinfo.ip 10.0.0.42"},
+		{"enoent", "Unknown Command", ""},
+	}
+
+	for _, test := range tests {
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+
+		res, err := vmx.Request([]byte(test.request))
+		if err != nil {
+			t.Fatalf("%s: %s", test.request, err)
+		}
+
+		if string(res) != test.expect {
+			t.Errorf("%s: reply=%q", test.request, res)
+		}
+
+		if test.rpci == "" {
+			continue
+		}
+
+		mu.Lock()
+		found := false
+		for _, r := range requests {
+			if strings.HasPrefix(r, test.rpci) {
+				found = true
+			}
+		}
+		mu.Unlock()
+
+		if !found {
+			t.Errorf("%s: rpci request %q not found in %q", test.request, test.rpci, requests)
+		}
+	}
+
+	mu.Lock()
+	reply = rpciERR
+	mu.Unlock()
+
+	res, err := vmx.Request([]byte("Set_Option broadcastIP 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res) != "ERR " {
+		t.Errorf("reply=%q", res)
+	}
+
+	// the Service reconnects if the vmx closes the connection
+	delay := resetDelay
+	resetDelay = 1
+	defer func() { resetDelay = delay }()
+
+	vmx.mu.Lock()
+	_ = vmx.tclo.Close()
+	vmx.mu.Unlock()
+
+	for i := 0; ; i++ {
+		if res, err = vmx.Request([]byte("ping")); err == nil {
+			break
+		}
+		if i > 100 {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	service.Stop()
+	service.Wait()
+
+	if _, err = vmx.Request([]byte("ping")); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestStreamChannel(t *testing.T) {
+	var host net.Conn
+
+	c := &streamChannel{
+		dial: func() (io.ReadWriteCloser, error) {
+			var guest net.Conn
+			guest, host = net.Pipe()
+			return guest, nil
+		},
+		wait: 10 * time.Millisecond,
+	}
+
+	if err := c.Send([]byte("ping")); err != os.ErrClosed {
+		t.Errorf("err=%v", err)
+	}
+
+	if _, err := c.Receive(); err != os.ErrClosed {
+		t.Errorf("err=%v", err)
+	}
+
+	if err := c.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Receive(); err != os.ErrDeadlineExceeded {
+		t.Errorf("err=%v", err)
+	}
+
+	go func() {
+		_ = writePacket(host, []byte("hello"))
+	}()
+
+	b, err := c.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, []byte("hello")) {
+		t.Errorf("received %q", b)
+	}
+
+	if err = c.Send(nil); err != nil {
+		t.Error(err)
+	}
+
+	// packet size exceeds limit
+	go func() {
+		_, _ = host.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	}()
+
+	if _, err = c.Receive(); err == nil {
+		t.Error("expected error")
+	}
+
+	if err = c.Send(nil); err == nil {
+		t.Error("expected error")
+	}
+
+	if err = c.Stop(); err != nil {
+		t.Error(err)
+	}
+
+	if err = c.Stop(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGuestRpcPacket(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := writePacket(&buf, []byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	// serialized DataMap: length, int64 packet type field and string payload field
+	expect := []byte{
+		0, 0, 0, 32,
+		0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 2, 0, 0, 0, 2, 0, 0, 0, 4, 'p', 'i', 'n', 'g',
+	}
+	if !bytes.Equal(buf.Bytes(), expect) {
+		t.Errorf("packet=%v", buf.Bytes())
+	}
+
+	// fields in any order, unknown fields and non-data packets are ignored
+	packets := [][]byte{
+		{
+			0, 0, 0, 16,
+			0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2,
+		},
+		{
+			0, 0, 0, 69,
+			0, 0, 0, 2, 0, 0, 0, 2, 0, 0, 0, 4, 'p', 'o', 'n', 'g',
+			0, 0, 0, 3, 0, 0, 0, 9, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 7,
+			0, 0, 0, 4, 0, 0, 0, 8, 0, 0, 0, 1, 0, 0, 0, 1, 'x',
+			0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1,
+		},
+	}
+	r := bytes.NewReader(bytes.Join(packets, nil))
+
+	b, err := readPacket(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "pong" {
+		t.Errorf("payload=%q", b)
+	}
+
+	truncated := []byte{0, 0, 0, 12, 0, 0, 0, 2, 0, 0, 0, 2, 0, 0, 0, 4}
+	if _, err = readPacket(bytes.NewReader(truncated)); err != errPacketTruncated {
+		t.Errorf("err=%v", err)
+	}
+}
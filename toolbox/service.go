@@ -65,8 +65,12 @@ type Service struct {
 	delay    time.Duration
 	rpcError bool
 
-	Command *CommandServer
-	Power   *PowerCommandHandler
+	Command  *CommandServer
+	Power    *PowerCommandHandler
+	Backup   *BackupCommandHandler
+	TimeSync *TimeSyncHandler
+	Deploy   *DeployPkgHandler
+	AppInfo  *AppInfoHandler
 
 	PrimaryIP func() string
 }
@@ -76,7 +80,7 @@ func NewService(rpcIn Channel, rpcOut Channel) *Service {
 	s := &Service{
 		name:     "toolbox", // Same name used by vmtoolsd
 		in:       NewTraceChannel(rpcIn),
-		out:      &ChannelOut{NewTraceChannel(rpcOut)},
+		out:      &ChannelOut{Channel: NewTraceChannel(rpcOut)},
 		handlers: make(map[string]Handler),
 		wg:       new(sync.WaitGroup),
 		stop:     make(chan struct{}),
@@ -95,6 +99,10 @@ func NewService(rpcIn Channel, rpcOut Channel) *Service {
 	s.Command.FileServer.RegisterFileHandler(hgfs.ArchiveScheme, hgfs.NewArchiveHandler())
 
 	s.Power = registerPowerCommandHandler(s)
+	s.Backup = registerBackupCommandHandler(s)
+	s.TimeSync = registerTimeSyncHandler(s)
+	s.Deploy = registerDeployPkgHandler(s)
+	s.AppInfo = new(AppInfoHandler)
 
 	return s
 }
@@ -283,6 +291,9 @@ func (s *Service) SetOption(args []byte) ([]byte, error) {
 			s.SendGuestInfo()
 		}
 	default:
+		if isTimeSyncOption(key) {
+			return nil, s.TimeSync.SetOption(key, val)
+		}
 		// TODO: handle other options...
 	}
 
@@ -317,9 +328,17 @@ func (s *Service) CapabilitiesRegister([]byte) ([]byte, error) {
 	return nil, nil
 }
 
+// SendGuestInfo sends the guest NIC, disk and OS info to the vmx, along with the running applications if enabled
 func (s *Service) SendGuestInfo() {
 	info := []func() ([]byte, error){
 		GuestInfoNicInfoRequest,
+		GuestInfoDiskRequest,
+		GuestInfoOSNameFullRequest,
+		GuestInfoOSNameRequest,
+	}
+
+	if s.AppInfo.Applications != nil {
+		info = append(info, s.AppInfo.Request)
 	}
 
 	for i, r := range info {
@@ -162,10 +162,12 @@ func TestServiceRun(t *testing.T) {
 		out.reply = append(out.reply, rpciOK)
 	}
 
-	out.reply = append(out.reply,
-		rpciOK, // reply to SendGuestInfo call in Reset()
-		rpciOK, // reply to IP broadcast
-	)
+	// replies to SendGuestInfo call in Reset()
+	for i := 0; i < 4; i++ {
+		out.reply = append(out.reply, rpciOK)
+	}
+
+	out.reply = append(out.reply, rpciOK) // reply to IP broadcast
 
 	in.service = service
 
@@ -244,7 +246,7 @@ func TestServiceErrors(t *testing.T) {
 		out.reply,
 		rpciERR,
 		rpciOK,
-		rpciOK,
+		rpciOK, rpciOK, rpciOK, rpciOK, // replies to SendGuestInfo
 		append(rpciOK, foo...),
 		rpciERR,
 	)
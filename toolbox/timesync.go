@@ -0,0 +1,268 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Time sync options as sent by the VMX via Set_Option
+const (
+	timeSyncOptionEnable            = "synctime"
+	timeSyncOptionPeriod            = "synctime.period"
+	timeSyncOptionSlewCorrection    = "synctime.slewCorrection"
+	timeSyncOptionPercentCorrection = "synctime.percentCorrection"
+	timeSyncOptionResume            = "time.synchronize.resume.disk"
+)
+
+var (
+	// TIMESYNC_TIME and TIMESYNC_PERCENT_CORRECTION as defined in open-vm-tools/services/plugins/timeSync/timeSync.c
+	timeSyncPeriod            = 60 * time.Second
+	timeSyncPercentCorrection = 50
+
+	// ErrTimeSyncDisabled is returned by TimeSyncHandler.Sync when time sync has not been enabled
+	ErrTimeSyncDisabled = errors.New("time sync disabled")
+)
+
+// TimeSyncHandler synchronizes the guest clock with the host clock.
+// The VMX enables periodic synchronization via Set_Option synctime, based on the VM's tools.syncTimeWithHost setting,
+// and requests a one-time synchronization via the Time_Synchronize RPC, such as after vMotion.
+// The clock is also synchronized when the VM is resumed from suspend.
+//
+// A one-time synchronization corrects the clock offset by calling step.  Periodic synchronization calls step when
+// the guest clock is behind by more than StepThreshold, otherwise the offset is gradually corrected by calling slew
+// with a percentage of the offset.  Periodic synchronization never steps the clock backward.
+// Synchronization is disabled until Enable is called, see the TimeStep and TimeSlew functions.
+type TimeSyncHandler struct {
+	HostTime func() (time.Time, error)
+
+	StepThreshold time.Duration
+
+	service           *Service
+	mu                sync.Mutex
+	step              func(time.Duration) error
+	slew              func(time.Duration) error
+	enabled           bool
+	period            time.Duration
+	slewCorrection    bool
+	percentCorrection int
+	resume            bool
+	done              chan struct{}
+	stop              chan struct{}
+}
+
+func registerTimeSyncHandler(service *Service) *TimeSyncHandler {
+	handler := &TimeSyncHandler{
+		HostTime:          backdoorHostTime,
+		StepThreshold:     time.Second,
+		period:            timeSyncPeriod,
+		slewCorrection:    true,
+		percentCorrection: timeSyncPercentCorrection,
+		resume:            true,
+		service:           service,
+		stop:              service.stop,
+	}
+
+	// Chain to the OS_Resume dispatcher, leaving Power.Resume.Handler to the caller
+	resume := service.handlers["OS_Resume"]
+	service.RegisterHandler("OS_Resume", func(args []byte) ([]byte, error) {
+		res, err := resume(args)
+		if serr := handler.Resume(); serr != nil {
+			log.Printf("OS_Resume: %s", serr)
+		}
+		return res, err
+	})
+
+	return handler
+}
+
+// Enable time sync using the given functions to step and slew the guest clock, slew may be nil.
+// Enable registers the Time_Synchronize RPC handler and must be called before Service.Start.
+// Periodic synchronization is started if the VMX has already enabled it via Set_Option synctime.
+func (h *TimeSyncHandler) Enable(step, slew func(time.Duration) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.step = step
+	h.slew = slew
+
+	h.service.RegisterHandler("Time_Synchronize", h.Synchronize)
+
+	if h.enabled {
+		h.start()
+	}
+}
+
+// Synchronize handles the Time_Synchronize RPC, the clock is stepped backward if the argument is "1"
+func (h *TimeSyncHandler) Synchronize(args []byte) ([]byte, error) {
+	backward := string(bytes.TrimRight(args, "\x00")) == "1"
+
+	return nil, h.Sync(backward)
+}
+
+// Resume synchronizes the clock after the VM is resumed from suspend, unless disabled via
+// Set_Option time.synchronize.resume.disk
+func (h *TimeSyncHandler) Resume() error {
+	h.mu.Lock()
+	resume := h.resume && h.step != nil
+	h.mu.Unlock()
+
+	if !resume {
+		return nil
+	}
+
+	return h.Sync(true)
+}
+
+// Sync performs a one-time synchronization of the guest clock, backward must be true to step the clock backward.
+func (h *TimeSyncHandler) Sync(backward bool) error {
+	return h.sync(true, backward)
+}
+
+// sync corrects the guest clock offset from the host clock.
+// A one-time sync steps the clock, a periodic sync only steps the clock forward when the offset exceeds StepThreshold.
+// Any offset that is not stepped is slewed, if enabled.
+func (h *TimeSyncHandler) sync(once bool, backward bool) error {
+	h.mu.Lock()
+	stepf, slewf := h.step, h.slew
+	slew := h.slewCorrection && slewf != nil
+	percent := h.percentCorrection
+	h.mu.Unlock()
+
+	if stepf == nil {
+		return ErrTimeSyncDisabled
+	}
+
+	host, err := h.HostTime()
+	if err != nil {
+		return err
+	}
+
+	offset := time.Until(host)
+	if offset == 0 {
+		return nil
+	}
+
+	step := offset > 0 || backward
+	if !once {
+		step = offset > h.StepThreshold
+	}
+
+	switch {
+	case step:
+		log.Printf("stepping guest clock by %s", offset)
+		return stepf(offset)
+	case slew:
+		return slewf(offset * time.Duration(percent) / 100)
+	}
+
+	return nil
+}
+
+// SetOption applies the given Set_Option time sync key and value
+func (h *TimeSyncHandler) SetOption(key, val string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch key {
+	case timeSyncOptionEnable:
+		h.enabled = val == "1" // applied by Enable if not yet enabled
+		if h.enabled {
+			h.start()
+		} else {
+			h.cancel()
+		}
+	case timeSyncOptionPeriod:
+		n, err := strconv.Atoi(val)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid %s: %q", key, val)
+		}
+		h.period = time.Duration(n) * time.Second
+		if h.done != nil {
+			h.cancel()
+			h.start() // restart with the new period
+		}
+	case timeSyncOptionSlewCorrection:
+		h.slewCorrection = val == "1"
+	case timeSyncOptionPercentCorrection:
+		n, err := strconv.Atoi(val)
+		if err != nil || n <= 0 || n > 100 {
+			return fmt.Errorf("invalid %s: %q", key, val)
+		}
+		h.percentCorrection = n
+	case timeSyncOptionResume:
+		h.resume = val == "1"
+	}
+
+	return nil
+}
+
+// start the periodic sync routine, if not already running.  Must be called with mu held.
+func (h *TimeSyncHandler) start() {
+	if h.done != nil || h.step == nil {
+		return
+	}
+
+	h.done = make(chan struct{})
+
+	go h.periodic(h.done, h.period)
+}
+
+// cancel the periodic sync routine, if running.  Must be called with mu held.
+func (h *TimeSyncHandler) cancel() {
+	if h.done == nil {
+		return
+	}
+
+	close(h.done)
+	h.done = nil
+}
+
+func (h *TimeSyncHandler) periodic(done chan struct{}, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		if err := h.sync(false, false); err != nil {
+			log.Printf("time sync: %s", err)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func isTimeSyncOption(key string) bool {
+	switch key {
+	case timeSyncOptionEnable, timeSyncOptionPeriod, timeSyncOptionSlewCorrection,
+		timeSyncOptionPercentCorrection, timeSyncOptionResume:
+		return true
+	}
+
+	return false
+}
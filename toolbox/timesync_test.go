@@ -0,0 +1,281 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testClock records calls to the TimeSyncHandler Step and Slew functions
+type testClock struct {
+	mu     sync.Mutex
+	offset time.Duration
+	steps  []time.Duration
+	slews  []time.Duration
+}
+
+func (c *testClock) hostTime() (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().Add(c.offset), nil
+}
+
+func (c *testClock) step(d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.steps = append(c.steps, d)
+	return nil
+}
+
+func (c *testClock) slew(d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slews = append(c.slews, d)
+	return nil
+}
+
+func (c *testClock) reset() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	steps, slews := len(c.steps), len(c.slews)
+	c.steps, c.slews = nil, nil
+	return steps, slews
+}
+
+func newTestTimeSync(t *testing.T) (*Service, *testClock) {
+	service := NewService(new(mockChannelIn), new(eventChannelOut))
+	t.Cleanup(service.Stop)
+
+	clock := new(testClock)
+	service.TimeSync.HostTime = clock.hostTime
+	service.TimeSync.Enable(clock.step, clock.slew)
+
+	return service, clock
+}
+
+func TestTimeSyncPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		once     bool
+		backward bool
+		offset   time.Duration
+		slew     bool
+		steps    int
+		slews    int
+	}{
+		{"periodic behind", false, false, time.Minute, true, 1, 0},
+		{"periodic ahead", false, false, -time.Minute, true, 0, 1},
+		{"periodic small", false, false, 100 * time.Millisecond, true, 0, 1},
+		{"periodic no slew", false, false, -time.Minute, false, 0, 0},
+		{"once behind", true, false, 100 * time.Millisecond, false, 1, 0},
+		{"once ahead", true, false, -time.Minute, true, 0, 1},
+		{"once ahead backward", true, true, -time.Minute, true, 1, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, clock := newTestTimeSync(t)
+			clock.offset = test.offset
+			service.TimeSync.slewCorrection = test.slew
+
+			if err := service.TimeSync.sync(test.once, test.backward); err != nil {
+				t.Fatal(err)
+			}
+
+			steps, slews := clock.reset()
+			if steps != test.steps || slews != test.slews {
+				t.Errorf("steps=%d slews=%d, expected steps=%d slews=%d", steps, slews, test.steps, test.slews)
+			}
+		})
+	}
+}
+
+func TestTimeSyncPercentCorrection(t *testing.T) {
+	service, clock := newTestTimeSync(t)
+	clock.offset = -time.Second
+
+	if err := service.TimeSync.SetOption(timeSyncOptionPercentCorrection, "10"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.TimeSync.sync(false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clock.slews) != 1 {
+		t.Fatalf("slews=%d", len(clock.slews))
+	}
+
+	// allow for time elapsed between HostTime and time.Until
+	if d := clock.slews[0]; d > -90*time.Millisecond || d < -110*time.Millisecond {
+		t.Errorf("slew=%s", d)
+	}
+
+	for _, val := range []string{"0", "101", "x"} {
+		if err := service.TimeSync.SetOption(timeSyncOptionPercentCorrection, val); err == nil {
+			t.Errorf("expected error for %q", val)
+		}
+	}
+}
+
+func TestTimeSyncRPC(t *testing.T) {
+	service, clock := newTestTimeSync(t)
+	clock.offset = -time.Minute
+
+	rpc := []struct {
+		cmd    string
+		expect string
+		steps  int
+		slews  int
+	}{
+		{"Time_Synchronize 0", "OK ", 0, 1},
+		{"Time_Synchronize 1", "OK ", 1, 0},
+		{"Set_Option synctime.period 0", "ERR ", 0, 0},
+		{"Set_Option synctime.slewCorrection 0", "OK ", 0, 0},
+		{"Time_Synchronize 0", "OK ", 0, 0},
+	}
+
+	for _, test := range rpc {
+		reply := service.Dispatch([]byte(test.cmd))
+		if string(reply) != test.expect {
+			t.Errorf("%s: reply=%q", test.cmd, reply)
+		}
+
+		steps, slews := clock.reset()
+		if steps != test.steps || slews != test.slews {
+			t.Errorf("%s: steps=%d slews=%d", test.cmd, steps, slews)
+		}
+	}
+
+	service.TimeSync.HostTime = func() (time.Time, error) {
+		return time.Time{}, ErrHostTimeUnavailable
+	}
+
+	if reply := service.Dispatch([]byte("Time_Synchronize 1")); string(reply) != "ERR " {
+		t.Errorf("reply=%q", reply)
+	}
+}
+
+func TestTimeSyncEnable(t *testing.T) {
+	service := NewService(new(mockChannelIn), new(eventChannelOut))
+	t.Cleanup(service.Stop)
+
+	clock := new(testClock)
+	clock.offset = time.Minute
+	service.TimeSync.HostTime = clock.hostTime
+	service.TimeSync.period = 10 * time.Millisecond
+
+	if reply := service.Dispatch([]byte("Time_Synchronize 1")); string(reply) != "Unknown Command" {
+		t.Errorf("reply=%q", reply)
+	}
+
+	if err := service.TimeSync.Sync(true); err != ErrTimeSyncDisabled {
+		t.Errorf("err=%v", err)
+	}
+
+	// periodic sync requested before Enable is started by Enable
+	if reply := service.Dispatch([]byte("Set_Option synctime 1")); string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+
+	service.TimeSync.Enable(clock.step, clock.slew)
+
+	for i := 0; ; i++ {
+		if steps, _ := clock.reset(); steps != 0 {
+			break
+		}
+		if i > 500 {
+			t.Fatal("timeout waiting for periodic sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if reply := service.Dispatch([]byte("Time_Synchronize 1")); string(reply) != "OK " {
+		t.Errorf("reply=%q", reply)
+	}
+}
+
+func TestTimeSyncResume(t *testing.T) {
+	service, clock := newTestTimeSync(t)
+	clock.offset = -time.Minute
+
+	resumed := false
+	service.Power.Resume.Handler = func() error {
+		resumed = true
+		return nil
+	}
+
+	_ = service.Dispatch([]byte("OS_Resume"))
+
+	if steps, _ := clock.reset(); steps != 1 {
+		t.Errorf("steps=%d", steps)
+	}
+	if !resumed {
+		t.Error("Power.Resume.Handler not called")
+	}
+
+	_ = service.Dispatch([]byte("Set_Option time.synchronize.resume.disk 0"))
+	_ = service.Dispatch([]byte("OS_Resume"))
+
+	if steps, _ := clock.reset(); steps != 0 {
+		t.Errorf("steps=%d", steps)
+	}
+
+	service.TimeSync.HostTime = func() (time.Time, error) {
+		return time.Time{}, errors.New("no host time")
+	}
+	_ = service.Dispatch([]byte("Set_Option time.synchronize.resume.disk 1"))
+
+	if err := service.TimeSync.Resume(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTimeSyncPeriodic(t *testing.T) {
+	service, clock := newTestTimeSync(t)
+	clock.offset = time.Minute
+	service.TimeSync.period = 10 * time.Millisecond
+
+	if reply := service.Dispatch([]byte("Set_Option synctime 1")); string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+
+	count := func() int {
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		return len(clock.steps)
+	}
+
+	for i := 0; count() < 3; i++ {
+		if i > 500 {
+			t.Fatal("timeout waiting for periodic sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_ = service.Dispatch([]byte("Set_Option synctime 0"))
+	time.Sleep(20 * time.Millisecond) // sync in progress may complete
+	n := count()
+	time.Sleep(50 * time.Millisecond)
+
+	if count() != n {
+		t.Error("periodic sync not stopped")
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/vmware/govmomi/toolbox"
@@ -41,16 +42,39 @@ import (
 
 // This example can be run on a VM hosted by ESX, Fusion or Workstation
 func main() {
+	vsock := flag.Bool("vsock", false, "Use vsock instead of the backdoor for RPC channels")
+	freeze := flag.String("fsfreeze", "", "Comma separated list of mount points to freeze for quiesced snapshots")
+	appinfo := flag.String("appinfo", "", "Comma separated list of process names to publish to guestinfo.appInfo, '*' for all")
 	flag.Parse()
 
 	in := toolbox.NewBackdoorChannelIn()
 	out := toolbox.NewBackdoorChannelOut()
 
+	if *vsock {
+		in = toolbox.NewVsockChannelIn()
+		out = toolbox.NewVsockChannelOut()
+	}
+
 	service := toolbox.NewService(in, out)
 
+	switch *appinfo {
+	case "":
+	case "*":
+		service.AppInfo.Applications = toolbox.RunningApplications()
+	default:
+		service.AppInfo.Applications = toolbox.RunningApplications(strings.Split(*appinfo, ",")...)
+	}
+
 	if os.Getuid() == 0 {
 		service.Power.Halt.Handler = toolbox.Halt
 		service.Power.Reboot.Handler = toolbox.Reboot
+		service.TimeSync.Enable(toolbox.TimeStep, toolbox.TimeSlew)
+
+		if *freeze != "" {
+			paths := strings.Split(*freeze, ",")
+			service.Backup.Freeze = toolbox.FSFreeze(paths...)
+			service.Backup.Thaw = toolbox.FSThaw(paths...)
+		}
 	}
 
 	err := service.Start()
@@ -18,8 +18,31 @@ package toolbox
 
 import (
 	"os"
+	"syscall"
+	"time"
 )
 
 func fileExtendedInfoFormat(dir string, info os.FileInfo) string {
 	return ""
 }
+
+// TimeStep sets the system clock forward or backward by d
+func TimeStep(d time.Duration) error {
+	tv := syscall.NsecToTimeval(time.Now().Add(d).UnixNano())
+	return syscall.Settimeofday(&tv)
+}
+
+// TimeSlew gradually adjusts the system clock by d, replacing any adjustment in progress
+func TimeSlew(d time.Duration) error {
+	tv := syscall.NsecToTimeval(d.Nanoseconds())
+	return syscall.Adjtime(&tv, nil)
+}
+
+// filesystemUsage returns the size and available space of the file system mounted at path
+func filesystemUsage(path string) (uint64, uint64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, 0, err
+	}
+	return fs.Blocks * uint64(fs.Bsize), fs.Bavail * uint64(fs.Bsize), nil
+}
@@ -67,3 +67,32 @@ func fileExtendedInfoFormat(dir string, info os.FileInfo) string {
 
 	return fmt.Sprintf(format, info.Name(), props, size, mtime, atime, uid, gid, perm, targ)
 }
+
+// ADJ_OFFSET_SINGLESHOT as defined in linux/timex.h
+const adjOffsetSingleshot = 0x8001
+
+// TimeStep sets the system clock forward or backward by d
+func TimeStep(d time.Duration) error {
+	tv := syscall.NsecToTimeval(time.Now().Add(d).UnixNano())
+	return syscall.Settimeofday(&tv)
+}
+
+// TimeSlew gradually adjusts the system clock by d, replacing any adjustment in progress
+func TimeSlew(d time.Duration) error {
+	tv := syscall.NsecToTimeval(d.Nanoseconds())
+	tx := syscall.Timex{
+		Modes:  adjOffsetSingleshot,
+		Offset: tv.Sec*1e6 + tv.Usec,
+	}
+	_, err := syscall.Adjtimex(&tx)
+	return err
+}
+
+// filesystemUsage returns the size and available space of the file system mounted at path
+func filesystemUsage(path string) (uint64, uint64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, 0, err
+	}
+	return fs.Blocks * uint64(fs.Bsize), fs.Bavail * uint64(fs.Bsize), nil
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package toolbox
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 )
 
 func fileExtendedInfoFormat(dir string, info os.FileInfo) string {
@@ -39,3 +41,20 @@ func fileExtendedInfoFormat(dir string, info os.FileInfo) string {
 
 	return fmt.Sprintf(format, info.Name(), props, size, mtime, ctime, atime)
 }
+
+var errTimeSyncNotSupported = errors.New("time sync not supported")
+
+// TimeStep is not supported on Windows
+func TimeStep(time.Duration) error {
+	return errTimeSyncNotSupported
+}
+
+// TimeSlew is not supported on Windows
+func TimeSlew(time.Duration) error {
+	return errTimeSyncNotSupported
+}
+
+// filesystemUsage is not supported on Windows
+func filesystemUsage(string) (uint64, uint64, error) {
+	return 0, 0, errors.New("file system usage not supported")
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// vmbackup events as defined in open-vm-tools/lib/include/vmBackupSignals.h
+const (
+	vmbackupEventRequestorAbort = "req.aborted"
+	vmbackupEventRequestorDone  = "req.done"
+	vmbackupEventRequestorError = "req.error"
+	vmbackupEventSnapshotCommit = "prov.snapshotCommit"
+	vmbackupEventKeepAlive      = "req.keepAlive"
+)
+
+// vmbackup status codes as defined in open-vm-tools/lib/include/vmBackupSignals.h
+const (
+	vmbackupSuccess = iota
+	vmbackupInvalidState
+	vmbackupScriptError
+	vmbackupSyncError
+	vmbackupRemoteAbort
+	vmbackupUnexpectedError
+)
+
+// vmbackup operation states
+const (
+	vmbackupIdle = iota
+	vmbackupFreezing
+	vmbackupFrozen
+	vmbackupAborting
+	vmbackupThawing
+)
+
+var (
+	fsfreeze = "/sbin/fsfreeze"
+
+	// VMBACKUP_KEEP_ALIVE_PERIOD / 2 as defined in open-vm-tools/services/plugins/vmbackup/vmBackupInt.h
+	backupKeepAlive = 15 * time.Second
+)
+
+// BackupCommandHandler implements the vmbackup protocol, used by the VMX to take quiesced snapshots.
+// When a quiesced snapshot is requested, Freeze is called before the VMX takes the snapshot and Thaw is called
+// once the snapshot is complete or the operation is aborted. Thaw is also called if Freeze returns an error,
+// to undo any partial freeze. A nil Freeze or Thaw function is treated as a no-op.
+type BackupCommandHandler struct {
+	Freeze func() error
+	Thaw   func() error
+
+	out   *ChannelOut
+	mu    sync.Mutex
+	state int
+	done  chan struct{}
+}
+
+func registerBackupCommandHandler(service *Service) *BackupCommandHandler {
+	handler := &BackupCommandHandler{out: service.out}
+
+	service.RegisterHandler("vmbackup.start", handler.Start)
+	service.RegisterHandler("vmbackup.startWithOpts", handler.Start)
+	service.RegisterHandler("vmbackup.snapshotDone", handler.SnapshotDone)
+	service.RegisterHandler("vmbackup.abort", handler.Abort)
+
+	return handler
+}
+
+// Start handles the vmbackup.start RPC, starting the Freeze hook in the background.
+// The VMX is notified via a prov.snapshotCommit event when the guest is ready for the snapshot.
+func (h *BackupCommandHandler) Start([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != vmbackupIdle {
+		return nil, errors.New("backup operation already in progress")
+	}
+
+	log.Printf("starting quiesced snapshot")
+
+	h.state = vmbackupFreezing
+	h.done = make(chan struct{})
+
+	go h.keepAlive(h.done, backupKeepAlive)
+	go h.freeze()
+
+	return nil, nil
+}
+
+// SnapshotDone handles the vmbackup.snapshotDone RPC, starting the Thaw hook in the background.
+// The VMX is notified via a req.done event when the operation is complete.
+func (h *BackupCommandHandler) SnapshotDone([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != vmbackupFrozen {
+		return nil, fmt.Errorf("invalid backup state: %d", h.state)
+	}
+
+	h.state = vmbackupThawing
+	go h.thaw(vmbackupSuccess)
+
+	return nil, nil
+}
+
+// Abort handles the vmbackup.abort RPC, calling the Thaw hook if the guest is frozen or once Freeze returns.
+// The VMX is notified via a req.aborted event when the operation is complete.
+func (h *BackupCommandHandler) Abort([]byte) ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch h.state {
+	case vmbackupFreezing:
+		h.state = vmbackupAborting // thawed once freeze returns
+	case vmbackupFrozen:
+		h.state = vmbackupThawing
+		go h.thaw(vmbackupRemoteAbort)
+	default:
+		return nil, errors.New("no backup operation in progress")
+	}
+
+	return nil, nil
+}
+
+func (h *BackupCommandHandler) freeze() {
+	var err error
+	if h.Freeze != nil {
+		err = h.Freeze()
+	}
+
+	h.mu.Lock()
+	state := h.state
+	if state == vmbackupFreezing && err == nil {
+		h.state = vmbackupFrozen
+	} else {
+		h.state = vmbackupThawing
+	}
+	h.mu.Unlock()
+
+	switch {
+	case state == vmbackupAborting:
+		h.thaw(vmbackupRemoteAbort)
+	case err != nil:
+		log.Printf("vmbackup freeze: %s", err)
+		h.thaw(vmbackupSyncError)
+	default:
+		h.event(vmbackupEventSnapshotCommit, vmbackupSuccess, "")
+	}
+}
+
+func (h *BackupCommandHandler) thaw(status int) {
+	var err error
+	if h.Thaw != nil {
+		err = h.Thaw()
+	}
+
+	switch {
+	case status == vmbackupSyncError:
+		h.event(vmbackupEventRequestorError, status, "Error when enabling the sync provider.")
+	case err != nil:
+		log.Printf("vmbackup thaw: %s", err)
+		h.event(vmbackupEventRequestorError, vmbackupSyncError, err.Error())
+	case status == vmbackupRemoteAbort:
+		h.event(vmbackupEventRequestorAbort, status, "Quiesce aborted.")
+	default:
+		h.event(vmbackupEventRequestorDone, status, "")
+	}
+
+	h.mu.Lock()
+	close(h.done)
+	h.state = vmbackupIdle
+	h.mu.Unlock()
+}
+
+// keepAlive periodically notifies the VMX that the backup operation is still in progress
+func (h *BackupCommandHandler) keepAlive(done chan struct{}, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			h.event(vmbackupEventKeepAlive, vmbackupSuccess, "")
+		}
+	}
+}
+
+func (h *BackupCommandHandler) event(name string, code int, desc string) {
+	msg := fmt.Sprintf("vmbackup.eventSet %s %d %s", name, code, desc)
+
+	if _, err := h.out.Request([]byte(msg)); err != nil {
+		log.Printf("unable to send %q: %s", msg, err)
+	}
+}
+
+// FSFreeze returns a BackupCommandHandler.Freeze function that uses fsfreeze(8) to suspend access to
+// the filesystems mounted at the given paths. If any path fails to freeze, those already frozen are thawed.
+func FSFreeze(paths ...string) func() error {
+	return func() error {
+		for i, path := range paths {
+			// #nosec: Subprocess launching with variable
+			if out, err := exec.Command(fsfreeze, "--freeze", path).CombinedOutput(); err != nil {
+				_ = FSThaw(paths[:i]...)()
+				return fmt.Errorf("fsfreeze %s: %s: %s", path, err, out)
+			}
+		}
+		return nil
+	}
+}
+
+// FSThaw returns a BackupCommandHandler.Thaw function that uses fsfreeze(8) to resume access to
+// the filesystems mounted at the given paths, in the reverse order of FSFreeze.
+func FSThaw(paths ...string) func() error {
+	return func() error {
+		var err error
+		for i := len(paths) - 1; i >= 0; i-- {
+			// #nosec: Subprocess launching with variable
+			if out, xerr := exec.Command(fsfreeze, "--unfreeze", paths[i]).CombinedOutput(); xerr != nil && err == nil {
+				err = fmt.Errorf("fsfreeze %s: %s: %s", paths[i], xerr, out)
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// eventChannelOut records requests sent to the vmx, replying OK to each
+type eventChannelOut struct {
+	mu    sync.Mutex
+	sent  []string
+	reply bool
+}
+
+func (c *eventChannelOut) Start() error {
+	return nil
+}
+
+func (c *eventChannelOut) Stop() error {
+	return nil
+}
+
+func (c *eventChannelOut) Send(buf []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, string(buf))
+	c.reply = true
+	return nil
+}
+
+func (c *eventChannelOut) Receive() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.reply {
+		return nil, io.EOF
+	}
+	c.reply = false
+	return rpciOK, nil
+}
+
+// wait for the given vmbackup event
+func (c *eventChannelOut) wait(t *testing.T, event string) {
+	t.Helper()
+	c.waitFor(t, "vmbackup.eventSet "+event)
+}
+
+// waitFor a request with the given prefix, returning the request
+func (c *eventChannelOut) waitFor(t *testing.T, prefix string) string {
+	t.Helper()
+
+	for i := 0; i < 500; i++ {
+		c.mu.Lock()
+		for j, msg := range c.sent {
+			if strings.HasPrefix(msg, prefix) {
+				c.sent = c.sent[j+1:]
+				c.mu.Unlock()
+				return msg
+			}
+		}
+		c.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("timeout waiting for %q", prefix)
+	return ""
+}
+
+func TestBackupCommandHandler(t *testing.T) {
+	out := new(eventChannelOut)
+	service := NewService(new(mockChannelIn), out)
+
+	var calls []string
+	service.Backup.Freeze = func() error {
+		calls = append(calls, "freeze")
+		return nil
+	}
+	service.Backup.Thaw = func() error {
+		calls = append(calls, "thaw")
+		return nil
+	}
+
+	reply := service.Dispatch([]byte("vmbackup.start 0"))
+	if string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+
+	out.wait(t, vmbackupEventSnapshotCommit)
+
+	reply = service.Dispatch([]byte("vmbackup.start 0"))
+	if !strings.HasPrefix(string(reply), "ERR") {
+		t.Errorf("reply=%q", reply)
+	}
+
+	reply = service.Dispatch([]byte("vmbackup.snapshotDone"))
+	if string(reply) != "OK " {
+		t.Fatalf("reply=%q", reply)
+	}
+
+	out.wait(t, vmbackupEventRequestorDone+" 0")
+
+	if strings.Join(calls, ",") != "freeze,thaw" {
+		t.Errorf("calls=%v", calls)
+	}
+
+	reply = service.Dispatch([]byte("vmbackup.snapshotDone"))
+	if !strings.HasPrefix(string(reply), "ERR") {
+		t.Errorf("reply=%q", reply)
+	}
+
+	// abort while frozen
+	_ = service.Dispatch([]byte("vmbackup.start"))
+	out.wait(t, vmbackupEventSnapshotCommit)
+	_ = service.Dispatch([]byte("vmbackup.abort"))
+	out.wait(t, vmbackupEventRequestorAbort+" 4")
+
+	// abort while freezing
+	release := make(chan struct{})
+	service.Backup.Freeze = func() error {
+		<-release
+		return nil
+	}
+	_ = service.Dispatch([]byte("vmbackup.start"))
+	_ = service.Dispatch([]byte("vmbackup.abort"))
+	close(release)
+	out.wait(t, vmbackupEventRequestorAbort+" 4")
+
+	// freeze error
+	service.Backup.Freeze = func() error {
+		return errors.New("freeze failed")
+	}
+	_ = service.Dispatch([]byte("vmbackup.start"))
+	out.wait(t, vmbackupEventRequestorError+" 3")
+
+	reply = service.Dispatch([]byte("vmbackup.abort"))
+	for i := 0; i < 100 && string(reply) == "OK "; i++ {
+		// the freeze goroutine may not have reset the state yet
+		time.Sleep(10 * time.Millisecond)
+		reply = service.Dispatch([]byte("vmbackup.abort"))
+	}
+	if !strings.HasPrefix(string(reply), "ERR") {
+		t.Errorf("reply=%q", reply)
+	}
+}
+
+func TestBackupKeepAlive(t *testing.T) {
+	backupKeepAlive = 10 * time.Millisecond
+	defer func() { backupKeepAlive = 15 * time.Second }()
+
+	out := new(eventChannelOut)
+	service := NewService(new(mockChannelIn), out)
+
+	release := make(chan struct{})
+	service.Backup.Freeze = func() error {
+		<-release
+		return nil
+	}
+
+	_ = service.Dispatch([]byte("vmbackup.start"))
+	out.wait(t, vmbackupEventKeepAlive)
+	close(release)
+	out.wait(t, vmbackupEventSnapshotCommit)
+
+	_ = service.Dispatch([]byte("vmbackup.snapshotDone"))
+	out.wait(t, vmbackupEventRequestorDone)
+}
+
+func TestFSFreeze(t *testing.T) {
+	fsfreeze = "/bin/echo"
+	defer func() { fsfreeze = "/sbin/fsfreeze" }()
+
+	if err := FSFreeze("/a", "/b")(); err != nil {
+		t.Error(err)
+	}
+	if err := FSThaw("/a", "/b")(); err != nil {
+		t.Error(err)
+	}
+
+	fsfreeze = "/bin/false"
+
+	if err := FSFreeze("/a")(); err == nil {
+		t.Error("expected error")
+	}
+	if err := FSThaw("/a")(); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,318 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// GUESTRPC_TCLO_VSOCK_LISTEN_PORT and GUESTRPC_RPCI_VSOCK_LISTEN_PORT as defined by open-vm-tools
+	tcloVsockPort = 975
+	rpciVsockPort = 976
+
+	// PRIVILEGED_PORT_MAX and PRIVILEGED_PORT_MIN as defined by open-vm-tools
+	privilegedPortMax = 1023
+	privilegedPortMin = 1
+
+	// maxPacketSize limits the size of a stream channel packet
+	maxPacketSize = 16 * 1024 * 1024
+)
+
+// GuestRpcPacketType and GuestRpcPacketFieldId as defined by open-vm-tools
+const (
+	guestRpcPacketTypeData = 1
+
+	guestRpcFieldType    = 1
+	guestRpcFieldPayload = 2
+)
+
+// DMFieldType as defined by open-vm-tools
+const (
+	dataMapInt64      = 1
+	dataMapString     = 2
+	dataMapInt64List  = 3
+	dataMapStringList = 4
+)
+
+var (
+	// streamReplyTimeout is the time a stream channel waits for the reply to an RPCI request
+	streamReplyTimeout = 10 * time.Second
+)
+
+// streamChannel implements the Channel interface over a stream connection, such as vsock.
+// Each message is sent as a GuestRpc packet, see writePacket.
+// Unlike the backdoor, stream channels do not need to poll for requests: sending an empty message only checks
+// the connection is still alive and Receive returns a packet if one is available, waiting up to the channel's wait duration.
+type streamChannel struct {
+	dial func() (io.ReadWriteCloser, error)
+	wait time.Duration
+
+	conn *streamConn
+}
+
+// streamConn reads packets from a connection in the background
+type streamConn struct {
+	io.ReadWriteCloser
+
+	packets chan []byte
+	done    chan struct{}
+	exit    chan struct{}
+	err     error
+}
+
+func (c *streamChannel) Start() error {
+	conn, err := c.dial()
+	if err != nil {
+		return err
+	}
+
+	c.conn = &streamConn{
+		ReadWriteCloser: conn,
+		packets:         make(chan []byte),
+		done:            make(chan struct{}),
+		exit:            make(chan struct{}),
+	}
+
+	go c.conn.read()
+
+	return nil
+}
+
+func (c *streamChannel) Stop() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	close(c.conn.done)
+	err := c.conn.Close()
+	c.conn = nil
+
+	return err
+}
+
+func (c *streamChannel) Send(buf []byte) error {
+	if c.conn == nil {
+		return os.ErrClosed
+	}
+
+	select {
+	case <-c.conn.exit:
+		return c.conn.err // connection closed by the peer
+	default:
+	}
+
+	if len(buf) == 0 {
+		return nil
+	}
+
+	return writePacket(c.conn, buf)
+}
+
+func (c *streamChannel) Receive() ([]byte, error) {
+	if c.conn == nil {
+		return nil, os.ErrClosed
+	}
+
+	if c.wait == 0 {
+		select {
+		case packet := <-c.conn.packets:
+			return packet, nil
+		case <-c.conn.exit:
+			return nil, c.conn.err
+		default:
+			return nil, io.EOF
+		}
+	}
+
+	timer := time.NewTimer(c.wait)
+	defer timer.Stop()
+
+	select {
+	case packet := <-c.conn.packets:
+		return packet, nil
+	case <-c.conn.exit:
+		return nil, c.conn.err
+	case <-timer.C:
+		return nil, os.ErrDeadlineExceeded
+	}
+}
+
+// read packets until an error occurs or the channel is stopped
+func (c *streamConn) read() {
+	for {
+		packet, err := readPacket(c)
+		if err != nil {
+			c.err = err
+			close(c.exit)
+			return
+		}
+
+		select {
+		case c.packets <- packet:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// writePacket writes buf as the payload of a GuestRpc data packet, the same format as the open-vm-tools
+// Socket_SendPacket function: a serialized DataMap with the packet type and payload fields.
+// A serialized DataMap is prefixed with its length and each field is encoded as its type, id and value,
+// all integers in network byte order.
+func writePacket(w io.Writer, buf []byte) error {
+	packet := make([]byte, 4+16+12+len(buf))
+	be := binary.BigEndian
+
+	be.PutUint32(packet, uint32(len(packet)-4))
+
+	be.PutUint32(packet[4:], dataMapInt64)
+	be.PutUint32(packet[8:], guestRpcFieldType)
+	be.PutUint64(packet[12:], guestRpcPacketTypeData)
+
+	be.PutUint32(packet[20:], dataMapString)
+	be.PutUint32(packet[24:], guestRpcFieldPayload)
+	be.PutUint32(packet[28:], uint32(len(buf)))
+	copy(packet[32:], buf)
+
+	_, err := w.Write(packet)
+	return err
+}
+
+// readPacket returns the payload of the next GuestRpc data packet, other packet types are ignored.
+func readPacket(r io.Reader) ([]byte, error) {
+	for {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, err
+		}
+
+		if size > maxPacketSize {
+			return nil, fmt.Errorf("packet size %d exceeds limit", size)
+		}
+
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+
+		typ, payload, err := decodePacket(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if typ == guestRpcPacketTypeData {
+			if payload == nil {
+				return nil, errors.New("packet has no payload")
+			}
+			return payload, nil
+		}
+	}
+}
+
+var errPacketTruncated = errors.New("packet truncated")
+
+// decodePacket decodes the packet type and payload fields of a serialized DataMap, without the length prefix.
+func decodePacket(buf []byte) (int64, []byte, error) {
+	var typ int64
+	var payload []byte
+
+	next := func(n int) ([]byte, error) {
+		if n < 0 || n > len(buf) {
+			return nil, errPacketTruncated
+		}
+		b := buf[:n]
+		buf = buf[n:]
+		return b, nil
+	}
+
+	length := func() (int, error) {
+		b, err := next(4)
+		if err != nil {
+			return 0, err
+		}
+		return int(int32(binary.BigEndian.Uint32(b))), nil
+	}
+
+	for len(buf) != 0 {
+		b, err := next(8)
+		if err != nil {
+			return 0, nil, err
+		}
+		ftype, id := binary.BigEndian.Uint32(b), binary.BigEndian.Uint32(b[4:])
+
+		switch ftype {
+		case dataMapInt64:
+			if b, err = next(8); err == nil && id == guestRpcFieldType {
+				typ = int64(binary.BigEndian.Uint64(b))
+			}
+		case dataMapString:
+			var n int
+			if n, err = length(); err == nil {
+				if b, err = next(n); err == nil && id == guestRpcFieldPayload {
+					payload = append([]byte{}, b...)
+				}
+			}
+		case dataMapInt64List:
+			var n int
+			if n, err = length(); err == nil {
+				_, err = next(n * 8)
+			}
+		case dataMapStringList:
+			var n int
+			if n, err = length(); err == nil {
+				for i := 0; i < n && err == nil; i++ {
+					if n, err = length(); err == nil {
+						_, err = next(n)
+					}
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown packet field type %d", ftype)
+		}
+
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	return typ, payload, nil
+}
+
+// NewVsockChannelOut creates a Channel for use with the RPCI protocol over AF_VSOCK.
+// Unlike the backdoor, vsock does not require the guest to execute privileged I/O instructions.
+// The connection is made from a reserved source port if the process is privileged to bind one,
+// otherwise from an unprivileged port, in which case the VMX restricts the RPCs it accepts.
+func NewVsockChannelOut() Channel {
+	return &streamChannel{
+		dial: dialVsock(rpciVsockPort),
+		wait: streamReplyTimeout,
+	}
+}
+
+// NewVsockChannelIn creates a Channel for use with the TCLO protocol over AF_VSOCK.
+// See NewVsockChannelOut.
+func NewVsockChannelIn() Channel {
+	return &streamChannel{
+		dial: dialVsock(tcloVsockPort),
+	}
+}
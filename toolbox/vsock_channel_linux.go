@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package toolbox
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// dialVsock returns a function that connects to the host vsock listener on the given port
+func dialVsock(port uint32) func() (io.ReadWriteCloser, error) {
+	return func() (io.ReadWriteCloser, error) {
+		fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return nil, os.NewSyscallError("socket", err)
+		}
+
+		if err = bindReservedPort(fd); err != nil {
+			_ = unix.Close(fd)
+			return nil, err
+		}
+
+		addr := &unix.SockaddrVM{CID: unix.VMADDR_CID_HYPERVISOR, Port: port}
+
+		if err = unix.Connect(fd, addr); err != nil {
+			_ = unix.Close(fd)
+			return nil, os.NewSyscallError("connect", err)
+		}
+
+		// non-blocking mode allows Close to interrupt a pending Read
+		if err = unix.SetNonblock(fd, true); err != nil {
+			_ = unix.Close(fd)
+			return nil, os.NewSyscallError("setnonblock", err)
+		}
+
+		return os.NewFile(uintptr(fd), "vsock"), nil
+	}
+}
+
+// bindReservedPort binds fd to the highest available reserved port, as open-vm-tools does for privileged connections.
+// If the process is not privileged to bind a reserved port, fd is left unbound and connects from an ephemeral port.
+func bindReservedPort(fd int) error {
+	for port := privilegedPortMax; port >= privilegedPortMin; port-- {
+		err := unix.Bind(fd, &unix.SockaddrVM{CID: unix.VMADDR_CID_ANY, Port: uint32(port)})
+		switch err {
+		case nil:
+			return nil
+		case unix.EADDRINUSE:
+			continue
+		case unix.EACCES, unix.EPERM:
+			return nil // unprivileged
+		default:
+			return os.NewSyscallError("bind", err)
+		}
+	}
+
+	return errors.New("no reserved vsock port available")
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	Path      = "/api/appliance/health"
+	LastCheck = "lastcheck"
+)
+
+// Health components
+const (
+	System           = "system"
+	Applmgmt         = "applmgmt"
+	Database         = "database"
+	DatabaseStorage  = "database-storage"
+	Load             = "load"
+	Mem              = "mem"
+	SoftwarePackages = "software-packages"
+	Storage          = "storage"
+	Swap             = "swap"
+)
+
+// Components lists the health components of the appliance, System being the overall health.
+var Components = []string{
+	System,
+	Applmgmt,
+	Database,
+	DatabaseStorage,
+	Load,
+	Mem,
+	SoftwarePackages,
+	Storage,
+	Swap,
+}
+
+// Health levels
+const (
+	Green   = "green"
+	Yellow  = "yellow"
+	Orange  = "orange"
+	Red     = "red"
+	Gray    = "gray"
+	Unknown = "unknown"
+)
+
+// Manager provides convenience methods for the appliance health API
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Get returns the health level of the given component.
+func (m *Manager) Get(ctx context.Context, component string) (string, error) {
+	r := m.Resource(path.Join(Path, component))
+
+	var level string
+
+	return level, m.Do(ctx, r.Request(http.MethodGet), &level)
+}
+
+// LastCheck returns the time of the last overall health check.
+func (m *Manager) LastCheck(ctx context.Context) (string, error) {
+	r := m.Resource(path.Join(Path, System, LastCheck))
+
+	var t string
+
+	return t, m.Do(ctx, r.Request(http.MethodGet), &t)
+}
+
+// Status is the health level of a component.
+type Status struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+// List returns the health level of all Components.
+func (m *Manager) List(ctx context.Context) ([]Status, error) {
+	var res []Status
+
+	for _, c := range Components {
+		level, err := m.Get(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Status{Component: c, Level: level})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	JobPath   = "/api/appliance/recovery/backup/job"
+	PartsPath = "/api/appliance/recovery/backup/parts"
+
+	Action = "action"
+	Cancel = "cancel"
+)
+
+// Location types supported by file-based backup
+const (
+	LocationFTP   = "FTP"
+	LocationFTPS  = "FTPS"
+	LocationHTTP  = "HTTP"
+	LocationHTTPS = "HTTPS"
+	LocationSCP   = "SCP"
+	LocationSFTP  = "SFTP"
+	LocationNFS   = "NFS"
+	LocationSMB   = "SMB"
+)
+
+// Backup job states
+const (
+	StateFailed     = "FAILED"
+	StateInProgress = "INPROGRESS"
+	StateNone       = "NONE"
+	StateSucceeded  = "SUCCEEDED"
+)
+
+// Manager provides convenience methods for the appliance file-based backup API
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Request defines a backup job to create via the Job.create operation
+type Request struct {
+	Parts            []string `json:"parts,omitempty"`
+	BackupPassword   string   `json:"backup_password,omitempty"`
+	LocationType     string   `json:"location_type"`
+	Location         string   `json:"location"`
+	LocationUser     string   `json:"location_user,omitempty"`
+	LocationPassword string   `json:"location_password,omitempty"`
+	Comment          string   `json:"comment,omitempty"`
+}
+
+// Status defines a backup job returned by the Job.get operation
+type Status struct {
+	ID         string                    `json:"id"`
+	State      string                    `json:"state"`
+	Progress   int64                     `json:"progress"`
+	Messages   []rest.LocalizableMessage `json:"messages"`
+	StartTime  string                    `json:"start_time"`
+	EndTime    string                    `json:"end_time,omitempty"`
+	Location   string                    `json:"location,omitempty"`
+	Parts      []string                  `json:"parts,omitempty"`
+	Size       int64                     `json:"size,omitempty"`
+	Comment    string                    `json:"comment,omitempty"`
+	Cancelable bool                      `json:"cancelable"`
+}
+
+// Create starts a new backup job.
+func (m *Manager) Create(ctx context.Context, req Request) (Status, error) {
+	r := m.Resource(JobPath)
+
+	var res Status
+
+	return res, m.Do(ctx, r.Request(http.MethodPost, req), &res)
+}
+
+// List returns the IDs of all backup jobs.
+func (m *Manager) List(ctx context.Context) ([]string, error) {
+	r := m.Resource(JobPath)
+
+	var res []string
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+// Get returns the status of the backup job with the given ID.
+func (m *Manager) Get(ctx context.Context, id string) (Status, error) {
+	r := m.Resource(path.Join(JobPath, id))
+
+	var res Status
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+// Cancel cancels the backup job with the given ID.
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	r := m.Resource(path.Join(JobPath, id)).WithParam(Action, Cancel)
+
+	return m.Do(ctx, r.Request(http.MethodPost), nil)
+}
+
+// Part defines a part of the appliance that can be included in a backup
+type Part struct {
+	ID                string                  `json:"id"`
+	Name              rest.LocalizableMessage `json:"name"`
+	Description       rest.LocalizableMessage `json:"description"`
+	SelectedByDefault bool                    `json:"selected_by_default"`
+	Optional          bool                    `json:"optional"`
+}
+
+// Parts returns the parts of the appliance that can be included in a backup.
+func (m *Manager) Parts(ctx context.Context) ([]Part, error) {
+	r := m.Resource(PartsPath)
+
+	var res []Part
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
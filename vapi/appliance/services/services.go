@@ -0,0 +1,171 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	// Path is the endpoint for appliance (systemd) services
+	Path = "/api/appliance/services"
+	// VmonPath is the endpoint for services managed by the vMon service lifecycle manager
+	VmonPath = "/api/vcenter/services"
+
+	Action  = "action"
+	Start   = "start"
+	Stop    = "stop"
+	Restart = "restart"
+)
+
+// Service states
+const (
+	StateStarting = "STARTING"
+	StateStopping = "STOPPING"
+	StateStarted  = "STARTED"
+	StateStopped  = "STOPPED"
+)
+
+// Startup types of vMon services
+const (
+	StartupManual    = "MANUAL"
+	StartupAutomatic = "AUTOMATIC"
+	StartupDisabled  = "DISABLED"
+)
+
+// Health of vMon services
+const (
+	HealthDegraded = "DEGRADED"
+	HealthHealthy  = "HEALTHY"
+	HealthWarning  = "HEALTHY_WITH_WARNINGS"
+)
+
+// Manager provides convenience methods for the appliance service lifecycle API
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Info defines an appliance service returned by the Services.get operation
+type Info struct {
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+// List returns the appliance services, keyed by service name.
+func (m *Manager) List(ctx context.Context) (map[string]Info, error) {
+	r := m.Resource(Path)
+
+	var res map[string]Info
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+// Get returns the appliance service with the given name.
+func (m *Manager) Get(ctx context.Context, name string) (Info, error) {
+	r := m.Resource(path.Join(Path, name))
+
+	var res Info
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+func (m *Manager) action(ctx context.Context, p, name, action string) error {
+	r := m.Resource(path.Join(p, name)).WithParam(Action, action)
+
+	return m.Do(ctx, r.Request(http.MethodPost), nil)
+}
+
+// Start starts the appliance service with the given name.
+func (m *Manager) Start(ctx context.Context, name string) error {
+	return m.action(ctx, Path, name, Start)
+}
+
+// Stop stops the appliance service with the given name.
+func (m *Manager) Stop(ctx context.Context, name string) error {
+	return m.action(ctx, Path, name, Stop)
+}
+
+// Restart restarts the appliance service with the given name.
+func (m *Manager) Restart(ctx context.Context, name string) error {
+	return m.action(ctx, Path, name, Restart)
+}
+
+// VmonInfo defines a vMon service returned by the Services.get operation
+type VmonInfo struct {
+	NameKey        string                    `json:"name_key"`
+	DescriptionKey string                    `json:"description_key"`
+	StartupType    string                    `json:"startup_type"`
+	State          string                    `json:"state"`
+	Health         string                    `json:"health,omitempty"`
+	HealthMessages []rest.LocalizableMessage `json:"health_messages,omitempty"`
+}
+
+// VmonUpdateSpec defines the changes to a vMon service
+type VmonUpdateSpec struct {
+	StartupType string `json:"startup_type,omitempty"`
+}
+
+// ListVmon returns the vMon services, keyed by service name.
+func (m *Manager) ListVmon(ctx context.Context) (map[string]VmonInfo, error) {
+	r := m.Resource(VmonPath)
+
+	var res map[string]VmonInfo
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+// GetVmon returns the vMon service with the given name.
+func (m *Manager) GetVmon(ctx context.Context, name string) (VmonInfo, error) {
+	r := m.Resource(path.Join(VmonPath, name))
+
+	var res VmonInfo
+
+	return res, m.Do(ctx, r.Request(http.MethodGet), &res)
+}
+
+// UpdateVmon applies the given changes to the vMon service with the given name.
+func (m *Manager) UpdateVmon(ctx context.Context, name string, spec VmonUpdateSpec) error {
+	r := m.Resource(path.Join(VmonPath, name))
+
+	return m.Do(ctx, r.Request(http.MethodPatch, spec), nil)
+}
+
+// StartVmon starts the vMon service with the given name.
+func (m *Manager) StartVmon(ctx context.Context, name string) error {
+	return m.action(ctx, VmonPath, name, Start)
+}
+
+// StopVmon stops the vMon service with the given name.
+func (m *Manager) StopVmon(ctx context.Context, name string) error {
+	return m.action(ctx, VmonPath, name, Stop)
+}
+
+// RestartVmon restarts the vMon service with the given name.
+func (m *Manager) RestartVmon(ctx context.Context, name string) error {
+	return m.action(ctx, VmonPath, name, Restart)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// backupParts are the parts of the appliance that can be included in a backup, with their simulated size in MB
+var backupParts = []struct {
+	backup.Part
+	size int64
+}{
+	{backup.Part{ID: "common", SelectedByDefault: true}, 512},
+	{backup.Part{ID: "seat", SelectedByDefault: true, Optional: true}, 2048},
+}
+
+var backupLocations = map[string]string{
+	"ftp":   backup.LocationFTP,
+	"ftps":  backup.LocationFTPS,
+	"http":  backup.LocationHTTP,
+	"https": backup.LocationHTTPS,
+	"scp":   backup.LocationSCP,
+	"sftp":  backup.LocationSFTP,
+	"nfs":   backup.LocationNFS,
+	"smb":   backup.LocationSMB,
+}
+
+func message(id, format string, args ...interface{}) rest.LocalizableMessage {
+	return rest.LocalizableMessage{
+		ID:             id,
+		DefaultMessage: fmt.Sprintf(format, args...),
+		Args:           []string{},
+	}
+}
+
+func (h *Handler) backupParts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	var res []backup.Part
+	for _, p := range backupParts {
+		part := p.Part
+		part.Name = message("com.vmware.applmgmt.backup.parts."+p.ID, p.ID)
+		part.Description = message("com.vmware.applmgmt.backup.parts."+p.ID+".description", "%s data", p.ID)
+		res = append(res, part)
+	}
+
+	vapi.StatusOK(w, res)
+}
+
+func (h *Handler) backupJobs(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []string{}
+		for id := range h.backups {
+			res = append(res, id)
+		}
+		sort.Strings(res)
+		vapi.StatusOK(w, res)
+	case http.MethodPost:
+		var req backup.Request
+		if !h.decode(r, w, &req) {
+			vapi.APIError(w, rest.ErrorInvalidArgument, "invalid backup request")
+			return
+		}
+
+		u, err := url.Parse(req.Location)
+		if err != nil || u.Host == "" || backupLocations[u.Scheme] != req.LocationType {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid %s location: %q", req.LocationType, req.Location))
+			return
+		}
+
+		if len(req.Parts) == 0 {
+			for _, p := range backupParts {
+				if p.SelectedByDefault {
+					req.Parts = append(req.Parts, p.ID)
+				}
+			}
+		}
+
+		var size int64
+		for _, id := range req.Parts {
+			found := false
+			for _, p := range backupParts {
+				if p.ID == id {
+					size += p.size
+					found = true
+				}
+			}
+			if !found {
+				vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid part: %q", id))
+				return
+			}
+		}
+
+		now := time.Now().UTC()
+		job := &backup.Status{
+			ID:         now.Format("20060102-150405") + fmt.Sprintf("-%d", len(h.backups)),
+			State:      backup.StateInProgress,
+			Messages:   []rest.LocalizableMessage{},
+			StartTime:  now.Format(time.RFC3339),
+			Location:   req.Location,
+			Parts:      req.Parts,
+			Size:       size,
+			Comment:    req.Comment,
+			Cancelable: true,
+		}
+		h.backups[job.ID] = job
+
+		vapi.StatusOK(w, job)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// backupJob handles the status and cancelation of a backup job.
+// A job remains in progress until its status is first retrieved, at which point it succeeds.
+func (h *Handler) backupJob(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, backup.JobPath+"/")
+	job, ok := h.backups[id]
+	if !ok {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("backup job %q not found", id))
+		return
+	}
+
+	end := func(state string, msg rest.LocalizableMessage) {
+		job.State = state
+		job.Cancelable = false
+		job.EndTime = time.Now().UTC().Format(time.RFC3339)
+		job.Messages = append(job.Messages, msg)
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if job.State == backup.StateInProgress {
+			job.Progress = 100
+			end(backup.StateSucceeded, message("com.vmware.applmgmt.backup.job.succeeded", "Backup job %s succeeded", id))
+		}
+		vapi.StatusOK(w, job)
+	case http.MethodPost:
+		if action := r.URL.Query().Get(backup.Action); action != backup.Cancel {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", action))
+			return
+		}
+		if job.State != backup.StateInProgress {
+			vapi.APIError(w, rest.ErrorNotAllowedInCurrentState, fmt.Sprintf("backup job %s is %s", id, job.State))
+			return
+		}
+		end(backup.StateFailed, message("com.vmware.applmgmt.backup.job.canceled", "Backup job %s canceled", id))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vmware/govmomi/vapi/appliance/services"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+)
+
+// applianceServices are a subset of the systemd services of a vCenter appliance
+var applianceServices = map[string]string{
+	"appliance-shell": "Appliance Shell",
+	"applmgmt":        "VMware Appliance Management Service",
+	"ntpd":            "ntpd.service",
+	"sshd":            "sshd.service",
+	"syslog":          "syslog.service",
+	"vmware-vpxd":     "VMware vCenter Server",
+}
+
+// vmonServices are a subset of the vMon managed services of a vCenter appliance
+var vmonServices = []string{
+	"content-library",
+	"eam",
+	"rhttpproxy",
+	"sps",
+	"trustmanagement",
+	"vapi-endpoint",
+	"vpxd",
+	"vpxd-svcs",
+	"vsphere-ui",
+	"wcp",
+}
+
+func (h *Handler) listServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	vapi.StatusOK(w, h.services)
+}
+
+func (h *Handler) service(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, services.Path+"/")
+	info, ok := h.services[name]
+	if !ok {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("service %q not found", name))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, info)
+	case http.MethodPost:
+		switch r.URL.Query().Get(services.Action) {
+		case services.Start, services.Restart:
+			info.State = services.StateStarted
+		case services.Stop:
+			info.State = services.StateStopped
+		default:
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", r.URL.Query().Get(services.Action)))
+			return
+		}
+		h.services[name] = info
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) listVmonServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	vapi.StatusOK(w, h.vmon)
+}
+
+func (h *Handler) vmonService(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, services.VmonPath+"/")
+	info, ok := h.vmon[name]
+	if !ok {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("service %q not found", name))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, info)
+		return
+	case http.MethodPatch:
+		var spec services.VmonUpdateSpec
+		if !h.decode(r, w, &spec) {
+			vapi.APIError(w, rest.ErrorInvalidArgument, "invalid update spec")
+			return
+		}
+		switch spec.StartupType {
+		case services.StartupAutomatic, services.StartupManual, services.StartupDisabled:
+			info.StartupType = spec.StartupType
+		case "":
+		default:
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid startup type: %q", spec.StartupType))
+			return
+		}
+	case http.MethodPost:
+		switch r.URL.Query().Get(services.Action) {
+		case services.Start, services.Restart:
+			if info.StartupType == services.StartupDisabled {
+				vapi.APIError(w, rest.ErrorNotAllowedInCurrentState, fmt.Sprintf("service %q is disabled", name))
+				return
+			}
+			info.State = services.StateStarted
+			info.Health = services.HealthHealthy
+		case services.Stop:
+			info.State = services.StateStopped
+			info.Health = ""
+		default:
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", r.URL.Query().Get(services.Action)))
+			return
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	h.vmon[name] = info
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -21,6 +21,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/vmware/govmomi/simulator"
@@ -28,7 +29,11 @@ import (
 	"github.com/vmware/govmomi/vapi/appliance/access/dcui"
 	"github.com/vmware/govmomi/vapi/appliance/access/shell"
 	"github.com/vmware/govmomi/vapi/appliance/access/ssh"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/appliance/recovery/backup"
+	"github.com/vmware/govmomi/vapi/appliance/services"
 	"github.com/vmware/govmomi/vapi/appliance/shutdown"
+	"github.com/vmware/govmomi/vapi/appliance/system"
 	vapi "github.com/vmware/govmomi/vapi/simulator"
 )
 
@@ -46,18 +51,48 @@ type Handler struct {
 	ssh            ssh.Access
 	shell          shell.Access
 	shutdownConfig shutdown.Config
+
+	mu       sync.Mutex
+	boot     time.Time
+	health   map[string]string
+	services map[string]services.Info
+	vmon     map[string]services.VmonInfo
+	backups  map[string]*backup.Status
 }
 
 // New creates a Handler instance
 func New(u *url.URL) *Handler {
-	return &Handler{
+	h := &Handler{
 		URL:            nil,
 		consolecli:     consolecli.Access{Enabled: false},
 		dcui:           dcui.Access{Enabled: false},
 		ssh:            ssh.Access{Enabled: false},
 		shell:          shell.Access{Enabled: false, Timeout: 0},
 		shutdownConfig: shutdown.Config{},
+		boot:           time.Now(),
+		health:         make(map[string]string),
+		services:       make(map[string]services.Info),
+		vmon:           make(map[string]services.VmonInfo),
+		backups:        make(map[string]*backup.Status),
+	}
+
+	for _, c := range health.Components {
+		h.health[c] = health.Green
+	}
+	for name, description := range applianceServices {
+		h.services[name] = services.Info{Description: description, State: services.StateStarted}
 	}
+	for _, name := range vmonServices {
+		h.vmon[name] = services.VmonInfo{
+			NameKey:        "cis." + name + ".ServiceName",
+			DescriptionKey: "cis." + name + ".ServiceDescription",
+			StartupType:    services.StartupAutomatic,
+			State:          services.StateStarted,
+			Health:         services.HealthHealthy,
+		}
+	}
+
+	return h
 }
 
 // Register Appliance Management API paths with the vapi simulator's http.ServeMux
@@ -67,6 +102,16 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 	s.HandleFunc(ssh.Path, h.sshAccess)
 	s.HandleFunc(shell.Path, h.shellAccess)
 	s.HandleFunc(shutdown.Path, h.shutdown)
+	s.HandleFunc(health.Path+"/", h.healthComponent)
+	s.HandleFunc(system.VersionPath, h.systemVersion)
+	s.HandleFunc(system.UptimePath, h.systemUptime)
+	s.HandleFunc(services.Path, h.listServices)
+	s.HandleFunc(services.Path+"/", h.service)
+	s.HandleFunc(services.VmonPath, h.listVmonServices)
+	s.HandleFunc(services.VmonPath+"/", h.vmonService)
+	s.HandleFunc(backup.JobPath, h.backupJobs)
+	s.HandleFunc(backup.JobPath+"/", h.backupJob)
+	s.HandleFunc(backup.PartsPath, h.backupParts)
 }
 
 func (h *Handler) decode(r *http.Request, w http.ResponseWriter, val interface{}) bool {
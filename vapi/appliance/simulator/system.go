@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/appliance/health"
+	"github.com/vmware/govmomi/vapi/appliance/system"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func (h *Handler) healthComponent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	component := strings.TrimPrefix(r.URL.Path, health.Path+"/")
+	if component == health.System+"/"+health.LastCheck {
+		vapi.StatusOK(w, time.Now().UTC().Format(time.RFC3339))
+		return
+	}
+
+	level, ok := h.health[component]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	vapi.StatusOK(w, level)
+}
+
+func (h *Handler) systemVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	about := simulator.Map.Get(vim25.ServiceInstance).(*simulator.ServiceInstance).Content.About
+
+	vapi.StatusOK(w, system.Version{
+		Version:     about.Version,
+		Product:     "VMware vCenter Server",
+		Build:       about.Build,
+		Type:        "vCenter Server with an embedded Platform Services Controller",
+		Summary:     "vcsim",
+		ReleaseDate: "2022-01-01",
+		InstallTime: h.boot.UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *Handler) systemUptime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	vapi.StatusOK(w, time.Since(h.boot).Seconds())
+}
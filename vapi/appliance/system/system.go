@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+const (
+	VersionPath = "/api/appliance/system/version"
+	UptimePath  = "/api/appliance/system/uptime"
+)
+
+// Manager provides convenience methods for the appliance system API
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+// Version defines the appliance version information returned by the Version.get operation
+type Version struct {
+	Version     string `json:"version"`
+	Product     string `json:"product"`
+	Build       string `json:"build"`
+	Type        string `json:"type"`
+	Summary     string `json:"summary"`
+	ReleaseDate string `json:"releasedate"`
+	InstallTime string `json:"install_time"`
+}
+
+// Version returns the appliance version information.
+func (m *Manager) Version(ctx context.Context) (Version, error) {
+	r := m.Resource(VersionPath)
+
+	var v Version
+
+	return v, m.Do(ctx, r.Request(http.MethodGet), &v)
+}
+
+// Uptime returns the time in seconds since the appliance was started.
+func (m *Manager) Uptime(ctx context.Context) (float64, error) {
+	r := m.Resource(UptimePath)
+
+	var t float64
+
+	return t, m.Do(ctx, r.Request(http.MethodGet), &t)
+}
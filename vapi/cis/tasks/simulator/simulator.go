@@ -0,0 +1,205 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		if r.IsVPX() {
+			_ = Find(s, r)
+		}
+	})
+}
+
+// handlerRef is the reference of the Handler in the Registry of the Service it is registered with
+var handlerRef = types.ManagedObjectReference{Type: "CisTasks", Value: "cis-tasks"}
+
+var findLock sync.Mutex
+
+// Find returns the Handler for the given Service and Registry, registering a new Handler if needed.
+// The Handler is kept in the Registry, such that it is released along with the Registry.
+// Other API simulators use Find to create tasks for their operations.
+func Find(s *simulator.Service, r *simulator.Registry) *Handler {
+	findLock.Lock()
+	defer findLock.Unlock()
+
+	h, ok := r.Get(handlerRef).(*Handler)
+	if !ok {
+		h = New()
+		r.Put(h)
+		s.HandleFunc(tasks.Path+"/", h.tasksID)
+	}
+
+	return h
+}
+
+type task struct {
+	tasks.Info
+	result   interface{}
+	err      *rest.Error
+	duration time.Duration
+}
+
+// Handler implements the CIS Tasks API simulator
+type Handler struct {
+	mu    sync.Mutex
+	delay time.Duration
+	tasks map[string]*task
+}
+
+// Reference implements mo.Reference
+func (h *Handler) Reference() types.ManagedObjectReference {
+	return handlerRef
+}
+
+// New creates a Handler instance
+func New() *Handler {
+	return &Handler{
+		tasks: make(map[string]*task),
+	}
+}
+
+// SetDelay sets the time tasks started after this call take to complete, defaults to 0.
+// While in progress, tasks are reported as RUNNING with progress relative to the delay.
+func (h *Handler) SetDelay(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.delay = d
+}
+
+// Start creates a task for the given operation, which completes with the given result or error.
+// Errors other than *rest.Error are reported as the ERROR kind.
+// The operation itself is expected to have been applied by the caller, the task only simulates its completion.
+func (h *Handler) Start(service, operation string, result interface{}, err error) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	t := &task{
+		Info: tasks.Info{
+			Description: rest.LocalizableMessage{DefaultMessage: service + "." + operation},
+			Service:     service,
+			Operation:   operation,
+			Status:      tasks.Running,
+			Cancelable:  true,
+			StartTime:   &now,
+		},
+		result:   result,
+		duration: h.delay,
+	}
+
+	if err != nil {
+		if !errors.As(err, &t.err) {
+			t.err = &rest.Error{
+				Kind:     rest.ErrorError,
+				Messages: []rest.LocalizableMessage{{DefaultMessage: err.Error()}},
+			}
+		}
+	}
+
+	id := uuid.New().String() + ":" + service
+	h.tasks[id] = t
+	h.update(t, now)
+
+	return id
+}
+
+// update the state of the given task, as of the given time.
+func (h *Handler) update(t *task, now time.Time) {
+	if t.Done() {
+		return
+	}
+
+	elapsed := now.Sub(*t.StartTime)
+	if elapsed < t.duration {
+		t.Progress = &tasks.Progress{
+			Total:     100,
+			Completed: int64(100 * elapsed / t.duration),
+			Message:   rest.LocalizableMessage{DefaultMessage: t.Operation + " in progress"},
+		}
+		return
+	}
+
+	h.done(t, t.result, t.err)
+}
+
+func (h *Handler) done(t *task, result interface{}, err *rest.Error) {
+	now := time.Now()
+	t.EndTime = &now
+	t.Cancelable = false
+	t.Progress = &tasks.Progress{Total: 100, Completed: 100}
+
+	if err != nil {
+		t.Status = tasks.Failed
+		t.Error = err
+		return
+	}
+
+	t.Status = tasks.Succeeded
+	if result != nil {
+		t.Result, _ = json.Marshal(result)
+	}
+}
+
+func (h *Handler) tasksID(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := path.Base(r.URL.Path)
+	t, ok := h.tasks[id]
+	if !ok {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("task %q not found", id))
+		return
+	}
+
+	h.update(t, time.Now())
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, t.Info)
+	case http.MethodPost:
+		if action := r.URL.Query().Get("action"); action != "cancel" {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", action))
+			return
+		}
+		if !t.Cancelable {
+			vapi.APIError(w, rest.ErrorNotAllowedInCurrentState, fmt.Sprintf("task %q is %s", id, t.Status))
+			return
+		}
+		h.done(t, nil, &rest.Error{Kind: rest.ErrorCanceled})
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
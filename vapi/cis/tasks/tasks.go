@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/progress"
+)
+
+// Path is the rest endpoint for the CIS Tasks API
+const Path = "/api/cis/tasks"
+
+// Task status
+const (
+	Pending   = "PENDING"
+	Running   = "RUNNING"
+	Blocked   = "BLOCKED"
+	Succeeded = "SUCCEEDED"
+	Failed    = "FAILED"
+)
+
+// Progress of a running task.
+type Progress struct {
+	Total     int64                   `json:"total"`
+	Completed int64                   `json:"completed"`
+	Message   rest.LocalizableMessage `json:"message"`
+}
+
+// Info is the state of a task.
+type Info struct {
+	Description rest.LocalizableMessage `json:"description"`
+	Service     string                  `json:"service"`
+	Operation   string                  `json:"operation"`
+	Status      string                  `json:"status"`
+	Cancelable  bool                    `json:"cancelable"`
+	Progress    *Progress               `json:"progress,omitempty"`
+	Result      json.RawMessage         `json:"result,omitempty"`
+	Error       *rest.Error             `json:"error,omitempty"`
+	StartTime   *time.Time              `json:"start_time,omitempty"`
+	EndTime     *time.Time              `json:"end_time,omitempty"`
+}
+
+// Done returns true if the task has completed, either successfully or with an error.
+func (i *Info) Done() bool {
+	return i.Status == Succeeded || i.Status == Failed
+}
+
+// Err returns the error of a failed task.
+func (i *Info) Err() error {
+	if i.Status != Failed {
+		return nil
+	}
+	if i.Error == nil {
+		return &rest.Error{Kind: rest.ErrorError}
+	}
+	return i.Error
+}
+
+// Manager extends rest.Client, adding CIS task related methods.
+type Manager struct {
+	*rest.Client
+
+	// Delay is the initial delay between polls of the task state, doubled after each poll.
+	Delay time.Duration
+	// MaxDelay limits the delay between polls of the task state.
+	MaxDelay time.Duration
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client:   client,
+		Delay:    100 * time.Millisecond,
+		MaxDelay: 5 * time.Second,
+	}
+}
+
+// WithTask returns the given Resource with the "vmw-task" param set,
+// such that the operation is invoked as a task and the response is the task ID.
+func WithTask(r *rest.Resource) *rest.Resource {
+	return r.WithParam("vmw-task", "true")
+}
+
+// Start invokes the given request as a task, returning the task ID.
+func (c *Manager) Start(ctx context.Context, req *http.Request) (string, error) {
+	q := req.URL.Query()
+	q.Set("vmw-task", "true")
+	req.URL.RawQuery = q.Encode()
+
+	var id string
+	return id, c.Do(ctx, req, &id)
+}
+
+// Get returns the state of the given task.
+func (c *Manager) Get(ctx context.Context, id string) (*Info, error) {
+	url := c.Resource(path.Join(Path, id))
+	var res Info
+	return &res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// Cancel requests cancellation of the given task.
+func (c *Manager) Cancel(ctx context.Context, id string) error {
+	url := c.Resource(path.Join(Path, id)).WithParam("action", "cancel")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+type taskProgress struct {
+	info *Info
+}
+
+func (t taskProgress) Percentage() float32 {
+	if t.info.Status == Succeeded {
+		return 100
+	}
+	if p := t.info.Progress; p != nil && p.Total != 0 {
+		return 100 * float32(p.Completed) / float32(p.Total)
+	}
+	return 0
+}
+
+func (t taskProgress) Detail() string {
+	if p := t.info.Progress; p != nil {
+		return p.Message.DefaultMessage
+	}
+	return ""
+}
+
+func (t taskProgress) Error() error {
+	return t.info.Err()
+}
+
+// Wait polls the given task until it has completed.
+// If the task succeeded, its result is decoded into val, if val is non-nil.
+// If the task failed, the error is returned as a *rest.Error.
+//
+// If the progress.Sinker argument is specified, progress updates for the task are sent there,
+// with the task progress message as the detail.
+func (c *Manager) Wait(ctx context.Context, id string, s progress.Sinker, val interface{}) (*Info, error) {
+	var ch chan<- progress.Report
+	if s != nil {
+		ch = s.Sink()
+		defer close(ch)
+	}
+
+	delay := c.Delay
+	if delay == 0 {
+		delay = 100 * time.Millisecond
+	}
+
+	for {
+		info, err := c.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if info.Done() {
+			if ch != nil {
+				// Last one must always be delivered
+				ch <- taskProgress{info}
+			}
+			if err = info.Err(); err != nil {
+				return info, err
+			}
+			if val != nil && len(info.Result) != 0 {
+				err = json.Unmarshal(info.Result, val)
+			}
+			return info, err
+		}
+
+		if ch != nil {
+			// Don't care if this is dropped
+			select {
+			case ch <- taskProgress{info}:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return info, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if c.MaxDelay != 0 && delay > c.MaxDelay {
+			delay = c.MaxDelay
+		}
+	}
+}
+
+// Run invokes the given request as a task and waits for the task to complete,
+// decoding the task result into val as described by Wait.
+func (c *Manager) Run(ctx context.Context, req *http.Request, s progress.Sinker, val interface{}) error {
+	id, err := c.Start(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Wait(ctx, id, s, val)
+	return err
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/esx/settings"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/progress"
+
+	tsim "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
+	_ "github.com/vmware/govmomi/vapi/esx/settings/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+type sink struct {
+	ch      chan progress.Report
+	reports []progress.Report
+	done    chan struct{}
+}
+
+func newSink() *sink {
+	s := &sink{ch: make(chan progress.Report), done: make(chan struct{})}
+	go func() {
+		for r := range s.ch {
+			s.reports = append(s.reports, r)
+		}
+		close(s.done)
+	}()
+	return s
+}
+
+func (s *sink) Sink() chan<- progress.Report {
+	return s.ch
+}
+
+func TestTasks(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+
+	err := model.Run(func(ctx context.Context, vc *vim25.Client) error {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			return err
+		}
+
+		m := tasks.NewManager(c)
+		m.Delay = 10 * time.Millisecond
+		h := tsim.Find(model.Service, simulator.Map)
+		if tsim.Find(model.Service, simulator.Map) != h {
+			t.Error("expected the registered handler")
+		}
+
+		// completed task
+		id := h.Start("com.vmware.test", "echo", map[string]string{"name": "test"}, nil)
+		var res map[string]string
+		info, err := m.Wait(ctx, id, nil, &res)
+		if err != nil {
+			return err
+		}
+		if info.Status != tasks.Succeeded || res["name"] != "test" {
+			t.Errorf("info=%#v, res=%#v", info, res)
+		}
+
+		// failed task
+		id = h.Start("com.vmware.test", "fail", nil, &rest.Error{Kind: rest.ErrorResourceBusy})
+		_, err = m.Wait(ctx, id, nil, nil)
+		if !errors.Is(err, rest.ErrorResourceBusy) {
+			t.Errorf("err=%v", err)
+		}
+
+		id = h.Start("com.vmware.test", "fail", nil, errors.New("failed"))
+		_, err = m.Wait(ctx, id, nil, nil)
+		if !errors.Is(err, rest.ErrorError) || err.Error() != "ERROR: failed" {
+			t.Errorf("err=%v", err)
+		}
+
+		// long running task with progress
+		h.SetDelay(200 * time.Millisecond)
+		id = h.Start("com.vmware.test", "sleep", "done", nil)
+
+		info, err = m.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if info.Status != tasks.Running || info.Progress == nil {
+			t.Errorf("info=%#v", info)
+		}
+
+		s := newSink()
+		var done string
+		if _, err = m.Wait(ctx, id, s, &done); err != nil {
+			return err
+		}
+		<-s.done
+		if done != "done" {
+			t.Errorf("res=%q", done)
+		}
+		n := len(s.reports)
+		if n < 2 {
+			t.Fatalf("%d progress reports", n)
+		}
+		if p := s.reports[n-1].Percentage(); p != 100 {
+			t.Errorf("percentage=%f", p)
+		}
+		if s.reports[0].Detail() == "" {
+			t.Error("expected progress detail")
+		}
+
+		// canceled task
+		id = h.Start("com.vmware.test", "sleep", nil, nil)
+		if err = m.Cancel(ctx, id); err != nil {
+			return err
+		}
+		_, err = m.Wait(ctx, id, nil, nil)
+		if !errors.Is(err, rest.ErrorCanceled) {
+			t.Errorf("err=%v", err)
+		}
+		if err = m.Cancel(ctx, id); !errors.Is(err, rest.ErrorNotAllowedInCurrentState) {
+			t.Errorf("err=%v", err)
+		}
+
+		_, err = m.Get(ctx, "enoent")
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+
+		url := c.Resource(path.Join(tasks.Path, id)).WithParam("action", "enoent")
+		if err = c.Do(ctx, url.Request(http.MethodPost), nil); !errors.Is(err, rest.ErrorInvalidArgument) {
+			t.Errorf("err=%v", err)
+		}
+
+		// operation invoked as a task
+		cluster, err := find.NewFinder(vc).ClusterComputeResource(ctx, "DC0_C0")
+		if err != nil {
+			return err
+		}
+		url = c.Resource(path.Join(settings.ClustersPath, cluster.Reference().Value, "software")).WithParam("action", "scan")
+		var compliance settings.ClusterCompliance
+		if err = m.Run(ctx, url.Request(http.MethodPost), nil, &compliance); err != nil {
+			return err
+		}
+		if compliance.Status != settings.ComplianceCompliant {
+			t.Errorf("compliance=%#v", compliance)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,358 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/cis/tasks"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// Paths of the vSphere Lifecycle Manager (vLCM) API
+const (
+	Path             = "/api/esx/settings"
+	ClustersPath     = Path + "/clusters"
+	DepotsPath       = Path + "/depots"
+	OnlineDepotsPath = DepotsPath + "/online"
+	DepotContentPath = Path + "/depot-content"
+	TasksPath        = tasks.Path
+)
+
+// Manager extends rest.Client, adding vSphere Lifecycle Manager related methods.
+type Manager struct {
+	*rest.Client
+}
+
+// NewManager creates a new Manager instance with the given client.
+func NewManager(client *rest.Client) *Manager {
+	return &Manager{
+		Client: client,
+	}
+}
+
+func (c *Manager) software(cluster string, elem ...string) *rest.Resource {
+	return c.Resource(path.Join(append([]string{ClustersPath, cluster, "software"}, elem...)...))
+}
+
+// BaseImageDetails describes an ESXi base image.
+type BaseImageDetails struct {
+	DisplayName    string     `json:"display_name"`
+	DisplayVersion string     `json:"display_version"`
+	ReleaseDate    *time.Time `json:"release_date,omitempty"`
+}
+
+// BaseImage is the ESXi base image of a software specification.
+type BaseImage struct {
+	Version string            `json:"version"`
+	Details *BaseImageDetails `json:"details,omitempty"`
+}
+
+// ComponentDetails describes a software component.
+type ComponentDetails struct {
+	DisplayName    string `json:"display_name"`
+	DisplayVersion string `json:"display_version"`
+	Vendor         string `json:"vendor,omitempty"`
+}
+
+// Component is a software component in addition to the base image.
+type Component struct {
+	Version string            `json:"version"`
+	Details *ComponentDetails `json:"details,omitempty"`
+}
+
+// SoftwareInfo is the desired software specification of a cluster.
+type SoftwareInfo struct {
+	BaseImage  BaseImage            `json:"base_image"`
+	Components map[string]Component `json:"components,omitempty"`
+}
+
+// GetSoftware returns the desired software specification of the given cluster.
+func (c *Manager) GetSoftware(ctx context.Context, cluster string) (*SoftwareInfo, error) {
+	var res SoftwareInfo
+	return &res, c.Do(ctx, c.software(cluster).Request(http.MethodGet), &res)
+}
+
+// Draft metadata status
+const (
+	DraftStatusValid   = "VALID"
+	DraftStatusInvalid = "INVALID"
+)
+
+// DraftMetadata of a software draft.
+type DraftMetadata struct {
+	Owner        string    `json:"owner"`
+	Status       string    `json:"status"`
+	CreationTime time.Time `json:"creation_time"`
+}
+
+// DraftInfo is a software draft, a pending change to the desired software specification of a cluster.
+type DraftInfo struct {
+	Metadata DraftMetadata `json:"metadata"`
+	Software SoftwareInfo  `json:"software"`
+}
+
+// CreateDraft creates a draft of the desired software specification of the given cluster, returning the draft ID.
+func (c *Manager) CreateDraft(ctx context.Context, cluster string) (string, error) {
+	var res string
+	return res, c.Do(ctx, c.software(cluster, "drafts").Request(http.MethodPost), &res)
+}
+
+// ListDrafts returns the software drafts of the given cluster, keyed by draft ID.
+func (c *Manager) ListDrafts(ctx context.Context, cluster string) (map[string]DraftMetadata, error) {
+	var res map[string]struct {
+		Metadata DraftMetadata `json:"metadata"`
+	}
+	if err := c.Do(ctx, c.software(cluster, "drafts").Request(http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	drafts := make(map[string]DraftMetadata, len(res))
+	for id, d := range res {
+		drafts[id] = d.Metadata
+	}
+	return drafts, nil
+}
+
+// GetDraft returns the given software draft.
+func (c *Manager) GetDraft(ctx context.Context, cluster, draft string) (*DraftInfo, error) {
+	var res DraftInfo
+	return &res, c.Do(ctx, c.software(cluster, "drafts", draft).Request(http.MethodGet), &res)
+}
+
+// DeleteDraft discards the given software draft.
+func (c *Manager) DeleteDraft(ctx context.Context, cluster, draft string) error {
+	return c.Do(ctx, c.software(cluster, "drafts", draft).Request(http.MethodDelete), nil)
+}
+
+// BaseImageSpec selects the base image version of a draft.
+type BaseImageSpec struct {
+	Version string `json:"version"`
+}
+
+// SetDraftBaseImage sets the base image version of the given software draft.
+func (c *Manager) SetDraftBaseImage(ctx context.Context, cluster, draft, version string) error {
+	url := c.software(cluster, "drafts", draft, "software", "base-image")
+	return c.Do(ctx, url.Request(http.MethodPut, BaseImageSpec{Version: version}), nil)
+}
+
+// ComponentsUpdateSpec adds, updates or removes components of a draft.
+type ComponentsUpdateSpec struct {
+	ComponentsToSet    map[string]string `json:"components_to_set,omitempty"`
+	ComponentsToDelete []string          `json:"components_to_delete,omitempty"`
+}
+
+// UpdateDraftComponents updates the components of the given software draft.
+func (c *Manager) UpdateDraftComponents(ctx context.Context, cluster, draft string, spec ComponentsUpdateSpec) error {
+	url := c.software(cluster, "drafts", draft, "software", "components")
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// CommitSpec for committing a software draft.
+type CommitSpec struct {
+	Message string `json:"message,omitempty"`
+}
+
+// CommitDraft commits the given software draft as the desired software specification of the cluster,
+// returning the ID of the task. The task result is the commit ID.
+func (c *Manager) CommitDraft(ctx context.Context, cluster, draft string, spec CommitSpec) (string, error) {
+	url := tasks.WithTask(c.software(cluster, "drafts", draft).WithParam("action", "commit"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// Compliance status
+const (
+	ComplianceCompliant    = "COMPLIANT"
+	ComplianceNonCompliant = "NON_COMPLIANT"
+	ComplianceIncompatible = "INCOMPATIBLE"
+	ComplianceUnavailable  = "UNAVAILABLE"
+)
+
+// HostCompliance is the compliance of a host with the desired software specification of its cluster.
+type HostCompliance struct {
+	Status    string    `json:"status"`
+	BaseImage BaseImage `json:"base_image"`
+	ScanTime  time.Time `json:"scan_time"`
+	Notes     []string  `json:"notes,omitempty"`
+}
+
+// ClusterCompliance is the result of a compliance scan of a cluster.
+type ClusterCompliance struct {
+	Status            string                    `json:"status"`
+	ScanTime          time.Time                 `json:"scan_time"`
+	Commit            string                    `json:"commit"`
+	Hosts             map[string]HostCompliance `json:"hosts"`
+	CompliantHosts    []string                  `json:"compliant_hosts"`
+	NonCompliantHosts []string                  `json:"non_compliant_hosts"`
+	IncompatibleHosts []string                  `json:"incompatible_hosts"`
+	UnavailableHosts  []string                  `json:"unavailable_hosts"`
+}
+
+// Scan checks the compliance of the hosts in the given cluster with its desired software specification,
+// returning the ID of the task. The task result is a ClusterCompliance.
+func (c *Manager) Scan(ctx context.Context, cluster string) (string, error) {
+	url := tasks.WithTask(c.software(cluster).WithParam("action", "scan"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost), &res)
+}
+
+// GetCompliance returns the result of the last compliance scan of the given cluster.
+func (c *Manager) GetCompliance(ctx context.Context, cluster string) (*ClusterCompliance, error) {
+	var res ClusterCompliance
+	return &res, c.Do(ctx, c.software(cluster, "compliance").Request(http.MethodGet), &res)
+}
+
+// ApplySpec for remediating the hosts of a cluster.
+type ApplySpec struct {
+	Commit     string   `json:"commit,omitempty"`
+	Hosts      []string `json:"hosts,omitempty"`
+	AcceptEULA bool     `json:"accept_eula,omitempty"`
+}
+
+// Apply status
+const (
+	ApplyStatusRunning = "RUNNING"
+	ApplyStatusOK      = "OK"
+	ApplyStatusSkipped = "SKIPPED"
+	ApplyStatusTimeout = "TIMED_OUT"
+	ApplyStatusError   = "ERROR"
+)
+
+// HostApplyStatus is the remediation status of a host.
+type HostApplyStatus struct {
+	Status string `json:"status"`
+}
+
+// ApplyResult is the result of remediating the hosts of a cluster.
+type ApplyResult struct {
+	Status string                     `json:"status"`
+	Commit string                     `json:"commit"`
+	Hosts  map[string]HostApplyStatus `json:"host_status"`
+}
+
+// Apply remediates the hosts of the given cluster to its desired software specification,
+// returning the ID of the task. The task result is an ApplyResult.
+// By default all hosts in the cluster are remediated to the latest commit.
+func (c *Manager) Apply(ctx context.Context, cluster string, spec ApplySpec) (string, error) {
+	url := tasks.WithTask(c.software(cluster).WithParam("action", "apply"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// OnlineDepotSpec for creating an online depot.
+type OnlineDepotSpec struct {
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// OnlineDepot is an online depot configured in vLCM.
+type OnlineDepot struct {
+	Description string `json:"description"`
+	Location    string `json:"location"`
+	Enabled     bool   `json:"enabled"`
+	Owner       string `json:"owner,omitempty"`
+}
+
+// ListOnlineDepots returns the online depots, keyed by depot ID.
+func (c *Manager) ListOnlineDepots(ctx context.Context) (map[string]OnlineDepot, error) {
+	var res map[string]OnlineDepot
+	return res, c.Do(ctx, c.Resource(OnlineDepotsPath).Request(http.MethodGet), &res)
+}
+
+// CreateOnlineDepot creates an online depot, returning the depot ID.
+func (c *Manager) CreateOnlineDepot(ctx context.Context, spec OnlineDepotSpec) (string, error) {
+	var res string
+	return res, c.Do(ctx, c.Resource(OnlineDepotsPath).Request(http.MethodPost, spec), &res)
+}
+
+// DeleteOnlineDepot deletes the given online depot.
+func (c *Manager) DeleteOnlineDepot(ctx context.Context, id string) error {
+	url := c.Resource(path.Join(OnlineDepotsPath, id))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// SyncDepots synchronizes the content of the online depots, returning the ID of the task.
+func (c *Manager) SyncDepots(ctx context.Context) (string, error) {
+	url := tasks.WithTask(c.Resource(DepotsPath).WithParam("action", "sync"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost), &res)
+}
+
+// BaseImageSummary is a base image available in the depots.
+type BaseImageSummary struct {
+	Version string `json:"version"`
+	BaseImageDetails
+}
+
+// ListBaseImages returns the base images available in the depots.
+func (c *Manager) ListBaseImages(ctx context.Context) ([]BaseImageSummary, error) {
+	var res []BaseImageSummary
+	url := c.Resource(path.Join(DepotContentPath, "base-images"))
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// ComponentVersion is a version of a component available in the depots.
+type ComponentVersion struct {
+	Version        string `json:"version"`
+	DisplayVersion string `json:"display_version"`
+}
+
+// ComponentSummary is a component available in the depots.
+type ComponentSummary struct {
+	Name        string             `json:"name"`
+	DisplayName string             `json:"display_name"`
+	Vendor      string             `json:"vendor"`
+	Versions    []ComponentVersion `json:"versions"`
+}
+
+// ListComponents returns the components available in the depots.
+func (c *Manager) ListComponents(ctx context.Context) ([]ComponentSummary, error) {
+	var res []ComponentSummary
+	url := c.Resource(path.Join(DepotContentPath, "components"))
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// Task status
+const (
+	TaskPending   = tasks.Pending
+	TaskRunning   = tasks.Running
+	TaskBlocked   = tasks.Blocked
+	TaskSucceeded = tasks.Succeeded
+	TaskFailed    = tasks.Failed
+)
+
+// TaskProgress of a running task.
+type TaskProgress = tasks.Progress
+
+// TaskInfo is the state of a CIS task, as returned by "/api/cis/tasks/{task}".
+type TaskInfo = tasks.Info
+
+// GetTask returns the state of the given task.
+func (c *Manager) GetTask(ctx context.Context, id string) (*TaskInfo, error) {
+	return tasks.NewManager(c.Client).Get(ctx, id)
+}
+
+// WaitTask polls the given task until it has completed, decoding the task result into val if non-nil.
+// The task error is returned if the task failed.
+func (c *Manager) WaitTask(ctx context.Context, id string, val interface{}) error {
+	_, err := tasks.NewManager(c.Client).Wait(ctx, id, nil, val)
+	return err
+}
@@ -0,0 +1,291 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package settings_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/esx/settings"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/esx/settings/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestSoftware(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		obj, err := find.NewFinder(vc).ClusterComputeResource(ctx, "DC0_C0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cluster := obj.Reference().Value
+
+		m := settings.NewManager(c)
+
+		images, err := m.ListBaseImages(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(images) < 2 {
+			t.Fatalf("images=%#v", images)
+		}
+
+		components, err := m.ListComponents(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(components) == 0 {
+			t.Fatal("no components")
+		}
+
+		software, err := m.GetSoftware(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if software.BaseImage.Version != images[0].Version {
+			t.Errorf("base image=%s", software.BaseImage.Version)
+		}
+
+		_, err = m.GetSoftware(ctx, "enoent")
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+
+		_, err = m.GetCompliance(ctx, cluster)
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+
+		scan := func(expect string) *settings.ClusterCompliance {
+			id, err := m.Scan(ctx, cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var res settings.ClusterCompliance
+			if err = m.WaitTask(ctx, id, &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != expect {
+				t.Errorf("compliance=%s, expected %s", res.Status, expect)
+			}
+			return &res
+		}
+
+		res := scan(settings.ComplianceCompliant)
+		if len(res.CompliantHosts) == 0 || len(res.Hosts) != len(res.CompliantHosts) {
+			t.Errorf("compliance=%#v", res)
+		}
+
+		draft, err := m.CreateDraft(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.SetDraftBaseImage(ctx, cluster, draft, "enoent")
+		if !errors.Is(err, rest.ErrorInvalidArgument) {
+			t.Errorf("err=%v", err)
+		}
+
+		if err = m.SetDraftBaseImage(ctx, cluster, draft, images[1].Version); err != nil {
+			t.Fatal(err)
+		}
+
+		component := components[0]
+		spec := settings.ComponentsUpdateSpec{
+			ComponentsToSet: map[string]string{component.Name: component.Versions[0].Version},
+		}
+		if err = m.UpdateDraftComponents(ctx, cluster, draft, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetDraft(ctx, cluster, draft)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Software.BaseImage.Version != images[1].Version || len(info.Software.Components) != 1 {
+			t.Errorf("draft=%#v", info)
+		}
+
+		drafts, err := m.ListDrafts(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := drafts[draft]; !ok {
+			t.Errorf("drafts=%#v", drafts)
+		}
+
+		id, err := m.CommitDraft(ctx, cluster, draft, settings.CommitSpec{Message: "upgrade"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var commit string
+		if err = m.WaitTask(ctx, id, &commit); err != nil {
+			t.Fatal(err)
+		}
+
+		software, err = m.GetSoftware(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if software.BaseImage.Version != images[1].Version {
+			t.Errorf("base image=%s", software.BaseImage.Version)
+		}
+		if software.Components[component.Name].Details == nil {
+			t.Errorf("components=%#v", software.Components)
+		}
+
+		res = scan(settings.ComplianceNonCompliant)
+		if len(res.NonCompliantHosts) != len(res.Hosts) {
+			t.Errorf("compliance=%#v", res)
+		}
+
+		id, err = m.Apply(ctx, cluster, settings.ApplySpec{Commit: "enoent"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = m.WaitTask(ctx, id, nil)
+		if !errors.Is(err, rest.ErrorInvalidArgument) {
+			t.Errorf("err=%v", err)
+		}
+
+		id, err = m.Apply(ctx, cluster, settings.ApplySpec{Commit: commit, Hosts: res.NonCompliantHosts[:1]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var apply settings.ApplyResult
+		if err = m.WaitTask(ctx, id, &apply); err != nil {
+			t.Fatal(err)
+		}
+		if apply.Status != settings.ApplyStatusOK || len(apply.Hosts) != 1 {
+			t.Errorf("apply=%#v", apply)
+		}
+
+		compliance, err := m.GetCompliance(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(compliance.CompliantHosts) != 1 {
+			t.Errorf("compliance=%#v", compliance)
+		}
+
+		id, err = m.Apply(ctx, cluster, settings.ApplySpec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.WaitTask(ctx, id, nil); err != nil {
+			t.Fatal(err)
+		}
+		scan(settings.ComplianceCompliant)
+
+		// a component change alone makes the hosts non-compliant
+		draft, err = m.CreateDraft(ctx, cluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		spec = settings.ComponentsUpdateSpec{
+			ComponentsToSet: map[string]string{component.Name: component.Versions[1].Version},
+		}
+		if err = m.UpdateDraftComponents(ctx, cluster, draft, spec); err != nil {
+			t.Fatal(err)
+		}
+		id, err = m.CommitDraft(ctx, cluster, draft, settings.CommitSpec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.WaitTask(ctx, id, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		res = scan(settings.ComplianceNonCompliant)
+		for _, host := range res.Hosts {
+			if len(host.Notes) != 1 || !strings.Contains(host.Notes[0], component.Name) {
+				t.Errorf("notes=%v", host.Notes)
+			}
+		}
+
+		id, err = m.Apply(ctx, cluster, settings.ApplySpec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.WaitTask(ctx, id, nil); err != nil {
+			t.Fatal(err)
+		}
+		scan(settings.ComplianceCompliant)
+	})
+}
+
+func TestDepots(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := settings.NewManager(c)
+
+		depots, err := m.ListOnlineDepots(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := len(depots)
+
+		spec := settings.OnlineDepotSpec{Location: "https://example.com/depot/index.xml", Enabled: true}
+		id, err := m.CreateOnlineDepot(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.CreateOnlineDepot(ctx, spec)
+		if !errors.Is(err, rest.ErrorAlreadyExists) {
+			t.Errorf("err=%v", err)
+		}
+
+		depots, err = m.ListOnlineDepots(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(depots) != n+1 || depots[id].Location != spec.Location {
+			t.Errorf("depots=%#v", depots)
+		}
+
+		task, err := m.SyncDepots(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = m.WaitTask(ctx, task, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = m.DeleteOnlineDepot(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.DeleteOnlineDepot(ctx, id); !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
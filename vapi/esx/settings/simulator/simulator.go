@@ -0,0 +1,627 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vmware/govmomi/simulator"
+	tasks "github.com/vmware/govmomi/vapi/cis/tasks/simulator"
+	"github.com/vmware/govmomi/vapi/esx/settings"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func init() {
+	simulator.RegisterEndpoint(func(s *simulator.Service, r *simulator.Registry) {
+		New(s.Listen).Register(s, r)
+	})
+}
+
+var releaseDate = time.Date(2022, time.October, 11, 0, 0, 0, 0, time.UTC)
+
+// DefaultBaseImages are the base images available in the simulator's depot content.
+// The first base image is the initial desired image of each cluster.
+var DefaultBaseImages = []settings.BaseImageSummary{
+	{Version: "7.0.3-0.65.20842708", BaseImageDetails: settings.BaseImageDetails{DisplayName: "ESXi", DisplayVersion: "7.0 U3i", ReleaseDate: &releaseDate}},
+	{Version: "8.0.0-1.0.20513097", BaseImageDetails: settings.BaseImageDetails{DisplayName: "ESXi", DisplayVersion: "8.0 GA", ReleaseDate: &releaseDate}},
+	{Version: "8.0.1-0.0.21495797", BaseImageDetails: settings.BaseImageDetails{DisplayName: "ESXi", DisplayVersion: "8.0 U1", ReleaseDate: &releaseDate}},
+}
+
+// DefaultComponents are the components available in the simulator's depot content.
+var DefaultComponents = []settings.ComponentSummary{
+	{
+		Name:        "Intel-i40en",
+		DisplayName: "Intel(R) Ethernet Controller X710/XL710/XXV710/X722",
+		Vendor:      "Intel",
+		Versions: []settings.ComponentVersion{
+			{Version: "2.1.5.0-1OEM.700.1.0.15843807", DisplayVersion: "2.1.5.0"},
+			{Version: "2.3.4.0-1OEM.700.1.0.15843807", DisplayVersion: "2.3.4.0"},
+		},
+	},
+	{
+		Name:        "Broadcom-ELX-lpfc",
+		DisplayName: "Emulex FC Driver",
+		Vendor:      "Broadcom",
+		Versions: []settings.ComponentVersion{
+			{Version: "14.0.326.12-1OEM.800.1.0.20613240", DisplayVersion: "14.0.326.12"},
+		},
+	},
+}
+
+// hostSoftware is the software installed on a host
+type hostSoftware struct {
+	baseImage  string            // base image version
+	components map[string]string // component name -> version
+}
+
+// installedSoftware returns the hostSoftware installed by applying the given desired software
+func installedSoftware(s settings.SoftwareInfo) *hostSoftware {
+	host := &hostSoftware{
+		baseImage:  s.BaseImage.Version,
+		components: make(map[string]string, len(s.Components)),
+	}
+	for name, component := range s.Components {
+		host.components[name] = component.Version
+	}
+	return host
+}
+
+type cluster struct {
+	software   settings.SoftwareInfo
+	commit     int
+	draft      int
+	drafts     map[string]*settings.DraftInfo
+	hosts      map[string]*hostSoftware // host ID -> installed software
+	compliance *settings.ClusterCompliance
+}
+
+// Handler implements the vSphere Lifecycle Manager API simulator
+type Handler struct {
+	URL *url.URL
+
+	mu         sync.Mutex
+	depots     map[string]settings.OnlineDepot
+	baseImages []settings.BaseImageSummary
+	components []settings.ComponentSummary
+	clusters   map[string]*cluster
+	tasks      *tasks.Handler
+}
+
+// New creates a Handler instance
+func New(u *url.URL) *Handler {
+	return &Handler{
+		URL: u,
+		depots: map[string]settings.OnlineDepot{
+			"default": {
+				Description: "Default online depot",
+				Location:    "https://hostupdate.vmware.com/software/VUM/PRODUCTION/main/vmw-depot-index.xml",
+				Enabled:     true,
+				Owner:       "VMware",
+			},
+		},
+		baseImages: DefaultBaseImages,
+		components: DefaultComponents,
+		clusters:   make(map[string]*cluster),
+	}
+}
+
+// Register vLCM API paths with the vapi simulator's http.ServeMux
+func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
+	if r.IsVPX() {
+		h.tasks = tasks.Find(s, r)
+		s.HandleFunc(settings.ClustersPath+"/", h.clustersID)
+		s.HandleFunc(settings.DepotsPath, h.depotsSync)
+		s.HandleFunc(settings.OnlineDepotsPath, h.onlineDepots)
+		s.HandleFunc(settings.OnlineDepotsPath+"/", h.onlineDepotsID)
+		s.HandleFunc(settings.DepotContentPath+"/", h.depotContent)
+	}
+}
+
+func newError(kind rest.ErrorKind, format string, args ...interface{}) *rest.Error {
+	return &rest.Error{
+		Kind:     kind,
+		Messages: []rest.LocalizableMessage{{DefaultMessage: fmt.Sprintf(format, args...)}},
+	}
+}
+
+// invoke calls f, responding with the result of f or,
+// if the request was made with the "vmw-task" param, with the ID of a task tracking the result of f.
+func (h *Handler) invoke(w http.ResponseWriter, r *http.Request, op string, f func() (interface{}, *rest.Error)) {
+	res, err := f()
+
+	if r.URL.Query().Get("vmw-task") != "true" {
+		if err != nil {
+			var msg []string
+			for _, m := range err.Messages {
+				msg = append(msg, m.DefaultMessage)
+			}
+			vapi.APIError(w, err.Kind, msg...)
+			return
+		}
+		vapi.StatusOK(w, res)
+		return
+	}
+
+	service := "com.vmware.esx.settings.clusters.software"
+	if op == "sync" {
+		service = "com.vmware.esx.settings.depots"
+	}
+
+	if err != nil {
+		vapi.StatusOK(w, h.tasks.Start(service, op, nil, err))
+		return
+	}
+	vapi.StatusOK(w, h.tasks.Start(service, op, res, nil))
+}
+
+func (h *Handler) baseImage(version string) *settings.BaseImage {
+	for _, image := range h.baseImages {
+		if image.Version == version {
+			details := image.BaseImageDetails
+			return &settings.BaseImage{Version: version, Details: &details}
+		}
+	}
+	return nil
+}
+
+func (h *Handler) component(name, version string) *settings.Component {
+	for _, c := range h.components {
+		if c.Name != name {
+			continue
+		}
+		for _, v := range c.Versions {
+			if v.Version == version {
+				return &settings.Component{
+					Version: version,
+					Details: &settings.ComponentDetails{
+						DisplayName:    c.DisplayName,
+						DisplayVersion: v.DisplayVersion,
+						Vendor:         c.Vendor,
+					},
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// cluster returns the vLCM state of the given cluster, creating it on first use.
+func (h *Handler) cluster(id string) *cluster {
+	ref := types.ManagedObjectReference{Type: "ClusterComputeResource", Value: id}
+	obj, ok := simulator.Map.Get(ref).(*simulator.ClusterComputeResource)
+	if !ok {
+		delete(h.clusters, id)
+		return nil
+	}
+
+	c, ok := h.clusters[id]
+	if !ok {
+		image := h.baseImage(h.baseImages[0].Version)
+		c = &cluster{
+			software: settings.SoftwareInfo{BaseImage: *image},
+			commit:   1,
+			drafts:   make(map[string]*settings.DraftInfo),
+			hosts:    make(map[string]*hostSoftware),
+		}
+		h.clusters[id] = c
+	}
+
+	// track hosts added to or removed from the cluster
+	hosts := make(map[string]*hostSoftware)
+	for _, host := range obj.Host {
+		software, ok := c.hosts[host.Value]
+		if !ok {
+			software = installedSoftware(c.software)
+		}
+		hosts[host.Value] = software
+	}
+	c.hosts = hosts
+
+	return c
+}
+
+func copySoftware(s settings.SoftwareInfo) settings.SoftwareInfo {
+	c := s
+	c.Components = make(map[string]settings.Component, len(s.Components))
+	for name, component := range s.Components {
+		c.Components[name] = component
+	}
+	return c
+}
+
+func (h *Handler) clustersID(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	route := strings.Split(strings.TrimPrefix(r.URL.Path, settings.ClustersPath+"/"), "/")
+	if len(route) < 2 || route[1] != "software" {
+		http.NotFound(w, r)
+		return
+	}
+
+	c := h.cluster(route[0])
+	if c == nil {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("cluster %s not found", route[0]))
+		return
+	}
+
+	switch len(route) {
+	case 2:
+		h.software(c, w, r)
+		return
+	case 3:
+		switch route[2] {
+		case "compliance":
+			if c.compliance == nil {
+				vapi.APIError(w, rest.ErrorNotFound, "no compliance scan results")
+				return
+			}
+			vapi.StatusOK(w, c.compliance)
+			return
+		case "drafts":
+			h.drafts(c, w, r)
+			return
+		}
+	default:
+		if route[2] == "drafts" {
+			draft, ok := c.drafts[route[3]]
+			if !ok {
+				vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("draft %s not found", route[3]))
+				return
+			}
+			h.draftsID(c, route[3], draft, route[4:], w, r)
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
+
+func (h *Handler) software(c *cluster, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, c.software)
+	case http.MethodPost:
+		switch r.URL.Query().Get("action") {
+		case "scan":
+			h.invoke(w, r, "scan", func() (interface{}, *rest.Error) {
+				return h.scan(c), nil
+			})
+		case "apply":
+			var spec settings.ApplySpec
+			if r.ContentLength != 0 && !vapi.Decode(r, w, &spec) {
+				return
+			}
+			h.invoke(w, r, "apply", func() (interface{}, *rest.Error) {
+				return h.apply(c, spec)
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) scan(c *cluster) *settings.ClusterCompliance {
+	now := time.Now()
+	res := &settings.ClusterCompliance{
+		Status:            settings.ComplianceCompliant,
+		ScanTime:          now,
+		Commit:            fmt.Sprint(c.commit),
+		Hosts:             make(map[string]settings.HostCompliance),
+		CompliantHosts:    []string{},
+		NonCompliantHosts: []string{},
+		IncompatibleHosts: []string{},
+		UnavailableHosts:  []string{},
+	}
+
+	for host, software := range c.hosts {
+		status := settings.HostCompliance{
+			Status:   settings.ComplianceCompliant,
+			ScanTime: now,
+			Notes:    c.drift(software),
+		}
+		if image := h.baseImage(software.baseImage); image != nil {
+			status.BaseImage = *image
+		}
+
+		if len(status.Notes) == 0 {
+			res.CompliantHosts = append(res.CompliantHosts, host)
+		} else {
+			status.Status = settings.ComplianceNonCompliant
+			res.NonCompliantHosts = append(res.NonCompliantHosts, host)
+			res.Status = settings.ComplianceNonCompliant
+		}
+
+		res.Hosts[host] = status
+	}
+
+	sort.Strings(res.CompliantHosts)
+	sort.Strings(res.NonCompliantHosts)
+
+	c.compliance = res
+	return res
+}
+
+// drift returns a description of each difference between the host's installed software and the cluster's desired software
+func (c *cluster) drift(host *hostSoftware) []string {
+	var notes, components []string
+
+	if host.baseImage != c.software.BaseImage.Version {
+		notes = append(notes, fmt.Sprintf("base image version %s does not match desired version %s", host.baseImage, c.software.BaseImage.Version))
+	}
+
+	for name, component := range c.software.Components {
+		version, ok := host.components[name]
+		switch {
+		case !ok:
+			components = append(components, fmt.Sprintf("component %s %s is not installed", name, component.Version))
+		case version != component.Version:
+			components = append(components, fmt.Sprintf("component %s version %s does not match desired version %s", name, version, component.Version))
+		}
+	}
+
+	for name, version := range host.components {
+		if _, ok := c.software.Components[name]; !ok {
+			components = append(components, fmt.Sprintf("component %s %s is not in the desired software", name, version))
+		}
+	}
+
+	sort.Strings(components)
+
+	return append(notes, components...)
+}
+
+func (h *Handler) apply(c *cluster, spec settings.ApplySpec) (*settings.ApplyResult, *rest.Error) {
+	commit := fmt.Sprint(c.commit)
+	if spec.Commit != "" && spec.Commit != commit {
+		return nil, newError(rest.ErrorInvalidArgument, "commit %s is not the latest commit %s", spec.Commit, commit)
+	}
+
+	hosts := spec.Hosts
+	if len(hosts) == 0 {
+		for host := range c.hosts {
+			hosts = append(hosts, host)
+		}
+	}
+	for _, host := range hosts {
+		if _, ok := c.hosts[host]; !ok {
+			return nil, newError(rest.ErrorInvalidArgument, "host %s is not in the cluster", host)
+		}
+	}
+
+	res := &settings.ApplyResult{
+		Status: settings.ApplyStatusOK,
+		Commit: commit,
+		Hosts:  make(map[string]settings.HostApplyStatus),
+	}
+	for _, host := range hosts {
+		c.hosts[host] = installedSoftware(c.software)
+		res.Hosts[host] = settings.HostApplyStatus{Status: settings.ApplyStatusOK}
+	}
+
+	h.scan(c)
+
+	return res, nil
+}
+
+func (h *Handler) drafts(c *cluster, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		res := make(map[string]interface{}, len(c.drafts))
+		for id, draft := range c.drafts {
+			res[id] = struct {
+				Metadata settings.DraftMetadata `json:"metadata"`
+			}{draft.Metadata}
+		}
+		vapi.StatusOK(w, res)
+	case http.MethodPost:
+		c.draft++
+		id := fmt.Sprint(c.draft)
+		c.drafts[id] = &settings.DraftInfo{
+			Metadata: settings.DraftMetadata{
+				Owner:        simulator.DefaultLogin.Username(),
+				Status:       settings.DraftStatusValid,
+				CreationTime: time.Now(),
+			},
+			Software: copySoftware(c.software),
+		}
+		vapi.StatusOK(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) draftsID(c *cluster, id string, draft *settings.DraftInfo, route []string, w http.ResponseWriter, r *http.Request) {
+	if len(route) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			vapi.StatusOK(w, draft)
+		case http.MethodDelete:
+			delete(c.drafts, id)
+			vapi.StatusOK(w)
+		case http.MethodPost:
+			if r.URL.Query().Get("action") != "commit" {
+				http.NotFound(w, r)
+				return
+			}
+			var spec settings.CommitSpec
+			if r.ContentLength != 0 && !vapi.Decode(r, w, &spec) {
+				return
+			}
+			h.invoke(w, r, "commit", func() (interface{}, *rest.Error) {
+				c.software = draft.Software
+				c.commit++
+				delete(c.drafts, id)
+				return fmt.Sprint(c.commit), nil
+			})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if len(route) != 2 || route[0] != "software" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch route[1] {
+	case "base-image":
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var spec settings.BaseImageSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		image := h.baseImage(spec.Version)
+		if image == nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("base image %s not found in depot", spec.Version))
+			return
+		}
+		draft.Software.BaseImage = *image
+		vapi.StatusOK(w)
+	case "components":
+		if r.Method != http.MethodPatch {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var spec settings.ComponentsUpdateSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		for name, version := range spec.ComponentsToSet {
+			if h.component(name, version) == nil {
+				vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("component %s %s not found in depot", name, version))
+				return
+			}
+		}
+		for name, version := range spec.ComponentsToSet {
+			draft.Software.Components[name] = *h.component(name, version)
+		}
+		for _, name := range spec.ComponentsToDelete {
+			delete(draft.Software.Components, name)
+		}
+		vapi.StatusOK(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) depotsSync(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if r.Method != http.MethodPost || r.URL.Query().Get("action") != "sync" {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.invoke(w, r, "sync", func() (interface{}, *rest.Error) {
+		return nil, nil
+	})
+}
+
+func (h *Handler) onlineDepots(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.depots)
+	case http.MethodPost:
+		var spec settings.OnlineDepotSpec
+		if !vapi.Decode(r, w, &spec) {
+			return
+		}
+		if _, err := url.Parse(spec.Location); err != nil || spec.Location == "" {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid depot location %q", spec.Location))
+			return
+		}
+		for _, depot := range h.depots {
+			if depot.Location == spec.Location {
+				vapi.APIError(w, rest.ErrorAlreadyExists, fmt.Sprintf("depot %s already exists", spec.Location))
+				return
+			}
+		}
+		id := uuid.New().String()
+		h.depots[id] = settings.OnlineDepot{
+			Description: spec.Description,
+			Location:    spec.Location,
+			Enabled:     spec.Enabled,
+			Owner:       simulator.DefaultLogin.Username(),
+		}
+		vapi.StatusOK(w, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) onlineDepotsID(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := path.Base(r.URL.Path)
+	depot, ok := h.depots[id]
+	if !ok {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("depot %s not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, depot)
+	case http.MethodDelete:
+		delete(h.depots, id)
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) depotContent(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch path.Base(r.URL.Path) {
+	case "base-images":
+		vapi.StatusOK(w, h.baseImages)
+	case "components":
+		vapi.StatusOK(w, h.components)
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -102,6 +102,16 @@ func (c *Manager) GetLibraryItemUpdateSessionFile(ctx context.Context, sessionID
 	return &res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
 }
 
+// RemoveLibraryItemUpdateSessionFile requests a file to be removed.
+// The file will only be effectively removed when the update session is completed.
+func (c *Manager) RemoveLibraryItemUpdateSessionFile(ctx context.Context, sessionID string, fileName string) error {
+	url := c.Resource(internal.LibraryItemUpdateSessionFile).WithID(sessionID).WithAction("remove")
+	spec := struct {
+		Name string `json:"file_name"`
+	}{fileName}
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
 // getContentLengthAndFingerprint gets the number of bytes returned
 // by the URI as well as the SHA1 fingerprint of the peer certificate
 // if the URI's scheme is https.
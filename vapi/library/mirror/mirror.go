@@ -0,0 +1,473 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// Item mirror status
+const (
+	StatusCreated   = "created"
+	StatusUpdated   = "updated"
+	StatusUnchanged = "unchanged"
+)
+
+// Mirror copies content libraries and their items from a Source to a Target vCenter,
+// using library item download sessions on the Source and update sessions on the Target.
+// Unlike a subscribed library, the Target does not need network access to the Source.
+type Mirror struct {
+	Source *rest.Client
+	Target *rest.Client
+
+	// Storage backings used when creating a library on the Target.
+	Storage []library.StorageBackings
+
+	// Scratch is the ID of a Source library that VM templates are temporarily exported to as OVF items.
+	// Defaults to the library of the VM template, which must then be a local library.
+	Scratch string
+
+	// Interval between polls of transfer sessions, defaults to 1 second.
+	Interval time.Duration
+}
+
+// Result describes the mirroring of a single library item.
+type Result struct {
+	Library string `json:"library"`
+	Item    string `json:"item"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Files   int    `json:"files"`
+}
+
+func (m *Mirror) interval() time.Duration {
+	if m.Interval == 0 {
+		return time.Second
+	}
+	return m.Interval
+}
+
+// Library mirrors the given Source library and all of its items to the Target library with the given name,
+// creating the library on the Target if it does not exist. If name is empty, the Source library name is used.
+func (m *Mirror) Library(ctx context.Context, src *library.Library, name string) ([]Result, error) {
+	if name == "" {
+		name = src.Name
+	}
+
+	dst, err := m.library(ctx, src, name)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := library.NewManager(m.Source).GetLibraryItems(ctx, src.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Result
+	for i := range items {
+		if temporary(&items[i]) {
+			continue // left behind by an interrupted export
+		}
+		r, err := m.Item(ctx, &items[i], dst)
+		if err != nil {
+			return res, fmt.Errorf("mirror %s/%s: %s", src.Name, items[i].Name, err)
+		}
+		res = append(res, *r)
+	}
+
+	return res, nil
+}
+
+func (m *Mirror) library(ctx context.Context, src *library.Library, name string) (*library.Library, error) {
+	lm := library.NewManager(m.Target)
+
+	ids, err := lm.FindLibrary(ctx, library.Find{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) != 0 {
+		dst, err := lm.GetLibraryByID(ctx, ids[0])
+		if err != nil {
+			return nil, err
+		}
+		if dst.Description != src.Description {
+			update := &library.Library{ID: dst.ID, Description: src.Description}
+			if err = lm.UpdateLibrary(ctx, update); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	}
+
+	if len(m.Storage) == 0 {
+		return nil, fmt.Errorf("library %q does not exist on target and no storage backing specified", name)
+	}
+
+	id, err := lm.CreateLibrary(ctx, library.Library{
+		Name:        name,
+		Description: src.Description,
+		Type:        "LOCAL",
+		Storage:     m.Storage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lm.GetLibraryByID(ctx, id)
+}
+
+// Item mirrors the given Source library item to the dst library on the Target.
+// An existing Target item with the same name is only updated if its files differ from the Source,
+// as determined by file checksums.
+// VM template items are exported as OVF on the Source and mirrored as an OVF item, along with a marker file
+// recording the template's content version.  The export is skipped if the Target item has the current marker.
+func (m *Mirror) Item(ctx context.Context, src *library.Item, dst *library.Library) (*Result, error) {
+	sm := library.NewManager(m.Source)
+	tm := library.NewManager(m.Target)
+
+	res := &Result{Library: dst.Name, Item: src.Name, Type: src.Type}
+
+	var marker string
+	if src.Type == library.ItemTypeVMTX {
+		res.Type = library.ItemTypeOVF
+		marker = markerName(src)
+	}
+
+	item, err := m.item(ctx, src, dst, res.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var current []library.File
+	if item.ID != "" {
+		current, err = tm.ListLibraryItemFiles(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range current {
+			if marker != "" && f.Name == marker {
+				res.Status = StatusUnchanged
+				res.Files = len(current) - 1
+				return res, nil
+			}
+		}
+	}
+
+	if marker != "" {
+		ovf, err := m.export(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = sm.DeleteLibraryItem(ctx, ovf) }()
+		src = ovf
+	}
+
+	files, err := sm.ListLibraryItemFiles(ctx, src.ID)
+	if err != nil {
+		return nil, err
+	}
+	res.Files = len(files)
+
+	if item.ID == "" {
+		res.Status = StatusCreated
+		item.ID, err = tm.CreateLibraryItem(ctx, *item)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if unchanged(files, current) {
+			res.Status = StatusUnchanged
+			return res, nil
+		}
+		res.Status = StatusUpdated
+	}
+
+	return res, m.copy(ctx, src, item, files, current, marker)
+}
+
+// markerName returns the name of the marker file for the current content version of VM template item src.
+func markerName(src *library.Item) string {
+	return fmt.Sprintf("%s-v%s.mirror", src.ID, src.ContentVersion)
+}
+
+// temporaryName matches the names of the OVF items created by export.
+var temporaryName = regexp.MustCompile(`-mirror-\d+$`)
+
+// temporary returns true if item is a temporary OVF export of a VM template.
+func temporary(item *library.Item) bool {
+	return item.Type == library.ItemTypeOVF && temporaryName.MatchString(item.Name)
+}
+
+// item returns the Target item with the same name as src, updating its description if needed.
+// If no such item exists, the returned item has an empty ID.
+func (m *Mirror) item(ctx context.Context, src *library.Item, dst *library.Library, kind string) (*library.Item, error) {
+	tm := library.NewManager(m.Target)
+
+	ids, err := tm.FindLibraryItems(ctx, library.FindItem{LibraryID: dst.ID, Name: src.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return &library.Item{
+			LibraryID:   dst.ID,
+			Name:        src.Name,
+			Description: src.Description,
+			Type:        kind,
+		}, nil
+	}
+
+	item, err := tm.GetLibraryItem(ctx, ids[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Description != src.Description {
+		update := &library.Item{ID: item.ID, Description: src.Description}
+		if err = tm.UpdateLibraryItem(ctx, update); err != nil {
+			return nil, err
+		}
+	}
+
+	return item, nil
+}
+
+// export creates a temporary OVF item in the Scratch library, or the library of the given item,
+// from the VM template of the given item.
+func (m *Mirror) export(ctx context.Context, src *library.Item) (*library.Item, error) {
+	vm := vcenter.NewManager(m.Source)
+
+	scratch := m.Scratch
+	if scratch == "" {
+		lib, err := library.NewManager(m.Source).GetLibraryByID(ctx, src.LibraryID)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(lib.Type, "LOCAL") {
+			return nil, fmt.Errorf("cannot export VM template to %s library %q, a scratch library is required", lib.Type, lib.Name)
+		}
+		scratch = lib.ID
+	}
+
+	info, err := vm.GetLibraryTemplateInfo(ctx, src.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := vm.CreateOVF(ctx, vcenter.OVF{
+		Spec: vcenter.CreateSpec{
+			Name:        fmt.Sprintf("%s-mirror-%d", src.Name, time.Now().Unix()),
+			Description: src.Description,
+		},
+		Source: vcenter.ResourceID{Value: info.VmTemplate},
+		Target: vcenter.LibraryTarget{LibraryID: scratch},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := library.NewManager(m.Source).GetLibraryItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	item.Name = src.Name
+
+	return item, nil
+}
+
+// unchanged returns true if both sets of files have the same names and checksums.
+func unchanged(src, dst []library.File) bool {
+	if len(src) != len(dst) {
+		return false
+	}
+
+	sums := make(map[string]library.Checksum, len(dst))
+	for _, f := range dst {
+		if f.Checksum == nil {
+			return false
+		}
+		sums[f.Name] = *f.Checksum
+	}
+
+	for _, f := range src {
+		sum, ok := sums[f.Name]
+		if !ok || f.Checksum == nil || *f.Checksum != sum {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copy transfers the src item files to the dst item, removing any dst files that no longer exist in src.
+// If marker is not empty, an empty file with that name is added to the dst item.
+func (m *Mirror) copy(ctx context.Context, src, dst *library.Item, files, current []library.File, marker string) error {
+	sm := library.NewManager(m.Source)
+	tm := library.NewManager(m.Target)
+
+	download, err := sm.CreateLibraryItemDownloadSession(ctx, library.Session{LibraryItemID: src.ID})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sm.DeleteLibraryItemDownloadSession(ctx, download) }()
+
+	update, err := tm.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: dst.ID})
+	if err != nil {
+		return err
+	}
+
+	err = m.transfer(ctx, download, update, files, current)
+	if err == nil && marker != "" {
+		err = m.push(ctx, update, marker, strings.NewReader(""), 0)
+	}
+	if err != nil {
+		_ = tm.FailLibraryItemUpdateSession(ctx, update)
+		return err
+	}
+
+	if err = tm.CompleteLibraryItemUpdateSession(ctx, update); err != nil {
+		return err
+	}
+
+	return tm.WaitOnLibraryItemUpdateSession(ctx, update, m.interval(), nil)
+}
+
+func (m *Mirror) transfer(ctx context.Context, download, update string, files, current []library.File) error {
+	sm := library.NewManager(m.Source)
+	tm := library.NewManager(m.Target)
+
+	names := make(map[string]bool, len(files))
+
+	for _, file := range files {
+		names[file.Name] = true
+
+		if _, err := sm.PrepareLibraryItemDownloadSessionFile(ctx, download, file.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range current {
+		if !names[file.Name] {
+			if err := tm.RemoveLibraryItemUpdateSessionFile(ctx, update, file.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, file := range files {
+		info, err := m.prepared(ctx, download, file.Name)
+		if err != nil {
+			return err
+		}
+
+		src, err := url.Parse(info.DownloadEndpoint.URI)
+		if err != nil {
+			return err
+		}
+
+		r, size, err := m.Source.Download(ctx, src, &soap.DefaultDownload)
+		if err != nil {
+			return err
+		}
+
+		add, err := tm.AddLibraryItemFile(ctx, update, library.UpdateFile{
+			Name:       file.Name,
+			SourceType: "PUSH",
+			Checksum:   file.Checksum,
+			Size:       size,
+		})
+		if err != nil {
+			_ = r.Close()
+			return err
+		}
+
+		err = m.upload(ctx, add, r, size)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// push adds the file with the given name and content to the Target update session.
+func (m *Mirror) push(ctx context.Context, update, name string, r io.Reader, size int64) error {
+	add, err := library.NewManager(m.Target).AddLibraryItemFile(ctx, update, library.UpdateFile{
+		Name:       name,
+		SourceType: "PUSH",
+		Size:       size,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.upload(ctx, add, r, size)
+}
+
+// upload transfers the content of r to the upload endpoint of the given Target update session file.
+func (m *Mirror) upload(ctx context.Context, file *library.UpdateFile, r io.Reader, size int64) error {
+	dst, err := url.Parse(file.UploadEndpoint.URI)
+	if err != nil {
+		return err
+	}
+
+	p := soap.DefaultUpload
+	p.ContentLength = size
+	return m.Target.Upload(ctx, r, dst, &p)
+}
+
+// prepared waits for the given download session file to be ready for transfer.
+func (m *Mirror) prepared(ctx context.Context, session, name string) (*library.DownloadFile, error) {
+	sm := library.NewManager(m.Source)
+
+	for {
+		info, err := sm.GetLibraryItemDownloadSessionFile(ctx, session, name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch info.Status {
+		case "PREPARED":
+			return info, nil
+		case "ERROR":
+			if info.ErrorMessage != nil {
+				return nil, info.ErrorMessage
+			}
+			return nil, fmt.Errorf("prepare %s failed", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(m.interval()):
+		}
+	}
+}
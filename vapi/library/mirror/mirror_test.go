@@ -0,0 +1,300 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirror_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/library/mirror"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func upload(ctx context.Context, c *rest.Client, id string, files map[string]string, remove ...string) error {
+	m := library.NewManager(c)
+
+	session, err := m.CreateLibraryItemUpdateSession(ctx, library.Session{LibraryItemID: id})
+	if err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		info, err := m.AddLibraryItemFile(ctx, session, library.UpdateFile{
+			Name:       name,
+			SourceType: "PUSH",
+			Size:       int64(len(data)),
+		})
+		if err != nil {
+			return err
+		}
+
+		u, err := url.Parse(info.UploadEndpoint.URI)
+		if err != nil {
+			return err
+		}
+
+		p := soap.DefaultUpload
+		p.ContentLength = int64(len(data))
+		if err = c.Upload(ctx, bytes.NewBufferString(data), u, &p); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range remove {
+		if err = m.RemoveLibraryItemUpdateSessionFile(ctx, session, name); err != nil {
+			return err
+		}
+	}
+
+	return m.CompleteLibraryItemUpdateSession(ctx, session)
+}
+
+func TestMirror(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		finder := find.NewFinder(vc)
+		ds, err := finder.DefaultDatastore(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		storage := []library.StorageBackings{{DatastoreID: ds.Reference().Value, Type: "DATASTORE"}}
+
+		m := library.NewManager(c)
+
+		id, err := m.CreateLibrary(ctx, library.Library{Name: "src", Type: "LOCAL", Storage: storage})
+		if err != nil {
+			t.Fatal(err)
+		}
+		src, err := m.GetLibraryByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		iso, err := m.CreateLibraryItem(ctx, library.Item{LibraryID: id, Name: "iso", Type: library.ItemTypeISO, Description: "boot"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = upload(ctx, c, iso, map[string]string{"boot.iso": "boot"}); err != nil {
+			t.Fatal(err)
+		}
+
+		files, err := m.CreateLibraryItem(ctx, library.Item{LibraryID: id, Name: "files"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = upload(ctx, c, files, map[string]string{"a.txt": "a", "b.txt": "b"}); err != nil {
+			t.Fatal(err)
+		}
+
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		folder, err := finder.DefaultFolder(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := finder.ResourcePool(ctx, "DC0_H0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+		placement := &vcenter.Placement{
+			Folder:       folder.Reference().Value,
+			ResourcePool: pool.Reference().Value,
+		}
+		vmtx, err := vcenter.NewManager(c).CreateTemplate(ctx, vcenter.Template{
+			Name:      "vmtx",
+			Library:   id,
+			SourceVM:  vm.Reference().Value,
+			Placement: placement,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		status := func(res []mirror.Result) map[string]string {
+			s := make(map[string]string)
+			for _, r := range res {
+				s[r.Item] = r.Type + " " + r.Status
+			}
+			return s
+		}
+
+		checksums := func(lib string) map[string][]string {
+			l, err := m.GetLibraryByName(ctx, lib)
+			if err != nil {
+				t.Fatal(err)
+			}
+			items, err := m.GetLibraryItems(ctx, l.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sums := make(map[string][]string)
+			for _, item := range items {
+				files, err := m.ListLibraryItemFiles(ctx, item.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sums[item.Name] = []string{}
+				for _, f := range files {
+					sums[item.Name] = append(sums[item.Name], f.Name+":"+f.Checksum.Checksum)
+				}
+				sort.Strings(sums[item.Name])
+			}
+			delete(sums, "vmtx")
+			return sums
+		}
+
+		mi := &mirror.Mirror{Source: c, Target: c}
+
+		if _, err = mi.Library(ctx, src, "dst"); err == nil {
+			t.Error("expected error without storage")
+		}
+
+		mi.Storage = storage
+
+		res, err := mi.Library(ctx, src, "dst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := map[string]string{"iso": "iso created", "files": " created", "vmtx": "ovf created"}
+		if s := status(res); !reflect.DeepEqual(s, expect) {
+			t.Errorf("status=%v", s)
+		}
+
+		dst, err := m.GetLibraryByName(ctx, "dst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, err := m.FindLibraryItems(ctx, library.FindItem{LibraryID: dst.ID, Name: "iso"})
+		if err != nil || len(ids) != 1 {
+			t.Fatalf("find iso: %v %s", ids, err)
+		}
+		item, err := m.GetLibraryItem(ctx, ids[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Description != "boot" || item.Type != library.ItemTypeISO {
+			t.Errorf("item=%#v", item)
+		}
+
+		sums := checksums("dst")
+		if expect := checksums("src"); !reflect.DeepEqual(sums["iso"], expect["iso"]) || !reflect.DeepEqual(sums["files"], expect["files"]) {
+			t.Errorf("src=%v dst=%v", expect, sums)
+		}
+
+		res, err = mi.Library(ctx, src, "dst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect = map[string]string{"iso": "iso unchanged", "files": " unchanged", "vmtx": "ovf unchanged"}
+		if s := status(res); !reflect.DeepEqual(s, expect) {
+			t.Errorf("status=%v", s)
+		}
+
+		if err = upload(ctx, c, files, map[string]string{"b.txt": "bb", "c.txt": "c"}, "a.txt"); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err = mi.Library(ctx, src, "dst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect = map[string]string{"iso": "iso unchanged", "files": " updated", "vmtx": "ovf unchanged"}
+		if s := status(res); !reflect.DeepEqual(s, expect) {
+			t.Errorf("status=%v", s)
+		}
+
+		sums = checksums("dst")
+		if expect := checksums("src"); !reflect.DeepEqual(sums["files"], expect["files"]) || len(sums["files"]) != 2 {
+			t.Errorf("src=%v dst=%v", expect, sums)
+		}
+
+		// a temporary export left behind by an interrupted run is not mirrored
+		leftover, err := m.CreateLibraryItem(ctx, library.Item{LibraryID: id, Name: "vmtx-mirror-1650000000", Type: library.ItemTypeOVF})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err = mi.Library(ctx, src, "dst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect = map[string]string{"iso": "iso unchanged", "files": " unchanged", "vmtx": "ovf unchanged"}
+		if s := status(res); !reflect.DeepEqual(s, expect) {
+			t.Errorf("status=%v", s)
+		}
+
+		if err = m.DeleteLibraryItem(ctx, &library.Item{ID: leftover}); err != nil {
+			t.Fatal(err)
+		}
+
+		// a new template version is exported to the scratch library
+		vcm := vcenter.NewManager(c)
+		ref, err := vcm.CheckOut(ctx, vmtx, &vcenter.CheckOut{Name: "vmtx-v2", Placement: placement})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = vcm.CheckIn(ctx, vmtx, ref, &vcenter.CheckIn{Message: "v2"}); err != nil {
+			t.Fatal(err)
+		}
+
+		mi.Scratch, err = m.CreateLibrary(ctx, library.Library{Name: "scratch", Type: "LOCAL", Storage: storage})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err = mi.Library(ctx, src, "dst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect = map[string]string{"iso": "iso unchanged", "files": " unchanged", "vmtx": "ovf updated"}
+		if s := status(res); !reflect.DeepEqual(s, expect) {
+			t.Errorf("status=%v", s)
+		}
+
+		for _, lib := range []string{id, mi.Scratch} {
+			items, err := m.GetLibraryItems(ctx, lib)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range items {
+				if strings.Contains(item.Name, "-mirror-") {
+					t.Errorf("expected temporary ovf item %s to be removed", item.Name)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/namespace/internal"
+)
+
+// NamespacesInstanceSummary for a vSphere Namespace instance.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/Summary/
+type NamespacesInstanceSummary struct {
+	ClusterId    string `json:"cluster"`
+	Namespace    string `json:"namespace"`
+	ConfigStatus string `json:"config_status"`
+	Description  string `json:"description"`
+}
+
+// NamespacesInstanceInfo for a vSphere Namespace instance.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/Info/
+type NamespacesInstanceInfo struct {
+	ClusterId     string        `json:"cluster"`
+	ConfigStatus  string        `json:"config_status"`
+	Description   string        `json:"description"`
+	StorageSpecs  []StorageSpec `json:"storage_specs"`
+	AccessList    []AccessEntry `json:"access_list"`
+	VmServiceSpec VmServiceSpec `json:"vm_service_spec"`
+}
+
+// NamespacesInstanceCreateSpec defines a new vSphere Namespace instance.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/CreateSpec/
+type NamespacesInstanceCreateSpec struct {
+	Cluster       string        `json:"cluster"`
+	Namespace     string        `json:"namespace"`
+	Description   string        `json:"description,omitempty"`
+	StorageSpecs  []StorageSpec `json:"storage_specs,omitempty"`
+	AccessList    []AccessEntry `json:"access_list,omitempty"`
+	VmServiceSpec VmServiceSpec `json:"vm_service_spec"`
+}
+
+// NamespacesInstanceUpdateSpec defines the changes to an existing vSphere Namespace instance.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/UpdateSpec/
+// Fields left nil are unchanged, a non-nil empty StorageSpecs removes all storage bindings.
+type NamespacesInstanceUpdateSpec struct {
+	Description   *string        `json:"description,omitempty"`
+	StorageSpecs  []StorageSpec  `json:"storage_specs"`
+	VmServiceSpec *VmServiceSpec `json:"vm_service_spec,omitempty"`
+}
+
+// StorageSpec binds a storage policy to a vSphere Namespace, with an optional limit in MiB.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/StorageSpec/
+type StorageSpec struct {
+	Policy string `json:"policy"`
+	Limit  int64  `json:"limit,omitempty"`
+}
+
+// AccessEntry grants a role within a vSphere Namespace to a user or group.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/Access/
+type AccessEntry struct {
+	Role        string `json:"role"`
+	SubjectType string `json:"subject_type"`
+	Subject     string `json:"subject"`
+	Domain      string `json:"domain"`
+}
+
+// VmServiceSpec binds content libraries and VM classes to a vSphere Namespace.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Instances/VMServiceSpec/
+type VmServiceSpec struct {
+	ContentLibraries []string `json:"content_libraries,omitempty"`
+	VmClasses        []string `json:"vm_classes,omitempty"`
+}
+
+// AccessInfo describes the role granted to a subject within a vSphere Namespace.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/Namespaces/Access/Info/
+type AccessInfo struct {
+	Role        string `json:"role"`
+	IsInherited bool   `json:"inherited"`
+}
+
+// AccessSpec defines the role to grant to a subject within a vSphere Namespace.
+type AccessSpec struct {
+	Role string `json:"role"`
+}
+
+// Namespace access roles
+const (
+	AccessRoleEdit  = "EDIT"
+	AccessRoleView  = "VIEW"
+	AccessRoleOwner = "OWNER"
+)
+
+// Namespace access subject types
+const (
+	AccessSubjectUser  = "USER"
+	AccessSubjectGroup = "GROUP"
+)
+
+// ListNamespaces returns a summary of all vSphere Namespace instances.
+func (c *Manager) ListNamespaces(ctx context.Context) ([]NamespacesInstanceSummary, error) {
+	var res []NamespacesInstanceSummary
+	url := c.Resource(internal.NamespacesPath)
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetNamespace gets the information of a specific vSphere Namespace instance.
+func (c *Manager) GetNamespace(ctx context.Context, namespace string) (NamespacesInstanceInfo, error) {
+	var res NamespacesInstanceInfo
+	url := c.Resource(path.Join(internal.NamespacesPath, namespace))
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateNamespace creates a new vSphere Namespace instance.
+func (c *Manager) CreateNamespace(ctx context.Context, spec NamespacesInstanceCreateSpec) error {
+	url := c.Resource(internal.NamespacesPath)
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// UpdateNamespace applies the given changes to a vSphere Namespace instance.
+func (c *Manager) UpdateNamespace(ctx context.Context, namespace string, spec NamespacesInstanceUpdateSpec) error {
+	url := c.Resource(path.Join(internal.NamespacesPath, namespace))
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteNamespace removes a vSphere Namespace instance.
+func (c *Manager) DeleteNamespace(ctx context.Context, namespace string) error {
+	url := c.Resource(path.Join(internal.NamespacesPath, namespace))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+func accessPath(namespace, domain, subject string) string {
+	return path.Join(internal.NamespacesPath, namespace, "access", domain, subject)
+}
+
+// GetNamespaceAccess gets the role granted to a user or group within a vSphere Namespace instance.
+func (c *Manager) GetNamespaceAccess(ctx context.Context, namespace, domain, subject, subjectType string) (AccessInfo, error) {
+	var res AccessInfo
+	url := c.Resource(accessPath(namespace, domain, subject)).WithParam("type", subjectType)
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateNamespaceAccess grants a role to a user or group within a vSphere Namespace instance.
+func (c *Manager) CreateNamespaceAccess(ctx context.Context, namespace, domain, subject, subjectType, role string) error {
+	url := c.Resource(accessPath(namespace, domain, subject)).WithParam("type", subjectType)
+	return c.Do(ctx, url.Request(http.MethodPost, AccessSpec{Role: role}), nil)
+}
+
+// SetNamespaceAccess changes the role granted to a user or group within a vSphere Namespace instance.
+func (c *Manager) SetNamespaceAccess(ctx context.Context, namespace, domain, subject, subjectType, role string) error {
+	url := c.Resource(accessPath(namespace, domain, subject)).WithParam("type", subjectType)
+	return c.Do(ctx, url.Request(http.MethodPut, AccessSpec{Role: role}), nil)
+}
+
+// DeleteNamespaceAccess revokes the role granted to a user or group within a vSphere Namespace instance.
+func (c *Manager) DeleteNamespaceAccess(ctx context.Context, namespace, domain, subject, subjectType string) error {
+	url := c.Resource(accessPath(namespace, domain, subject)).WithParam("type", subjectType)
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
@@ -22,6 +22,8 @@ const (
 	NamespaceDistributedSwitchCompatibility = "/api/vcenter/namespace-management/distributed-switch-compatibility"
 	NamespaceEdgeClusterCompatibility       = "/api/vcenter/namespace-management/edge-cluster-compatibility"
 	SupervisorServicesPath                  = "/api/vcenter/namespace-management/supervisor-services"
+	VmClassesPath                           = "/api/vcenter/namespace-management/virtual-machine-classes"
+	NamespacesPath                          = "/api/vcenter/namespaces/instances"
 )
 
 type SupportBundleToken struct {
@@ -0,0 +1,226 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+
+	_ "github.com/vmware/govmomi/vapi/namespace/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestNamespaceAccess(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		err := c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+		cluster := simulator.Map.Any("ClusterComputeResource")
+
+		spec := namespace.NamespacesInstanceCreateSpec{
+			Cluster:   cluster.Reference().Value,
+			Namespace: "test-ns",
+			AccessList: []namespace.AccessEntry{
+				{Role: namespace.AccessRoleOwner, SubjectType: namespace.AccessSubjectUser, Subject: "admin", Domain: "vsphere.local"},
+			},
+		}
+
+		err = m.CreateNamespace(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		access, err := m.GetNamespaceAccess(ctx, "test-ns", "vsphere.local", "admin", namespace.AccessSubjectUser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if access.Role != namespace.AccessRoleOwner {
+			t.Errorf("role=%s", access.Role)
+		}
+
+		_, err = m.GetNamespaceAccess(ctx, "test-ns", "vsphere.local", "admin", namespace.AccessSubjectGroup)
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+
+		err = m.CreateNamespaceAccess(ctx, "test-ns", "vsphere.local", "devs", namespace.AccessSubjectGroup, namespace.AccessRoleView)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.CreateNamespaceAccess(ctx, "test-ns", "vsphere.local", "devs", namespace.AccessSubjectGroup, namespace.AccessRoleView)
+		if !errors.Is(err, rest.ErrorAlreadyExists) {
+			t.Errorf("err=%v", err)
+		}
+
+		err = m.SetNamespaceAccess(ctx, "test-ns", "vsphere.local", "devs", namespace.AccessSubjectGroup, namespace.AccessRoleEdit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetNamespace(ctx, "test-ns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(info.AccessList) != 2 || info.AccessList[1].Role != namespace.AccessRoleEdit {
+			t.Errorf("access=%#v", info.AccessList)
+		}
+
+		err = m.DeleteNamespaceAccess(ctx, "test-ns", "vsphere.local", "devs", namespace.AccessSubjectGroup)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.DeleteNamespaceAccess(ctx, "test-ns", "vsphere.local", "devs", namespace.AccessSubjectGroup)
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
+
+func TestNamespaceUpdate(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		err := c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+		cluster := simulator.Map.Any("ClusterComputeResource")
+
+		err = m.CreateNamespace(ctx, namespace.NamespacesInstanceCreateSpec{
+			Cluster:      cluster.Reference().Value,
+			Namespace:    "test-ns",
+			Description:  "test",
+			StorageSpecs: []namespace.StorageSpec{{Policy: "policy"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.CreateNamespace(ctx, namespace.NamespacesInstanceCreateSpec{Cluster: cluster.Reference().Value, Namespace: "test-ns"})
+		if !errors.Is(err, rest.ErrorAlreadyExists) {
+			t.Errorf("err=%v", err)
+		}
+
+		// fields left nil are unchanged
+		if err = m.UpdateNamespace(ctx, "test-ns", namespace.NamespacesInstanceUpdateSpec{}); err != nil {
+			t.Fatal(err)
+		}
+		info, err := m.GetNamespace(ctx, "test-ns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Description != "test" || len(info.StorageSpecs) != 1 {
+			t.Errorf("info=%#v", info)
+		}
+
+		// fields can be reset to empty
+		empty := ""
+		spec := namespace.NamespacesInstanceUpdateSpec{Description: &empty, StorageSpecs: []namespace.StorageSpec{}}
+		if err = m.UpdateNamespace(ctx, "test-ns", spec); err != nil {
+			t.Fatal(err)
+		}
+		info, err = m.GetNamespace(ctx, "test-ns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Description != "" || len(info.StorageSpecs) != 0 {
+			t.Errorf("info=%#v", info)
+		}
+
+		err = m.UpdateNamespace(ctx, "enoent", spec)
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
+
+func TestVmClassUpdate(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		err := c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := namespace.NewManager(c)
+
+		err = m.CreateVmClass(ctx, namespace.VirtualMachineClassCreateSpec{
+			ID:             "test-class",
+			CpuCount:       2,
+			MemoryMb:       2048,
+			CpuReservation: 50,
+			Description:    "test",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		zero, cpus, empty := int64(0), int64(4), ""
+		spec := namespace.VirtualMachineClassUpdateSpec{CpuCount: &cpus, CpuReservation: &zero, Description: &empty}
+		if err = m.UpdateVmClass(ctx, "test-class", spec); err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetVmClass(ctx, "test-class")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.CpuCount != 4 || info.MemoryMb != 2048 || info.CpuReservation != 0 || info.Description != "" {
+			t.Errorf("info=%#v", info)
+		}
+
+		err = m.UpdateVmClass(ctx, "test-class", namespace.VirtualMachineClassUpdateSpec{MemoryMb: &zero})
+		if !errors.Is(err, rest.ErrorInvalidArgument) {
+			t.Errorf("err=%v", err)
+		}
+
+		err = m.CreateNamespace(ctx, namespace.NamespacesInstanceCreateSpec{
+			Cluster:       simulator.Map.Any("ClusterComputeResource").Reference().Value,
+			Namespace:     "test-ns",
+			VmServiceSpec: namespace.VmServiceSpec{VmClasses: []string{"test-class"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.DeleteVmClass(ctx, "test-class")
+		if !errors.Is(err, rest.ErrorResourceInUse) {
+			t.Errorf("err=%v", err)
+		}
+
+		_, err = m.GetVmClass(ctx, "enoent")
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+	})
+}
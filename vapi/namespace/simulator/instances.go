@@ -0,0 +1,362 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/namespace"
+	"github.com/vmware/govmomi/vapi/rest"
+	vapi "github.com/vmware/govmomi/vapi/simulator"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/vmware/govmomi/vapi/namespace/internal"
+)
+
+// defaultVmClasses are the VM classes available out of the box on vCenter
+var defaultVmClasses = []namespace.VirtualMachineClassCreateSpec{
+	{ID: "best-effort-xsmall", CpuCount: 2, MemoryMb: 2048},
+	{ID: "best-effort-small", CpuCount: 2, MemoryMb: 4096},
+	{ID: "best-effort-medium", CpuCount: 2, MemoryMb: 8192},
+	{ID: "best-effort-large", CpuCount: 4, MemoryMb: 16384},
+	{ID: "guaranteed-xsmall", CpuCount: 2, MemoryMb: 2048, CpuReservation: 100, MemoryReservation: 100},
+	{ID: "guaranteed-small", CpuCount: 2, MemoryMb: 4096, CpuReservation: 100, MemoryReservation: 100},
+	{ID: "guaranteed-medium", CpuCount: 2, MemoryMb: 8192, CpuReservation: 100, MemoryReservation: 100},
+	{ID: "guaranteed-large", CpuCount: 4, MemoryMb: 16384, CpuReservation: 100, MemoryReservation: 100},
+}
+
+// namespaceName matches a valid DNS label, as required for namespace names
+var namespaceName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+func vmClassInfo(spec namespace.VirtualMachineClassCreateSpec) *namespace.VirtualMachineClassInfo {
+	return &namespace.VirtualMachineClassInfo{
+		ID:                spec.ID,
+		CpuCount:          spec.CpuCount,
+		MemoryMb:          spec.MemoryMb,
+		CpuReservation:    spec.CpuReservation,
+		MemoryReservation: spec.MemoryReservation,
+		Description:       spec.Description,
+		ConfigStatus:      "READY",
+	}
+}
+
+// validateVmService returns false if the spec references an unknown VM class.
+// Must be called with h.mu held.
+func (h *Handler) validateVmService(spec namespace.VmServiceSpec) bool {
+	for _, id := range spec.VmClasses {
+		if h.vmClasses[id] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Handler) namespacesInstances(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.NamespacesInstanceSummary{}
+		for name, ns := range h.namespaces {
+			res = append(res, namespace.NamespacesInstanceSummary{
+				ClusterId:    ns.ClusterId,
+				Namespace:    name,
+				ConfigStatus: ns.ConfigStatus,
+				Description:  ns.Description,
+			})
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Namespace < res[j].Namespace })
+		vapi.StatusOK(w, res)
+	case http.MethodPost:
+		var spec namespace.NamespacesInstanceCreateSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, err.Error())
+			return
+		}
+
+		ref := types.ManagedObjectReference{Type: "ClusterComputeResource", Value: spec.Cluster}
+		if len(spec.Namespace) > 63 || !namespaceName.MatchString(spec.Namespace) {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid namespace name %q", spec.Namespace))
+			return
+		}
+		if simulator.Map.Get(ref) == nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("cluster %q not found", spec.Cluster))
+			return
+		}
+		if !h.validateVmService(spec.VmServiceSpec) {
+			vapi.APIError(w, rest.ErrorInvalidArgument, "unknown VM class")
+			return
+		}
+
+		if h.namespaces[spec.Namespace] != nil {
+			vapi.APIError(w, rest.ErrorAlreadyExists, fmt.Sprintf("namespace %q already exists", spec.Namespace))
+			return
+		}
+
+		h.namespaces[spec.Namespace] = &namespace.NamespacesInstanceInfo{
+			ClusterId:     spec.Cluster,
+			ConfigStatus:  "RUNNING",
+			Description:   spec.Description,
+			StorageSpecs:  spec.StorageSpecs,
+			AccessList:    spec.AccessList,
+			VmServiceSpec: spec.VmServiceSpec,
+		}
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) namespacesInstancesID(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.NamespacesPath+"/"), "/")
+	ns := h.namespaces[p[0]]
+	if ns == nil {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("namespace %q not found", p[0]))
+		return
+	}
+
+	if len(p) == 4 && p[1] == "access" {
+		h.namespaceAccess(w, r, ns, p[2], p[3])
+		return
+	}
+	if len(p) != 1 {
+		vapi.APIError(w, rest.ErrorNotFound, r.URL.Path)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, ns)
+	case http.MethodPatch:
+		var spec namespace.NamespacesInstanceUpdateSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, err.Error())
+			return
+		}
+
+		if spec.VmServiceSpec != nil {
+			if !h.validateVmService(*spec.VmServiceSpec) {
+				vapi.APIError(w, rest.ErrorInvalidArgument, "unknown VM class")
+				return
+			}
+			ns.VmServiceSpec = *spec.VmServiceSpec
+		}
+		if spec.Description != nil {
+			ns.Description = *spec.Description
+		}
+		if spec.StorageSpecs != nil {
+			ns.StorageSpecs = spec.StorageSpecs
+		}
+		vapi.StatusOK(w)
+	case http.MethodDelete:
+		delete(h.namespaces, p[0])
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// namespaceAccess handles the access grants of a namespace.
+// Must be called with h.mu held.
+func (h *Handler) namespaceAccess(w http.ResponseWriter, r *http.Request, ns *namespace.NamespacesInstanceInfo, domain, subject string) {
+	kind := r.URL.Query().Get("type")
+	if kind != namespace.AccessSubjectUser && kind != namespace.AccessSubjectGroup {
+		vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid subject type %q", kind))
+		return
+	}
+
+	index := -1
+	for i, entry := range ns.AccessList {
+		if entry.Domain == domain && entry.Subject == subject && entry.SubjectType == kind {
+			index = i
+			break
+		}
+	}
+
+	var spec namespace.AccessSpec
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, err.Error())
+			return
+		}
+		if spec.Role == "" {
+			vapi.APIError(w, rest.ErrorInvalidArgument, "role is required")
+			return
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		if index != -1 {
+			vapi.APIError(w, rest.ErrorAlreadyExists, fmt.Sprintf("%s %s\\%s already has access", kind, domain, subject))
+			return
+		}
+		ns.AccessList = append(ns.AccessList, namespace.AccessEntry{
+			Role:        spec.Role,
+			SubjectType: kind,
+			Subject:     subject,
+			Domain:      domain,
+		})
+		vapi.StatusOK(w)
+		return
+	}
+
+	if index == -1 {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("%s %s\\%s has no access", kind, domain, subject))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, namespace.AccessInfo{Role: ns.AccessList[index].Role})
+	case http.MethodPut:
+		ns.AccessList[index].Role = spec.Role
+		vapi.StatusOK(w)
+	case http.MethodDelete:
+		ns.AccessList = append(ns.AccessList[:index], ns.AccessList[index+1:]...)
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// vmClassNamespaces returns the names of namespaces using the given VM class.
+// Must be called with h.mu held.
+func (h *Handler) vmClassNamespaces(id string) []string {
+	res := []string{}
+	for name, ns := range h.namespaces {
+		for _, class := range ns.VmServiceSpec.VmClasses {
+			if class == id {
+				res = append(res, name)
+			}
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// vmClass returns a copy of the given VM class, including the namespaces using it.
+// Must be called with h.mu held.
+func (h *Handler) vmClass(id string) namespace.VirtualMachineClassInfo {
+	info := *h.vmClasses[id]
+	info.Namespaces = h.vmClassNamespaces(id)
+	info.Vms = []string{}
+	return info
+}
+
+func (h *Handler) listOrCreateVmClasses(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		res := []namespace.VirtualMachineClassInfo{}
+		for id := range h.vmClasses {
+			res = append(res, h.vmClass(id))
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+		vapi.StatusOK(w, res)
+	case http.MethodPost:
+		var spec namespace.VirtualMachineClassCreateSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, err.Error())
+			return
+		}
+
+		if !namespaceName.MatchString(spec.ID) {
+			vapi.APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid VM class name %q", spec.ID))
+			return
+		}
+		if spec.CpuCount <= 0 || spec.MemoryMb <= 0 {
+			vapi.APIError(w, rest.ErrorInvalidArgument, "cpu_count and memory_MB must be greater than 0")
+			return
+		}
+
+		if h.vmClasses[spec.ID] != nil {
+			vapi.APIError(w, rest.ErrorAlreadyExists, fmt.Sprintf("VM class %q already exists", spec.ID))
+			return
+		}
+
+		h.vmClasses[spec.ID] = vmClassInfo(spec)
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) vmClassesID(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, internal.VmClassesPath+"/")
+	class := h.vmClasses[id]
+	if class == nil {
+		vapi.APIError(w, rest.ErrorNotFound, fmt.Sprintf("VM class %q not found", id))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		vapi.StatusOK(w, h.vmClass(id))
+	case http.MethodPatch:
+		var spec namespace.VirtualMachineClassUpdateSpec
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			vapi.APIError(w, rest.ErrorInvalidArgument, err.Error())
+			return
+		}
+
+		if (spec.CpuCount != nil && *spec.CpuCount <= 0) || (spec.MemoryMb != nil && *spec.MemoryMb <= 0) {
+			vapi.APIError(w, rest.ErrorInvalidArgument, "cpu_count and memory_MB must be greater than 0")
+			return
+		}
+
+		if spec.CpuCount != nil {
+			class.CpuCount = *spec.CpuCount
+		}
+		if spec.MemoryMb != nil {
+			class.MemoryMb = *spec.MemoryMb
+		}
+		if spec.CpuReservation != nil {
+			class.CpuReservation = *spec.CpuReservation
+		}
+		if spec.MemoryReservation != nil {
+			class.MemoryReservation = *spec.MemoryReservation
+		}
+		if spec.Description != nil {
+			class.Description = *spec.Description
+		}
+		vapi.StatusOK(w)
+	case http.MethodDelete:
+		if ns := h.vmClassNamespaces(id); len(ns) != 0 {
+			vapi.APIError(w, rest.ErrorResourceInUse, fmt.Sprintf("VM class %q is used by namespaces %s", id, strings.Join(ns, ", ")))
+			return
+		}
+		delete(h.vmClasses, id)
+		vapi.StatusOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -48,13 +49,25 @@ func init() {
 // Handler implements the Cluster Modules API simulator
 type Handler struct {
 	URL *url.URL
+
+	mu         sync.Mutex
+	namespaces map[string]*namespace.NamespacesInstanceInfo
+	vmClasses  map[string]*namespace.VirtualMachineClassInfo
 }
 
 // New creates a Handler instance
 func New(u *url.URL) *Handler {
-	return &Handler{
-		URL: u,
+	h := &Handler{
+		URL:        u,
+		namespaces: make(map[string]*namespace.NamespacesInstanceInfo),
+		vmClasses:  make(map[string]*namespace.VirtualMachineClassInfo),
+	}
+
+	for _, spec := range defaultVmClasses {
+		h.vmClasses[spec.ID] = vmClassInfo(spec)
 	}
+
+	return h
 }
 
 // Register Namespace Management API paths with the vapi simulator's http.ServeMux
@@ -68,6 +81,11 @@ func (h *Handler) Register(s *simulator.Service, r *simulator.Registry) {
 		s.HandleFunc(internal.SupervisorServicesPath, h.listServices)
 		s.HandleFunc(internal.SupervisorServicesPath+"/", h.getService)
 
+		s.HandleFunc(internal.NamespacesPath, h.namespacesInstances)
+		s.HandleFunc(internal.NamespacesPath+"/", h.namespacesInstancesID)
+
+		s.HandleFunc(internal.VmClassesPath, h.listOrCreateVmClasses)
+		s.HandleFunc(internal.VmClassesPath+"/", h.vmClassesID)
 	}
 }
 
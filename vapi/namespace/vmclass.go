@@ -0,0 +1,93 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/namespace/internal"
+)
+
+// VirtualMachineClassInfo for a VM class available to vSphere Namespaces.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/NamespaceManagement/VirtualMachineClasses/Info/
+type VirtualMachineClassInfo struct {
+	ID                string   `json:"id"`
+	CpuCount          int64    `json:"cpu_count"`
+	MemoryMb          int64    `json:"memory_MB"`
+	CpuReservation    int64    `json:"cpu_reservation,omitempty"`
+	MemoryReservation int64    `json:"memory_reservation,omitempty"`
+	Description       string   `json:"description"`
+	ConfigStatus      string   `json:"config_status"`
+	Namespaces        []string `json:"namespaces"`
+	Vms               []string `json:"vms"`
+}
+
+// VirtualMachineClassCreateSpec defines a new VM class.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/NamespaceManagement/VirtualMachineClasses/CreateSpec/
+type VirtualMachineClassCreateSpec struct {
+	ID                string `json:"id"`
+	CpuCount          int64  `json:"cpu_count"`
+	MemoryMb          int64  `json:"memory_MB"`
+	CpuReservation    int64  `json:"cpu_reservation,omitempty"`
+	MemoryReservation int64  `json:"memory_reservation,omitempty"`
+	Description       string `json:"description,omitempty"`
+}
+
+// VirtualMachineClassUpdateSpec defines the changes to an existing VM class.
+// See https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/data-structures/NamespaceManagement/VirtualMachineClasses/UpdateSpec/
+// Fields left nil are unchanged.
+type VirtualMachineClassUpdateSpec struct {
+	CpuCount          *int64  `json:"cpu_count,omitempty"`
+	MemoryMb          *int64  `json:"memory_MB,omitempty"`
+	CpuReservation    *int64  `json:"cpu_reservation,omitempty"`
+	MemoryReservation *int64  `json:"memory_reservation,omitempty"`
+	Description       *string `json:"description,omitempty"`
+}
+
+// ListVmClasses returns the information of all VM classes.
+func (c *Manager) ListVmClasses(ctx context.Context) ([]VirtualMachineClassInfo, error) {
+	var res []VirtualMachineClassInfo
+	url := c.Resource(internal.VmClassesPath)
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetVmClass gets the information of a specific VM class.
+func (c *Manager) GetVmClass(ctx context.Context, id string) (VirtualMachineClassInfo, error) {
+	var res VirtualMachineClassInfo
+	url := c.Resource(path.Join(internal.VmClassesPath, id))
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// CreateVmClass creates a new VM class.
+func (c *Manager) CreateVmClass(ctx context.Context, spec VirtualMachineClassCreateSpec) error {
+	url := c.Resource(internal.VmClassesPath)
+	return c.Do(ctx, url.Request(http.MethodPost, spec), nil)
+}
+
+// UpdateVmClass applies the given changes to a VM class.
+func (c *Manager) UpdateVmClass(ctx context.Context, id string, spec VirtualMachineClassUpdateSpec) error {
+	url := c.Resource(path.Join(internal.VmClassesPath, id))
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// DeleteVmClass removes a VM class.
+func (c *Manager) DeleteVmClass(ctx context.Context, id string) error {
+	url := c.Resource(path.Join(internal.VmClassesPath, id))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
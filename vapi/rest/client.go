@@ -20,9 +20,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
-	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
@@ -40,6 +39,7 @@ type Client struct {
 
 	*soap.Client
 	sessionID string
+	retry     *RetryPolicy
 }
 
 // Session information
@@ -147,14 +147,6 @@ func (c *Client) WithHeader(
 	return context.WithValue(ctx, headersContext{}, headers)
 }
 
-type statusError struct {
-	res *http.Response
-}
-
-func (e *statusError) Error() string {
-	return fmt.Sprintf("%s %s: %s", e.res.Request.Method, e.res.Request.URL, e.res.Status)
-}
-
 // RawResponse may be used with the Do method as the resBody argument in order
 // to capture the raw response data.
 type RawResponse struct {
@@ -188,20 +180,24 @@ func (c *Client) Do(ctx context.Context, req *http.Request, resBody interface{})
 		}
 	}
 
+	policy := c.RetryPolicy()
+	if p, ok := ctx.Value(retryContext{}).(*RetryPolicy); ok {
+		policy = p
+	}
+
+	return policy.do(ctx, req, func() error {
+		return c.do(ctx, req, resBody)
+	})
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, resBody interface{}) error {
 	return c.Client.Do(ctx, req, func(res *http.Response) error {
 		switch res.StatusCode {
 		case http.StatusOK:
 		case http.StatusCreated:
 		case http.StatusNoContent:
-		case http.StatusBadRequest:
-			// TODO: structured error types
-			detail, err := ioutil.ReadAll(res.Body)
-			if err != nil {
-				return err
-			}
-			return fmt.Errorf("%s: %s", res.Status, bytes.TrimSpace(detail))
 		default:
-			return &statusError{res}
+			return newError(res)
 		}
 
 		if resBody == nil {
@@ -300,10 +296,9 @@ func (c *Client) Session(ctx context.Context) (*Session, error) {
 	req := c.Resource(internal.SessionPath).WithAction("get").Request(http.MethodPost)
 	err := c.Do(ctx, req, &s)
 	if err != nil {
-		if e, ok := err.(*statusError); ok {
-			if e.res.StatusCode == http.StatusUnauthorized {
-				return nil, nil
-			}
+		var e *Error
+		if errors.As(err, &e) && e.StatusCode == http.StatusUnauthorized {
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ErrorKind is the type of a standard vAPI error, as returned by the "error_type" field of "/api" error responses.
+// Errors from "/rest" endpoints are mapped to the same kinds, for example "com.vmware.vapi.std.errors.not_found" maps to NOT_FOUND.
+type ErrorKind string
+
+// Standard vAPI error kinds
+const (
+	ErrorAlreadyExists               = ErrorKind("ALREADY_EXISTS")
+	ErrorAlreadyInDesiredState       = ErrorKind("ALREADY_IN_DESIRED_STATE")
+	ErrorCanceled                    = ErrorKind("CANCELED")
+	ErrorConcurrentChange            = ErrorKind("CONCURRENT_CHANGE")
+	ErrorError                       = ErrorKind("ERROR")
+	ErrorFeatureInUse                = ErrorKind("FEATURE_IN_USE")
+	ErrorInternalServerError         = ErrorKind("INTERNAL_SERVER_ERROR")
+	ErrorInvalidArgument             = ErrorKind("INVALID_ARGUMENT")
+	ErrorInvalidElementConfiguration = ErrorKind("INVALID_ELEMENT_CONFIGURATION")
+	ErrorInvalidElementType          = ErrorKind("INVALID_ELEMENT_TYPE")
+	ErrorInvalidRequest              = ErrorKind("INVALID_REQUEST")
+	ErrorNotAllowedInCurrentState    = ErrorKind("NOT_ALLOWED_IN_CURRENT_STATE")
+	ErrorNotFound                    = ErrorKind("NOT_FOUND")
+	ErrorOperationNotFound           = ErrorKind("OPERATION_NOT_FOUND")
+	ErrorResourceBusy                = ErrorKind("RESOURCE_BUSY")
+	ErrorResourceInUse               = ErrorKind("RESOURCE_IN_USE")
+	ErrorResourceInaccessible        = ErrorKind("RESOURCE_INACCESSIBLE")
+	ErrorServiceUnavailable          = ErrorKind("SERVICE_UNAVAILABLE")
+	ErrorTimedOut                    = ErrorKind("TIMED_OUT")
+	ErrorUnableToAllocateResource    = ErrorKind("UNABLE_TO_ALLOCATE_RESOURCE")
+	ErrorUnauthenticated             = ErrorKind("UNAUTHENTICATED")
+	ErrorUnauthorized                = ErrorKind("UNAUTHORIZED")
+	ErrorUnexpectedInput             = ErrorKind("UNEXPECTED_INPUT")
+	ErrorUnsupported                 = ErrorKind("UNSUPPORTED")
+	ErrorUnverifiedPeer              = ErrorKind("UNVERIFIED_PEER")
+)
+
+// statusErrorKind maps http status codes to an ErrorKind, for responses without a structured error body.
+var statusErrorKind = map[int]ErrorKind{
+	http.StatusBadRequest:          ErrorInvalidRequest,
+	http.StatusUnauthorized:        ErrorUnauthenticated,
+	http.StatusForbidden:           ErrorUnauthorized,
+	http.StatusNotFound:            ErrorNotFound,
+	http.StatusConflict:            ErrorConcurrentChange,
+	http.StatusTooManyRequests:     ErrorResourceBusy,
+	http.StatusInternalServerError: ErrorInternalServerError,
+	http.StatusServiceUnavailable:  ErrorServiceUnavailable,
+	http.StatusGatewayTimeout:      ErrorTimedOut,
+}
+
+func (k ErrorKind) Error() string {
+	return string(k)
+}
+
+// Error is a vAPI error response.
+// Use errors.As to access the error details or errors.Is with an ErrorKind to check the error type:
+//
+//	if errors.Is(err, rest.ErrorNotFound) { ... }
+type Error struct {
+	Kind       ErrorKind            `json:"error_type"`
+	Messages   []LocalizableMessage `json:"messages,omitempty"`
+	Data       json.RawMessage      `json:"data,omitempty"`
+	StatusCode int                  `json:"-"`
+	Status     string               `json:"-"`
+	Method     string               `json:"-"`
+	URL        string               `json:"-"`
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Kind)
+	if e.Method != "" { // not set for errors that are not an HTTP response, such as a CIS task error
+		msg = fmt.Sprintf("%s %s: %s", e.Method, e.URL, e.Status)
+		if e.Kind != "" {
+			msg += " (" + string(e.Kind) + ")"
+		}
+	}
+
+	var details []string
+	for _, m := range e.Messages {
+		if m.DefaultMessage != "" {
+			details = append(details, m.DefaultMessage)
+		}
+	}
+	if len(details) != 0 {
+		msg += ": " + strings.Join(details, ", ")
+	}
+
+	return msg
+}
+
+// Is returns true if target is the ErrorKind of this Error.
+func (e *Error) Is(target error) bool {
+	if kind, ok := target.(ErrorKind); ok {
+		return e.Kind == kind
+	}
+	return false
+}
+
+// IsTransientError returns true if err is a vAPI error that may succeed if retried,
+// such as RESOURCE_BUSY, SERVICE_UNAVAILABLE or TIMED_OUT, or a temporary network error.
+func IsTransientError(err error) bool {
+	var e *Error
+	if errors.As(err, &e) {
+		switch e.Kind {
+		case ErrorResourceBusy, ErrorServiceUnavailable, ErrorTimedOut:
+			return true
+		}
+		switch e.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var t interface {
+		// Temporary is implemented by url.Error and net.Error
+		Temporary() bool
+	}
+	if errors.As(err, &t) {
+		return t.Temporary()
+	}
+
+	return false
+}
+
+// newError decodes an Error from the given response, in either "/api" or "/rest" error format.
+func newError(res *http.Response) error {
+	e := &Error{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		Method:     res.Request.Method,
+		URL:        res.Request.URL.String(),
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	var detail struct {
+		ErrorType string            `json:"error_type"` // "/api" format
+		Messages  []json.RawMessage `json:"messages"`
+		Data      json.RawMessage   `json:"data"`
+
+		Type  string `json:"type"` // "/rest" format
+		Value struct {
+			Messages []json.RawMessage `json:"messages"`
+			Data     json.RawMessage   `json:"data"`
+		} `json:"value"`
+	}
+
+	if json.Unmarshal(body, &detail) == nil {
+		switch {
+		case detail.ErrorType != "":
+			e.Kind = ErrorKind(detail.ErrorType)
+		case detail.Type != "":
+			name := detail.Type[strings.LastIndex(detail.Type, ".")+1:]
+			e.Kind = ErrorKind(strings.ToUpper(name))
+			detail.Messages = detail.Value.Messages
+			detail.Data = detail.Value.Data
+		}
+
+		for _, m := range detail.Messages {
+			var msg LocalizableMessage
+			if json.Unmarshal(m, &msg) != nil {
+				_ = json.Unmarshal(m, &msg.DefaultMessage)
+			}
+			e.Messages = append(e.Messages, msg)
+		}
+		e.Data = detail.Data
+	} else if msg := bytes.TrimSpace(body); len(msg) != 0 {
+		e.Messages = []LocalizableMessage{{DefaultMessage: string(msg)}}
+	}
+
+	if e.Kind == "" {
+		e.Kind = statusErrorKind[res.StatusCode]
+	}
+
+	return e
+}
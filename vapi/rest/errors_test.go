@@ -0,0 +1,204 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func newTestClient(t *testing.T, h http.HandlerFunc) *rest.Client {
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &rest.Client{Client: soap.NewClient(u, true)}
+}
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		path string
+		code int
+		body string
+		kind rest.ErrorKind
+		msg  string
+	}{
+		{"/api/vcenter/vm/vm-1", http.StatusNotFound,
+			`{"error_type":"NOT_FOUND","messages":[{"id":"vm.not_found","default_message":"VM not found"}]}`,
+			rest.ErrorNotFound, "VM not found"},
+		{"/rest/com/vmware/cis/tagging/tag", http.StatusBadRequest,
+			`{"type":"com.vmware.vapi.std.errors.already_exists","value":{"messages":[{"id":"tag.exists","default_message":"tag exists"}]}}`,
+			rest.ErrorAlreadyExists, "tag exists"},
+		{"/api/session", http.StatusUnauthorized, "",
+			rest.ErrorUnauthenticated, ""},
+		{"/api/vcenter/vm", http.StatusServiceUnavailable, "try again later",
+			rest.ErrorServiceUnavailable, "try again later"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.path, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.code)
+				_, _ = fmt.Fprint(w, test.body)
+			})
+
+			err := c.Do(context.Background(), c.Resource(test.path).Request(http.MethodGet), nil)
+
+			var rerr *rest.Error
+			if !errors.As(err, &rerr) {
+				t.Fatalf("expected *rest.Error, got %T: %v", err, err)
+			}
+			if rerr.Kind != test.kind {
+				t.Errorf("kind=%s", rerr.Kind)
+			}
+			if rerr.StatusCode != test.code {
+				t.Errorf("status=%d", rerr.StatusCode)
+			}
+			if !errors.Is(err, test.kind) {
+				t.Errorf("expected errors.Is(%s)", test.kind)
+			}
+			if !strings.Contains(err.Error(), test.msg) {
+				t.Errorf("error %q does not contain %q", err, test.msg)
+			}
+			if rest.IsTransientError(err) != (test.kind == rest.ErrorServiceUnavailable) {
+				t.Errorf("IsTransientError=%t", rest.IsTransientError(err))
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var calls int32
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = fmt.Fprint(w, `"ok"`)
+	})
+
+	ctx := context.Background()
+	req := func() *http.Request {
+		return c.Resource("/api/test").Request(http.MethodPut, map[string]string{"name": "test"})
+	}
+
+	// no retry by default
+	if err := c.Do(ctx, req(), nil); !errors.Is(err, rest.ErrorServiceUnavailable) {
+		t.Fatalf("err=%v", err)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	c.SetRetryPolicy(&rest.RetryPolicy{Attempts: 2, Delay: time.Millisecond})
+	if err := c.Do(ctx, req(), nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("calls=%d", n)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	var res string
+	if err := c.Do(c.WithRetryPolicy(ctx, &rest.RetryPolicy{Attempts: 3, Delay: time.Millisecond}), req(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res != "ok" {
+		t.Errorf("res=%q", res)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	policy := &rest.RetryPolicy{Attempts: 5, Retryable: func(error) bool { return false }}
+	if err := c.Do(c.WithRetryPolicy(ctx, policy), req(), nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("calls=%d", n)
+	}
+
+	// POST is not retried unless included in Methods
+	post := func() *http.Request {
+		return c.Resource("/api/test").Request(http.MethodPost, map[string]string{"name": "test"})
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	policy = &rest.RetryPolicy{Attempts: 3, Delay: time.Millisecond}
+	if err := c.Do(c.WithRetryPolicy(ctx, policy), post(), nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("calls=%d", n)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	policy.Methods = []string{http.MethodPost}
+	if err := c.Do(c.WithRetryPolicy(ctx, policy), post(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("calls=%d", n)
+	}
+}
+
+func TestPageIterator(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"items":[1,2],"next":"p2"}`,
+		"p2": `{"items":[3],"next":"p3"}`,
+		"p3": `{"items":[4,5]}`,
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter") != "x" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = fmt.Fprint(w, pages[r.URL.Query().Get("page")])
+	})
+
+	it := c.NewPageIterator(c.Resource("/api/items").WithParam("filter", "x"))
+
+	var all []int
+	for it.Next(context.Background()) {
+		var items []int
+		if err := it.Decode(&items); err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, items...)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(all) != "[1 2 3 4 5]" {
+		t.Errorf("items=%v", all)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// Page is a single page of results from a paged "/api" list endpoint.
+type Page struct {
+	Items json.RawMessage `json:"items"`
+	Next  string          `json:"next,omitempty"`
+}
+
+// PageIterator iterates over the pages of a paged "/api" list endpoint.
+// The first page is requested with the Resource's query parameters, each following page
+// is requested with the Param query parameter set to the Next token of the previous page,
+// until a page is returned without a Next token.
+//
+//	it := c.NewPageIterator(c.Resource("/api/..."))
+//	for it.Next(ctx) {
+//		var items []Item
+//		if err := it.Decode(&items); err != nil {
+//			return err
+//		}
+//	}
+//	return it.Err()
+type PageIterator struct {
+	// Param is the query parameter used to request the next page, defaults to "page".
+	Param string
+
+	c    *Client
+	u    url.URL
+	page *Page
+	err  error
+	done bool
+}
+
+// NewPageIterator returns a PageIterator for the given Resource.
+func (c *Client) NewPageIterator(r *Resource) *PageIterator {
+	return &PageIterator{Param: "page", c: c, u: *r.u}
+}
+
+// Next fetches the next page, returning false when there are no more pages or an error occurred.
+func (it *PageIterator) Next(ctx context.Context) bool {
+	if it.done || it.err != nil {
+		return false
+	}
+
+	r := &Resource{u: &url.URL{}}
+	*r.u = it.u
+	if it.page != nil {
+		q := r.u.Query()
+		q.Set(it.Param, it.page.Next)
+		r.u.RawQuery = q.Encode()
+	}
+
+	var page Page
+	if it.err = it.c.Do(ctx, r.Request(http.MethodGet), &page); it.err != nil {
+		return false
+	}
+
+	it.page = &page
+	it.done = page.Next == ""
+
+	return true
+}
+
+// Page returns the current page.
+func (it *PageIterator) Page() *Page {
+	return it.page
+}
+
+// Decode decodes the Items of the current page into val.
+func (it *PageIterator) Decode(val interface{}) error {
+	if it.page == nil || len(it.page.Items) == 0 {
+		return nil
+	}
+	return json.Unmarshal(it.page.Items, val)
+}
+
+// Err returns the error, if any, that occurred during iteration.
+func (it *PageIterator) Err() error {
+	return it.err
+}
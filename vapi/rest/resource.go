@@ -84,7 +84,7 @@ func (r *Resource) WithPathEncodedParam(name string, value string) *Resource {
 // Request returns a new http.Request for the given method.
 // An optional body can be provided for POST and PATCH methods.
 func (r *Resource) Request(method string, body ...interface{}) *http.Request {
-	rdr := io.MultiReader() // empty body by default
+	var rdr io.Reader = http.NoBody // empty body by default
 	if len(body) != 0 {
 		rdr = encode(body[0])
 	}
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryDelay is the delay before the first retry, if RetryPolicy.Delay is not set.
+const DefaultRetryDelay = 500 * time.Millisecond
+
+// defaultRetryMethods are the idempotent methods retried, if RetryPolicy.Methods is not set.
+var defaultRetryMethods = []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}
+
+// RetryPolicy configures the retry of failed requests.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts, including the first request.
+	Attempts int
+	// Methods are the request methods retried, defaults to the idempotent GET, HEAD, PUT and DELETE methods.
+	// POST and PATCH requests are only retried if included, as a request that failed with a transient error
+	// may still have been applied by the server.
+	Methods []string
+	// Delay before the first retry, doubled after each retry. Defaults to DefaultRetryDelay.
+	Delay time.Duration
+	// MaxDelay limits the delay between retries, if non-zero.
+	MaxDelay time.Duration
+	// Retryable returns true if a request that failed with the given error should be retried.
+	// Defaults to IsTransientError.
+	Retryable func(error) bool
+}
+
+type retryContext struct{}
+
+// SetRetryPolicy sets the RetryPolicy used by Do. A nil policy disables retries.
+func (c *Client) SetRetryPolicy(p *RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = p
+}
+
+// RetryPolicy returns the RetryPolicy set by SetRetryPolicy.
+func (c *Client) RetryPolicy() *RetryPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.retry
+}
+
+// WithRetryPolicy returns a new Context that overrides the Client's RetryPolicy.
+// Calls to a VAPI REST client with this context will use the provided RetryPolicy, where nil disables retries.
+func (c *Client) WithRetryPolicy(ctx context.Context, p *RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryContext{}, p)
+}
+
+// retryMethod returns true if requests with the given method may be retried.
+func (p *RetryPolicy) retryMethod(method string) bool {
+	methods := p.Methods
+	if methods == nil {
+		methods = defaultRetryMethods
+	}
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return IsTransientError(err)
+	}
+	return p.Retryable(err)
+}
+
+// rewind resets the request body for another attempt, returning false if the body cannot be reset.
+func rewind(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+// do calls f, retrying as configured by the policy.
+func (p *RetryPolicy) do(ctx context.Context, req *http.Request, f func() error) error {
+	if p == nil || !p.retryMethod(req.Method) {
+		return f()
+	}
+
+	delay := p.Delay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.Attempts || !p.retryable(err) || !rewind(req) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if p.MaxDelay != 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+}
@@ -20,9 +20,11 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha1"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/pem"
 	"errors"
@@ -36,6 +38,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -65,6 +68,7 @@ type item struct {
 	*library.Item
 	File     []library.File
 	Template *types.ManagedObjectReference
+	Versions []vcenter.TemplateVersion
 }
 
 type content struct {
@@ -172,6 +176,8 @@ func New(u *url.URL, settings []vim.BaseOptionValue) ([]string, http.Handler) {
 		{internal.SecurityPoliciesPath, s.librarySecurityPolicies},
 		{internal.TrustedCertificatesPath, s.libraryTrustedCertificates},
 		{internal.TrustedCertificatesPath + "/", s.libraryTrustedCertificatesID},
+		{internal.VCenterVMPath, s.vcenterVM},
+		{internal.VCenterVMPath + "/", s.vcenterVMID},
 	}
 
 	for i := range handlers {
@@ -360,6 +366,35 @@ func BadRequest(w http.ResponseWriter, kind string) {
 	}
 }
 
+// errorKindStatus maps vAPI error kinds to the http status of "/api" error responses, defaulting to http.StatusBadRequest.
+var errorKindStatus = map[rest.ErrorKind]int{
+	rest.ErrorNotFound:            http.StatusNotFound,
+	rest.ErrorUnauthenticated:     http.StatusUnauthorized,
+	rest.ErrorUnauthorized:        http.StatusForbidden,
+	rest.ErrorServiceUnavailable:  http.StatusServiceUnavailable,
+	rest.ErrorInternalServerError: http.StatusInternalServerError,
+	rest.ErrorError:               http.StatusInternalServerError,
+}
+
+// APIError responds with the http status and json encoded vAPI error for the given error kind.
+// For use with "/api" endpoints, see BadRequest for "/rest" endpoints.
+func APIError(w http.ResponseWriter, kind rest.ErrorKind, msg ...string) {
+	status, ok := errorKindStatus[kind]
+	if !ok {
+		status = http.StatusBadRequest
+	}
+	w.WriteHeader(status)
+
+	res := rest.Error{Kind: kind}
+	for _, m := range msg {
+		res.Messages = append(res.Messages, rest.LocalizableMessage{DefaultMessage: m})
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Panic(err)
+	}
+}
+
 func (*handler) error(w http.ResponseWriter, err error) {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 	log.Print(err)
@@ -1377,8 +1412,21 @@ func (s *handler) libraryItemUpdateSessionFileID(w http.ResponseWriter, r *http.
 		}
 		OK(w, ids)
 	case "remove":
-		delete(s.Update, id)
-		OK(w)
+		var spec struct {
+			Name string `json:"file_name"`
+		}
+		if s.decode(r, w, &spec) {
+			i := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
+			files := make([]library.File, 0, len(i.File))
+			for _, f := range i.File {
+				if f.Name != spec.Name {
+					files = append(files, f)
+				}
+			}
+			i.File = files
+			_ = os.Remove(path.Join(libraryPath(up.Library, i.ID), spec.Name))
+			OK(w)
+		}
 	case "validate":
 		// TODO
 	}
@@ -1592,7 +1640,8 @@ func (s *handler) libraryItemFileCreate(up *update, name string, body io.ReadClo
 		return err
 	}
 
-	n, err := io.Copy(file, in)
+	sum := sha1.New()
+	n, err := io.Copy(io.MultiWriter(file, sum), in)
 	_ = body.Close()
 	if err != nil {
 		return err
@@ -1602,13 +1651,26 @@ func (s *handler) libraryItemFileCreate(up *update, name string, body io.ReadClo
 		return err
 	}
 
-	i := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
-	i.File = append(i.File, library.File{
-		Cached:  types.NewBool(true),
+	info := library.File{
+		Cached: types.NewBool(true),
+		Checksum: &library.Checksum{
+			Algorithm: "SHA1",
+			Checksum:  hex.EncodeToString(sum.Sum(nil)),
+		},
 		Name:    name,
 		Size:    types.NewInt64(n),
 		Version: "1",
-	})
+	}
+
+	i := s.Library[up.Library.ID].Item[up.Session.LibraryItemID]
+	files := make([]library.File, 0, len(i.File)+1)
+	for _, f := range i.File {
+		if f.Name == name {
+			continue // replaced
+		}
+		files = append(files, f)
+	}
+	i.File = append(files, info)
 
 	return nil
 }
@@ -1694,7 +1756,7 @@ func (s *handler) libraryItemFileID(w http.ResponseWriter, r *http.Request) {
 
 func (i *item) cp() *item {
 	nitem := *i.Item
-	return &item{&nitem, i.File, i.Template}
+	return &item{&nitem, i.File, i.Template, nil}
 }
 
 func (i *item) ovf() string {
@@ -2055,6 +2117,7 @@ func (s *handler) libraryItemCreateTemplate(w http.ResponseWriter, r *http.Reque
 			LibraryID:        l.Library.ID,
 			Name:             spec.Name,
 			Type:             library.ItemTypeVMTX,
+			ContentVersion:   "1",
 			CreationTime:     types.NewTime(time.Now()),
 			LastModifiedTime: types.NewTime(time.Now()),
 		},
@@ -2100,6 +2163,9 @@ func (s *handler) libraryItemTemplateID(w http.ResponseWriter, r *http.Request)
 		case "check-outs":
 			s.libraryItemCheckOuts(item, w, r)
 			return
+		case "versions":
+			s.libraryItemVersions(item, route[2:], w, r)
+			return
 		default:
 			http.NotFound(w, r)
 			return
@@ -2108,7 +2174,7 @@ func (s *handler) libraryItemTemplateID(w http.ResponseWriter, r *http.Request)
 
 	if r.Method == http.MethodGet {
 		// TODO: add mock data
-		t := &vcenter.TemplateInfo{}
+		t := &vcenter.TemplateInfo{VmTemplate: item.Template.Value}
 		OK(w, t)
 		return
 	}
@@ -2160,13 +2226,110 @@ func (s *handler) libraryItemCheckOuts(item *item, w http.ResponseWriter, r *htt
 		}
 		OK(w, ref.Value)
 	case "check-in":
-		// TODO: increment ContentVersion
-		OK(w, "0")
+		route := strings.Split(r.URL.Path, "/")
+		ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: route[len(route)-1]}
+		version, err := s.libraryItemNewVersion(item, ref)
+		if err != nil {
+			BadRequest(w, err.Error())
+			return
+		}
+		OK(w, version)
 	default:
 		http.NotFound(w, r)
 	}
 }
 
+// libraryItemNewVersion retains the current VM template of item as a previous version,
+// replacing it with the given template.
+func (s *handler) libraryItemNewVersion(item *item, ref types.ManagedObjectReference) (string, error) {
+	prev := *item.Template
+
+	err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+		rename := func(ref types.ManagedObjectReference, name string) error {
+			task, err := object.NewVirtualMachine(c, ref).Rename(ctx, name)
+			if err != nil {
+				return err
+			}
+			return task.Wait(ctx)
+		}
+		if err := rename(prev, fmt.Sprintf("%s-v%s", item.Name, item.ContentVersion)); err != nil {
+			return err
+		}
+		return rename(ref, item.Name)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	item.Versions = append(item.Versions, vcenter.TemplateVersion{
+		Version:    item.ContentVersion,
+		VMTemplate: prev.Value,
+	})
+
+	version, _ := strconv.Atoi(item.ContentVersion)
+	item.ContentVersion = strconv.Itoa(version + 1)
+	item.LastModifiedTime = types.NewTime(time.Now())
+	item.Template = &ref
+
+	return item.ContentVersion, nil
+}
+
+func (s *handler) libraryItemVersions(item *item, route []string, w http.ResponseWriter, r *http.Request) {
+	if len(route) == 0 {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		OK(w, item.Versions)
+		return
+	}
+
+	index := -1
+	for i, v := range item.Versions {
+		if v.Version == route[0] {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		http.NotFound(w, r)
+		return
+	}
+	version := item.Versions[index]
+
+	switch r.Method {
+	case http.MethodGet:
+		OK(w, struct {
+			VMTemplate string `json:"vm_template"`
+		}{version.VMTemplate})
+	case http.MethodDelete:
+		item.Versions = append(item.Versions[:index], item.Versions[index+1:]...)
+		s.deleteVM(&types.ManagedObjectReference{Type: "VirtualMachine", Value: version.VMTemplate})
+		OK(w)
+	case http.MethodPost:
+		if r.URL.Query().Get("action") != "rollback" {
+			http.NotFound(w, r)
+			return
+		}
+		var spec struct {
+			vcenter.Rollback `json:"spec"`
+		}
+		if !s.decode(r, w, &spec) {
+			return
+		}
+		ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: version.VMTemplate}
+		v, err := s.libraryItemNewVersion(item, ref)
+		if err != nil {
+			BadRequest(w, err.Error())
+			return
+		}
+		item.Versions = append(item.Versions[:index], item.Versions[index+1:]...)
+		OK(w, v)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
 // defaultSecurityPolicies generates the initial set of security policies always present on vCenter.
 func defaultSecurityPolicies() []library.ContentSecurityPoliciesInfo {
 	policyID, _ := uuid.NewUUID()
@@ -0,0 +1,1083 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// vmFault responds with the vAPI error corresponding to the given SOAP fault
+func vmFault(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+
+	kind := rest.ErrorError
+	if f, ok := err.(task.Error); ok {
+		switch fault := f.Fault().(type) {
+		case *types.InvalidPowerState:
+			kind = rest.ErrorNotAllowedInCurrentState
+			if fault.RequestedState == fault.ExistingState {
+				kind = rest.ErrorAlreadyInDesiredState
+			}
+		case *types.InvalidState, *types.ToolsUnavailable:
+			kind = rest.ErrorNotAllowedInCurrentState
+		case *types.ManagedObjectNotFound:
+			kind = rest.ErrorNotFound
+		case *types.InvalidArgument, *types.InvalidDeviceSpec, *types.InvalidVmConfig, *types.InvalidDatastorePath:
+			kind = rest.ErrorInvalidArgument
+		}
+	}
+
+	APIError(w, kind, err.Error())
+}
+
+// upperSnake converts a vim25 enum value such as "upgradeAtPowerCycle" to a vAPI enum value such as "UPGRADE_AT_POWER_CYCLE"
+func upperSnake(s string) string {
+	var b strings.Builder
+	r := []rune(strings.ReplaceAll(s, "-", "_"))
+
+	for i, c := range r {
+		if i > 0 && c != '_' && r[i-1] != '_' {
+			prev := r[i-1]
+			split := false
+			switch {
+			case unicode.IsUpper(c):
+				split = unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+					(i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(prev))
+			case unicode.IsDigit(c):
+				split = unicode.IsLetter(prev)
+			}
+			if split {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+
+	return b.String()
+}
+
+// lowerCamel converts a vAPI enum value such as "UPGRADE_AT_POWER_CYCLE" to a vim25 enum value such as "upgradeAtPowerCycle"
+func lowerCamel(s string) string {
+	parts := strings.Split(strings.ToLower(s), "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// guestOS converts a guest ID such as "ubuntu64Guest" to a vAPI GuestOS value such as "UBUNTU_64"
+func guestOS(id string) string {
+	return upperSnake(strings.TrimSuffix(id, "Guest"))
+}
+
+// guestID converts a vAPI GuestOS value to a guest ID, returning an empty string if there is no such guest ID
+func guestID(name string) string {
+	for _, id := range simulator.GuestID {
+		if guestOS(string(id)) == name {
+			return string(id)
+		}
+	}
+	return ""
+}
+
+func vmRef(id string) types.ManagedObjectReference {
+	return types.ManagedObjectReference{Type: "VirtualMachine", Value: id}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *handler) vcenterVM(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listVMs(w, r)
+	case http.MethodPost:
+		var spec vcenter.VMCreateSpec
+		if s.decode(r, w, &spec) {
+			s.createVM(w, spec)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) listVMs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := vcenter.VMFilter{
+		VMs:           q["vms"],
+		Names:         q["names"],
+		Folders:       q["folders"],
+		Datacenters:   q["datacenters"],
+		Hosts:         q["hosts"],
+		Clusters:      q["clusters"],
+		ResourcePools: q["resource_pools"],
+		PowerStates:   q["power_states"],
+	}
+
+	res := []vcenter.VMSummary{}
+
+	err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+		kind := []string{"VirtualMachine"}
+		m := view.NewManager(c)
+
+		// within returns the VMs in any of the given containers, or nil if no containers are given
+		within := func(typ string, ids []string) (map[types.ManagedObjectReference]bool, error) {
+			if len(ids) == 0 {
+				return nil, nil
+			}
+
+			set := make(map[types.ManagedObjectReference]bool)
+
+			for _, id := range ids {
+				root := types.ManagedObjectReference{Type: typ, Value: id}
+				if simulator.Map.Get(root) == nil {
+					continue
+				}
+
+				v, err := m.CreateContainerView(ctx, root, kind, true)
+				if err != nil {
+					return nil, err
+				}
+
+				refs, err := v.Find(ctx, kind, nil)
+				_ = v.Destroy(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, ref := range refs {
+					set[ref] = true
+				}
+			}
+
+			return set, nil
+		}
+
+		datacenters, err := within("Datacenter", filter.Datacenters)
+		if err != nil {
+			return err
+		}
+
+		clusters, err := within("ClusterComputeResource", filter.Clusters)
+		if err != nil {
+			return err
+		}
+
+		v, err := m.CreateContainerView(ctx, c.ServiceContent.RootFolder, kind, true)
+		if err != nil {
+			return err
+		}
+
+		props := []string{"name", "parent", "resourcePool", "runtime.host", "runtime.powerState", "config.hardware", "config.template"}
+		var vms []mo.VirtualMachine
+		err = v.Retrieve(ctx, kind, props, &vms)
+		_ = v.Destroy(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, vm := range vms {
+			if vm.Config == nil || vm.Config.Template {
+				continue
+			}
+			if (datacenters != nil && !datacenters[vm.Self]) || (clusters != nil && !clusters[vm.Self]) {
+				continue
+			}
+
+			state := upperSnake(string(vm.Runtime.PowerState))
+			match := func(list []string, val string) bool {
+				return len(list) == 0 || contains(list, val)
+			}
+			ref := func(r *types.ManagedObjectReference) string {
+				if r == nil {
+					return ""
+				}
+				return r.Value
+			}
+
+			if !match(filter.VMs, vm.Self.Value) ||
+				!match(filter.Names, vm.Name) ||
+				!match(filter.Folders, ref(vm.Parent)) ||
+				!match(filter.Hosts, ref(vm.Runtime.Host)) ||
+				!match(filter.ResourcePools, ref(vm.ResourcePool)) ||
+				!match(filter.PowerStates, state) {
+				continue
+			}
+
+			res = append(res, vcenter.VMSummary{
+				VM:            vm.Self.Value,
+				Name:          vm.Name,
+				PowerState:    state,
+				CPUCount:      int(vm.Config.Hardware.NumCPU),
+				MemorySizeMiB: int64(vm.Config.Hardware.MemoryMB),
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		vmFault(w, err)
+		return
+	}
+
+	StatusOK(w, res)
+}
+
+// vmPlacement resolves the folder, pool, host and datastore name of the given placement spec
+func vmPlacement(ctx context.Context, c *vim25.Client, p *vcenter.VMPlacement) (*object.Folder, *object.ResourcePool, *object.HostSystem, string, error) {
+	if p == nil || p.Folder == "" || p.Datastore == "" {
+		return nil, nil, nil, "", fmt.Errorf("placement folder and datastore must be specified")
+	}
+
+	pc := property.DefaultCollector(c)
+	folder := object.NewFolder(c, types.ManagedObjectReference{Type: "Folder", Value: p.Folder})
+
+	var ds mo.Datastore
+	err := pc.RetrieveOne(ctx, types.ManagedObjectReference{Type: "Datastore", Value: p.Datastore}, []string{"name"}, &ds)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
+	var host *object.HostSystem
+	var pool types.ManagedObjectReference
+
+	if p.Host != "" {
+		host = object.NewHostSystem(c, types.ManagedObjectReference{Type: "HostSystem", Value: p.Host})
+	}
+
+	switch {
+	case p.ResourcePool != "":
+		pool = types.ManagedObjectReference{Type: "ResourcePool", Value: p.ResourcePool}
+	case p.Cluster != "":
+		var cr mo.ClusterComputeResource
+		ref := types.ManagedObjectReference{Type: "ClusterComputeResource", Value: p.Cluster}
+		if err = pc.RetrieveOne(ctx, ref, []string{"resourcePool"}, &cr); err != nil {
+			return nil, nil, nil, "", err
+		}
+		pool = *cr.ResourcePool
+	case host != nil:
+		rp, err := host.ResourcePool(ctx)
+		if err != nil {
+			return nil, nil, nil, "", err
+		}
+		pool = rp.Reference()
+	default:
+		return nil, nil, nil, "", fmt.Errorf("placement resource_pool, cluster or host must be specified")
+	}
+
+	return folder, object.NewResourcePool(c, pool), host, ds.Name, nil
+}
+
+func (s *handler) createVM(w http.ResponseWriter, spec vcenter.VMCreateSpec) {
+	id := guestID(spec.GuestOS)
+	if id == "" || spec.Name == "" {
+		APIError(w, rest.ErrorInvalidArgument, "invalid name or guest_OS")
+		return
+	}
+
+	config := types.VirtualMachineConfigSpec{
+		Name:    spec.Name,
+		GuestId: id,
+	}
+	if spec.CPU != nil {
+		config.NumCPUs = int32(spec.CPU.Count)
+		config.NumCoresPerSocket = int32(spec.CPU.CoresPerSocket)
+		config.CpuHotAddEnabled = types.NewBool(spec.CPU.HotAddEnabled)
+		config.CpuHotRemoveEnabled = types.NewBool(spec.CPU.HotRemoveEnabled)
+	}
+	if spec.Memory != nil {
+		config.MemoryMB = spec.Memory.SizeMiB
+		config.MemoryHotAddEnabled = types.NewBool(spec.Memory.HotAddEnabled)
+	}
+
+	var ref types.ManagedObjectReference
+
+	err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+		folder, pool, host, ds, err := vmPlacement(ctx, c, spec.Placement)
+		if err != nil {
+			return err
+		}
+
+		config.Files = &types.VirtualMachineFileInfo{VmPathName: fmt.Sprintf("[%s]", ds)}
+
+		task, err := folder.CreateVM(ctx, config, pool, host)
+		if err != nil {
+			return err
+		}
+
+		res, err := task.WaitForResult(ctx, nil)
+		if err != nil {
+			return err
+		}
+		ref = res.Result.(types.ManagedObjectReference)
+		vm := object.NewVirtualMachine(c, ref)
+
+		for _, disk := range spec.Disks {
+			if _, err = addDisk(ctx, vm, disk); err != nil {
+				return err
+			}
+		}
+		for _, nic := range spec.Nics {
+			if _, err = addNic(ctx, vm, nic); err != nil {
+				return err
+			}
+		}
+		for _, cdrom := range spec.Cdroms {
+			if _, err = addCdrom(ctx, vm, cdrom); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(task.Error); ok {
+			vmFault(w, err)
+		} else {
+			APIError(w, rest.ErrorInvalidArgument, err.Error())
+		}
+		return
+	}
+
+	StatusOK(w, ref.Value)
+}
+
+func (s *handler) vcenterVMID(w http.ResponseWriter, r *http.Request) {
+	p := strings.Split(strings.TrimPrefix(r.URL.Path, internal.VCenterVMPath+"/"), "/")
+	id := p[0]
+	ref := vmRef(id)
+
+	if simulator.Map.Get(ref) == nil {
+		APIError(w, rest.ErrorNotFound, fmt.Sprintf("VM %s not found", id))
+		return
+	}
+
+	switch strings.Join(p[1:], "/") {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			s.vmInfo(w, ref)
+		case http.MethodDelete:
+			s.vmDelete(w, ref)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	case "power":
+		s.vmPower(w, r, ref)
+	case "tools":
+		s.vmTools(w, r, ref)
+	case "tools/installer":
+		s.vmToolsInstaller(w, r, ref)
+	case "guest/identity", "guest/networking", "guest/networking/interfaces":
+		s.vmGuest(w, r, ref, strings.Join(p[1:], "/"))
+	default:
+		if len(p) >= 3 && p[1] == "hardware" {
+			s.vmHardware(w, r, ref, p[2], p[3:])
+			return
+		}
+		http.NotFound(w, r)
+	}
+}
+
+// vm retrieves the given properties of a VM
+func (s *handler) vm(ref types.ManagedObjectReference, props []string) (*mo.VirtualMachine, error) {
+	var vm mo.VirtualMachine
+	err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+		return property.DefaultCollector(c).RetrieveOne(ctx, ref, props, &vm)
+	})
+	return &vm, err
+}
+
+func (s *handler) vmInfo(w http.ResponseWriter, ref types.ManagedObjectReference) {
+	vm, err := s.vm(ref, []string{"name", "runtime.powerState", "config"})
+	if err != nil {
+		vmFault(w, err)
+		return
+	}
+
+	hw := vm.Config.Hardware
+	devices := object.VirtualDeviceList(hw.Device)
+
+	info := vcenter.VMInfo{
+		Name:       vm.Name,
+		PowerState: upperSnake(string(vm.Runtime.PowerState)),
+		GuestOS:    guestOS(vm.Config.GuestId),
+		Identity: &vcenter.VMIdentity{
+			Name:         vm.Name,
+			InstanceUUID: vm.Config.InstanceUuid,
+			BiosUUID:     vm.Config.Uuid,
+		},
+		Hardware: vcenter.VMHardware{
+			Version:       upperSnake(vm.Config.Version),
+			UpgradePolicy: "NEVER",
+			UpgradeStatus: "NONE",
+		},
+		CPU: vcenter.VMCPU{
+			Count:          int(hw.NumCPU),
+			CoresPerSocket: int(hw.NumCoresPerSocket),
+		},
+		Memory: vcenter.VMMemory{
+			SizeMiB: int64(hw.MemoryMB),
+		},
+		Disks:  make(map[string]vcenter.VMDiskInfo),
+		Nics:   make(map[string]vcenter.VMNicInfo),
+		Cdroms: make(map[string]vcenter.VMCdromInfo),
+	}
+	if vm.Config.CpuHotAddEnabled != nil {
+		info.CPU.HotAddEnabled = *vm.Config.CpuHotAddEnabled
+	}
+	if vm.Config.CpuHotRemoveEnabled != nil {
+		info.CPU.HotRemoveEnabled = *vm.Config.CpuHotRemoveEnabled
+	}
+	if vm.Config.MemoryHotAddEnabled != nil {
+		info.Memory.HotAddEnabled = *vm.Config.MemoryHotAddEnabled
+	}
+
+	for _, d := range devices {
+		key := strconv.Itoa(int(d.GetVirtualDevice().Key))
+		switch d.(type) {
+		case *types.VirtualDisk:
+			info.Disks[key] = diskInfo(devices, d)
+		case types.BaseVirtualEthernetCard:
+			info.Nics[key] = nicInfo(devices, d)
+		case *types.VirtualCdrom:
+			info.Cdroms[key] = cdromInfo(devices, d)
+		}
+	}
+
+	StatusOK(w, info)
+}
+
+func (s *handler) vmDelete(w http.ResponseWriter, ref types.ManagedObjectReference) {
+	err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+		task, err := object.NewVirtualMachine(c, ref).Destroy(ctx)
+		if err != nil {
+			return err
+		}
+		return task.Wait(ctx)
+	})
+	if err != nil {
+		vmFault(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *handler) vmPower(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference) {
+	switch r.Method {
+	case http.MethodGet:
+		vm, err := s.vm(ref, []string{"runtime.powerState"})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+		StatusOK(w, map[string]string{"state": upperSnake(string(vm.Runtime.PowerState))})
+	case http.MethodPost:
+		action := r.URL.Query().Get("action")
+		if !contains([]string{vcenter.VMPowerStart, vcenter.VMPowerStop, vcenter.VMPowerSuspend, vcenter.VMPowerReset}, action) {
+			APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", action))
+			return
+		}
+
+		err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+			vm := object.NewVirtualMachine(c, ref)
+			var task *object.Task
+			var err error
+
+			switch action {
+			case vcenter.VMPowerStart:
+				task, err = vm.PowerOn(ctx)
+			case vcenter.VMPowerStop:
+				task, err = vm.PowerOff(ctx)
+			case vcenter.VMPowerSuspend:
+				task, err = vm.Suspend(ctx)
+			case vcenter.VMPowerReset:
+				task, err = vm.Reset(ctx)
+			}
+			if err != nil {
+				return err
+			}
+			return task.Wait(ctx)
+		})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func deviceState(d types.BaseVirtualDevice) (string, bool) {
+	c := d.GetVirtualDevice().Connectable
+	if c == nil {
+		return "NOT_CONNECTED", false
+	}
+	state := "NOT_CONNECTED"
+	if c.Connected {
+		state = "CONNECTED"
+	}
+	return state, c.StartConnected
+}
+
+// controllerType returns the vAPI HostBusAdapterType of the given device's controller
+func controllerType(devices object.VirtualDeviceList, d types.BaseVirtualDevice) string {
+	switch devices.FindByKey(d.GetVirtualDevice().ControllerKey).(type) {
+	case types.BaseVirtualSCSIController:
+		return "SCSI"
+	case types.BaseVirtualSATAController:
+		return "SATA"
+	case *types.VirtualNVMEController:
+		return "NVME"
+	default:
+		return "IDE"
+	}
+}
+
+func diskInfo(devices object.VirtualDeviceList, d types.BaseVirtualDevice) vcenter.VMDiskInfo {
+	disk := d.(*types.VirtualDisk)
+	info := vcenter.VMDiskInfo{
+		Label:    disk.DeviceInfo.GetDescription().Label,
+		Type:     controllerType(devices, d),
+		Capacity: disk.CapacityInBytes,
+	}
+	if b, ok := disk.Backing.(types.BaseVirtualDeviceFileBackingInfo); ok {
+		info.Backing = vcenter.VMDiskBacking{
+			Type:     "VMDK_FILE",
+			VMDKFile: b.GetVirtualDeviceFileBackingInfo().FileName,
+		}
+	}
+	return info
+}
+
+func nicInfo(devices object.VirtualDeviceList, d types.BaseVirtualDevice) vcenter.VMNicInfo {
+	nic := d.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+	info := vcenter.VMNicInfo{
+		Label:      nic.DeviceInfo.GetDescription().Label,
+		Type:       strings.ToUpper(strings.TrimPrefix(devices.TypeName(d), "Virtual")),
+		MacType:    upperSnake(nic.AddressType),
+		MacAddress: nic.MacAddress,
+	}
+	info.State, info.StartConnected = deviceState(d)
+
+	switch b := nic.Backing.(type) {
+	case *types.VirtualEthernetCardNetworkBackingInfo:
+		info.Backing = vcenter.VMNicBacking{Type: "STANDARD_PORTGROUP", NetworkName: b.DeviceName}
+		if b.Network != nil {
+			info.Backing.Network = b.Network.Value
+		}
+	case *types.VirtualEthernetCardDistributedVirtualPortBackingInfo:
+		info.Backing = vcenter.VMNicBacking{Type: "DISTRIBUTED_PORTGROUP", Network: b.Port.PortgroupKey}
+	case *types.VirtualEthernetCardOpaqueNetworkBackingInfo:
+		info.Backing = vcenter.VMNicBacking{Type: "OPAQUE_NETWORK", Network: b.OpaqueNetworkId}
+	}
+
+	return info
+}
+
+func cdromInfo(devices object.VirtualDeviceList, d types.BaseVirtualDevice) vcenter.VMCdromInfo {
+	cdrom := d.(*types.VirtualCdrom)
+	info := vcenter.VMCdromInfo{
+		Label: cdrom.DeviceInfo.GetDescription().Label,
+		Type:  controllerType(devices, d),
+	}
+	info.State, info.StartConnected = deviceState(d)
+
+	switch b := cdrom.Backing.(type) {
+	case *types.VirtualCdromIsoBackingInfo:
+		info.Backing = vcenter.VMCdromBacking{Type: "ISO_FILE", ISOFile: b.FileName}
+	case *types.VirtualCdromAtapiBackingInfo:
+		info.Backing = vcenter.VMCdromBacking{Type: "HOST_DEVICE", HostDevice: b.DeviceName}
+	default:
+		info.Backing = vcenter.VMCdromBacking{Type: "CLIENT_DEVICE"}
+	}
+
+	return info
+}
+
+// addDevice adds the devices returned by f to the VM, returning the ID of the last device
+func addDevice(ctx context.Context, vm *object.VirtualMachine, f func(object.VirtualDeviceList) ([]types.BaseVirtualDevice, error)) (string, error) {
+	devices, err := vm.Device(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	add, err := f(devices)
+	if err != nil {
+		return "", err
+	}
+
+	if err = vm.AddDevice(ctx, add...); err != nil {
+		return "", err
+	}
+
+	after, err := vm.Device(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	kind := reflect.TypeOf(add[len(add)-1])
+	var id string
+	for _, d := range after {
+		if reflect.TypeOf(d) == kind && devices.FindByKey(d.GetVirtualDevice().Key) == nil {
+			id = strconv.Itoa(int(d.GetVirtualDevice().Key))
+		}
+	}
+
+	return id, nil
+}
+
+func addDisk(ctx context.Context, vm *object.VirtualMachine, spec vcenter.VMDiskCreateSpec) (string, error) {
+	return addDevice(ctx, vm, func(devices object.VirtualDeviceList) ([]types.BaseVirtualDevice, error) {
+		var add []types.BaseVirtualDevice
+
+		name := ""
+		switch {
+		case spec.Backing != nil:
+			name = spec.Backing.VMDKFile
+		case spec.NewVMDK != nil:
+			if spec.NewVMDK.Name != "" {
+				var mvm mo.VirtualMachine
+				if err := vm.Properties(ctx, vm.Reference(), []string{"name", "config.files"}, &mvm); err != nil {
+					return nil, err
+				}
+				var p object.DatastorePath
+				p.FromString(mvm.Config.Files.VmPathName)
+				p.Path = path.Join(path.Dir(p.Path), spec.NewVMDK.Name)
+				name = p.String()
+			}
+		default:
+			return nil, fmt.Errorf("disk new_vmdk or backing must be specified")
+		}
+
+		controller, err := devices.FindDiskController(strings.ToLower(spec.Type))
+		if err != nil {
+			if spec.Type != "" && spec.Type != "SCSI" {
+				return nil, err
+			}
+			c, err := devices.CreateSCSIController("")
+			if err != nil {
+				return nil, err
+			}
+			add = append(add, c)
+			controller = c.(types.BaseVirtualController)
+			devices = append(devices, c)
+		}
+
+		disk := devices.CreateDisk(controller, types.ManagedObjectReference{}, name)
+		disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).Datastore = nil
+		if spec.NewVMDK != nil {
+			disk.CapacityInBytes = spec.NewVMDK.Capacity
+			disk.CapacityInKB = spec.NewVMDK.Capacity / 1024
+		}
+
+		return append(add, disk), nil
+	})
+}
+
+func addNic(ctx context.Context, vm *object.VirtualMachine, spec vcenter.VMNicCreateSpec) (string, error) {
+	return addDevice(ctx, vm, func(devices object.VirtualDeviceList) ([]types.BaseVirtualDevice, error) {
+		if spec.Backing == nil || spec.Backing.Network == "" {
+			return nil, fmt.Errorf("nic backing network must be specified")
+		}
+
+		kind := map[string]string{
+			"STANDARD_PORTGROUP":    "Network",
+			"DISTRIBUTED_PORTGROUP": "DistributedVirtualPortgroup",
+			"OPAQUE_NETWORK":        "OpaqueNetwork",
+		}[spec.Backing.Type]
+		if kind == "" {
+			return nil, fmt.Errorf("invalid nic backing type %q", spec.Backing.Type)
+		}
+
+		ref := types.ManagedObjectReference{Type: kind, Value: spec.Backing.Network}
+		network, ok := object.NewReference(vm.Client(), ref).(object.NetworkReference)
+		if !ok {
+			return nil, fmt.Errorf("invalid network %s", ref)
+		}
+
+		backing, err := network.EthernetCardBackingInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		nic, err := devices.CreateEthernetCard(strings.ToLower(spec.Type), backing)
+		if err != nil {
+			return nil, err
+		}
+
+		card := nic.(types.BaseVirtualEthernetCard).GetVirtualEthernetCard()
+		if spec.MacAddress != "" {
+			card.MacAddress = spec.MacAddress
+			card.AddressType = string(types.VirtualEthernetCardMacTypeManual)
+		}
+		card.Connectable = &types.VirtualDeviceConnectInfo{StartConnected: spec.StartConnected}
+
+		return []types.BaseVirtualDevice{nic}, nil
+	})
+}
+
+func addCdrom(ctx context.Context, vm *object.VirtualMachine, spec vcenter.VMCdromCreateSpec) (string, error) {
+	return addDevice(ctx, vm, func(devices object.VirtualDeviceList) ([]types.BaseVirtualDevice, error) {
+		ide, err := devices.FindIDEController("")
+		if err != nil {
+			return nil, err
+		}
+
+		cdrom, err := devices.CreateCdrom(ide)
+		if err != nil {
+			return nil, err
+		}
+
+		if spec.Backing != nil {
+			switch spec.Backing.Type {
+			case "ISO_FILE":
+				cdrom = devices.InsertIso(cdrom, spec.Backing.ISOFile)
+			case "HOST_DEVICE":
+				cdrom.Backing = &types.VirtualCdromAtapiBackingInfo{
+					VirtualDeviceDeviceBackingInfo: types.VirtualDeviceDeviceBackingInfo{DeviceName: spec.Backing.HostDevice},
+				}
+			case "CLIENT_DEVICE":
+			default:
+				return nil, fmt.Errorf("invalid cdrom backing type %q", spec.Backing.Type)
+			}
+		}
+
+		cdrom.Connectable = &types.VirtualDeviceConnectInfo{StartConnected: spec.StartConnected}
+
+		return []types.BaseVirtualDevice{cdrom}, nil
+	})
+}
+
+// vmHardware handles the /hardware/{disk,ethernet,cdrom} endpoints
+func (s *handler) vmHardware(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference, kind string, p []string) {
+	var match func(types.BaseVirtualDevice) bool
+	var info func(object.VirtualDeviceList, types.BaseVirtualDevice) interface{}
+	var create func(context.Context, *object.VirtualMachine) (string, error)
+	var key string
+
+	switch kind {
+	case "disk":
+		key = "disk"
+		match = func(d types.BaseVirtualDevice) bool { _, ok := d.(*types.VirtualDisk); return ok }
+		info = func(l object.VirtualDeviceList, d types.BaseVirtualDevice) interface{} { return diskInfo(l, d) }
+		create = func(ctx context.Context, vm *object.VirtualMachine) (string, error) {
+			var spec vcenter.VMDiskCreateSpec
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				return "", err
+			}
+			return addDisk(ctx, vm, spec)
+		}
+	case "ethernet":
+		key = "nic"
+		match = func(d types.BaseVirtualDevice) bool { _, ok := d.(types.BaseVirtualEthernetCard); return ok }
+		info = func(l object.VirtualDeviceList, d types.BaseVirtualDevice) interface{} { return nicInfo(l, d) }
+		create = func(ctx context.Context, vm *object.VirtualMachine) (string, error) {
+			var spec vcenter.VMNicCreateSpec
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				return "", err
+			}
+			return addNic(ctx, vm, spec)
+		}
+	case "cdrom":
+		key = "cdrom"
+		match = func(d types.BaseVirtualDevice) bool { _, ok := d.(*types.VirtualCdrom); return ok }
+		info = func(l object.VirtualDeviceList, d types.BaseVirtualDevice) interface{} { return cdromInfo(l, d) }
+		create = func(ctx context.Context, vm *object.VirtualMachine) (string, error) {
+			var spec vcenter.VMCdromCreateSpec
+			if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+				return "", err
+			}
+			return addCdrom(ctx, vm, spec)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	vm, err := s.vm(ref, []string{"config.hardware.device"})
+	if err != nil {
+		vmFault(w, err)
+		return
+	}
+	devices := object.VirtualDeviceList(vm.Config.Hardware.Device).Select(match)
+
+	if len(p) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			res := []map[string]string{}
+			for _, d := range devices {
+				res = append(res, map[string]string{key: strconv.Itoa(int(d.GetVirtualDevice().Key))})
+			}
+			StatusOK(w, res)
+		case http.MethodPost:
+			var id string
+			err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+				id, err = create(ctx, object.NewVirtualMachine(c, ref))
+				return err
+			})
+			if err != nil {
+				if _, ok := err.(task.Error); ok {
+					vmFault(w, err)
+				} else {
+					APIError(w, rest.ErrorInvalidArgument, err.Error())
+				}
+				return
+			}
+			StatusOK(w, id)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	var device types.BaseVirtualDevice
+	if k, err := strconv.Atoi(p[0]); err == nil {
+		device = devices.FindByKey(int32(k))
+	}
+	if device == nil || len(p) != 1 {
+		APIError(w, rest.ErrorNotFound, fmt.Sprintf("%s %s not found", kind, p[0]))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		StatusOK(w, info(object.VirtualDeviceList(vm.Config.Hardware.Device), device))
+	case http.MethodDelete:
+		err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+			return object.NewVirtualMachine(c, ref).RemoveDevice(ctx, true, device)
+		})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// vmGuest handles the /guest/identity and /guest/networking endpoints, which require tools to be running
+func (s *handler) vmGuest(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference, p string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	vm, err := s.vm(ref, []string{"guest"})
+	if err != nil {
+		vmFault(w, err)
+		return
+	}
+
+	g := vm.Guest
+	if g == nil || g.ToolsRunningStatus != string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
+		APIError(w, rest.ErrorServiceUnavailable, "VMware Tools is not running")
+		return
+	}
+
+	switch p {
+	case "guest/identity":
+		StatusOK(w, vcenter.VMGuestIdentity{
+			Name:   guestOS(g.GuestId),
+			Family: upperSnake(strings.TrimSuffix(strings.TrimSuffix(g.GuestFamily, "Family"), "Guest")),
+			FullName: rest.LocalizableMessage{
+				ID:             "vmsg.guestos." + g.GuestId + ".label",
+				DefaultMessage: g.GuestFullName,
+				Args:           []string{},
+			},
+			HostName:  g.HostName,
+			IPAddress: g.IpAddress,
+		})
+	case "guest/networking":
+		res := vcenter.VMGuestNetworking{
+			DNSValues: &vcenter.VMGuestDNSValues{HostName: g.HostName},
+		}
+		for _, nic := range g.Net {
+			if nic.DnsConfig != nil {
+				res.DNSValues.DomainName = nic.DnsConfig.DomainName
+				res.DNS = &vcenter.VMGuestDNS{
+					IPAddresses:   nic.DnsConfig.IpAddress,
+					SearchDomains: nic.DnsConfig.SearchDomain,
+				}
+				break
+			}
+		}
+		StatusOK(w, res)
+	case "guest/networking/interfaces":
+		res := []vcenter.VMGuestInterface{}
+		for _, nic := range g.Net {
+			iface := vcenter.VMGuestInterface{
+				MacAddress: nic.MacAddress,
+				IP:         new(vcenter.VMGuestIPConfig),
+			}
+			if nic.DeviceConfigId > 0 {
+				iface.Nic = strconv.Itoa(int(nic.DeviceConfigId))
+			}
+			if nic.IpConfig != nil && len(nic.IpConfig.IpAddress) != 0 {
+				for _, ip := range nic.IpConfig.IpAddress {
+					iface.IP.IPAddresses = append(iface.IP.IPAddresses, vcenter.VMGuestIPAddress{
+						IPAddress:    ip.IpAddress,
+						PrefixLength: ip.PrefixLength,
+						State:        upperSnake(ip.State),
+					})
+				}
+			} else {
+				for _, ip := range nic.IpAddress {
+					iface.IP.IPAddresses = append(iface.IP.IPAddresses, vcenter.VMGuestIPAddress{
+						IPAddress: ip,
+						State:     "UNKNOWN",
+					})
+				}
+			}
+			res = append(res, iface)
+		}
+		StatusOK(w, res)
+	}
+}
+
+func (s *handler) vmTools(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference) {
+	switch r.Method {
+	case http.MethodGet:
+		vm, err := s.vm(ref, []string{"guest", "config.tools"})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+
+		res := vcenter.VMTools{
+			UpgradePolicy: "MANUAL",
+			RunState:      "NOT_RUNNING",
+			VersionStatus: "NOT_INSTALLED",
+		}
+		if vm.Config != nil && vm.Config.Tools != nil && vm.Config.Tools.ToolsUpgradePolicy != "" {
+			res.UpgradePolicy = upperSnake(vm.Config.Tools.ToolsUpgradePolicy)
+		}
+		if g := vm.Guest; g != nil {
+			res.RunState = upperSnake(strings.TrimPrefix(g.ToolsRunningStatus, "guestTools"))
+			if g.ToolsVersionStatus2 != "" {
+				res.VersionStatus = upperSnake(strings.TrimPrefix(g.ToolsVersionStatus2, "guestTools"))
+			}
+			if g.ToolsInstallType != "" {
+				res.InstallType = upperSnake(strings.TrimPrefix(g.ToolsInstallType, "guestToolsType"))
+			}
+			res.VersionNumber, _ = strconv.Atoi(g.ToolsVersion)
+			res.AutoUpdateSupported = g.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)
+		}
+
+		StatusOK(w, res)
+	case http.MethodPatch:
+		var spec vcenter.VMToolsUpdate
+		if !s.decode(r, w, &spec) {
+			return
+		}
+
+		config := types.VirtualMachineConfigSpec{
+			Tools: &types.ToolsConfigInfo{ToolsUpgradePolicy: lowerCamel(spec.UpgradePolicy)},
+		}
+		if err := s.reconfigVM(ref, config); err != nil {
+			vmFault(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPost:
+		if action := r.URL.Query().Get("action"); action != "upgrade" {
+			APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", action))
+			return
+		}
+
+		err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+			task, err := object.NewVirtualMachine(c, ref).UpgradeTools(ctx, "")
+			if err != nil {
+				return err
+			}
+			return task.Wait(ctx)
+		})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *handler) vmToolsInstaller(w http.ResponseWriter, r *http.Request, ref types.ManagedObjectReference) {
+	switch r.Method {
+	case http.MethodGet:
+		vm, err := s.vm(ref, []string{"runtime.toolsInstallerMounted"})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+		StatusOK(w, vcenter.VMToolsInstaller{IsConnected: vm.Runtime.ToolsInstallerMounted})
+	case http.MethodPost:
+		action := r.URL.Query().Get("action")
+		if action != "connect" && action != "disconnect" {
+			APIError(w, rest.ErrorInvalidArgument, fmt.Sprintf("invalid action: %q", action))
+			return
+		}
+
+		err := s.withClient(func(ctx context.Context, c *vim25.Client) error {
+			vm := object.NewVirtualMachine(c, ref)
+			if action == "connect" {
+				return vm.MountToolsInstaller(ctx)
+			}
+			return vm.UnmountToolsInstaller(ctx)
+		})
+		if err != nil {
+			vmFault(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -18,11 +18,12 @@ package tags
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
 )
 
 // Category provides methods to create, read, update, delete, and enumerate
@@ -155,7 +156,7 @@ func (c *Manager) GetCategories(ctx context.Context) ([]Category, error) {
 	for _, id := range ids {
 		category, err := c.GetCategory(ctx, id)
 		if err != nil {
-			if strings.Contains(err.Error(), http.StatusText(http.StatusNotFound)) {
+			if errors.Is(err, rest.ErrorNotFound) {
 				continue // deleted since last fetch
 			}
 			return nil, fmt.Errorf("get category %s: %v", id, err)
@@ -0,0 +1,208 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// CategoryTag identifies a tag by category and tag name.
+type CategoryTag struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+}
+
+// ParseCategoryTag parses a tag in "category:name" form.
+func ParseCategoryTag(s string) (CategoryTag, error) {
+	n := strings.SplitN(s, ":", 2)
+	if len(n) != 2 || n[0] == "" || n[1] == "" {
+		return CategoryTag{}, fmt.Errorf("invalid tag %q, expected category:name", s)
+	}
+	return CategoryTag{Category: n[0], Name: n[1]}, nil
+}
+
+func (t CategoryTag) String() string {
+	return t.Category + ":" + t.Name
+}
+
+// ReconcileSpec declares the desired set of tags attached to each object.
+type ReconcileSpec struct {
+	// Objects maps each object to its desired tags, any other tags attached to the object are detached.
+	Objects map[types.ManagedObjectReference][]CategoryTag
+	// Create missing categories and tags, otherwise a missing tag is an error.
+	Create bool
+	// DryRun computes the changes without applying them.
+	DryRun bool
+}
+
+// ReconcileChange describes the tags attached to and detached from an object.
+type ReconcileChange struct {
+	Object types.ManagedObjectReference `json:"object"`
+	Attach []CategoryTag                `json:"attach,omitempty"`
+	Detach []CategoryTag                `json:"detach,omitempty"`
+}
+
+// ReconcileResult describes the changes made, or to be made in the case of ReconcileSpec.DryRun.
+type ReconcileResult struct {
+	Categories []string          `json:"categories,omitempty"`
+	Tags       []CategoryTag     `json:"tags,omitempty"`
+	Changes    []ReconcileChange `json:"changes,omitempty"`
+}
+
+// Reconcile attaches and detaches tags such that each object in the spec has exactly its desired set of tags.
+// Currently attached tags are fetched with a single GetAttachedTagsOnObjects call, tags are attached with one
+// AttachTagToMultipleObjects call per tag and detached with one DetachMultipleTagsFromObject call per object.
+func (c *Manager) Reconcile(ctx context.Context, spec ReconcileSpec) (*ReconcileResult, error) {
+	res := new(ReconcileResult)
+
+	categories, err := c.GetCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(categories)) // category ID -> name
+	ids := make(map[string]string, len(categories))   // category name -> ID
+	for _, category := range categories {
+		names[category.ID] = category.Name
+		ids[category.Name] = category.ID
+	}
+
+	all, err := c.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[CategoryTag]string, len(all)) // category:name -> tag ID
+	for _, tag := range all {
+		tags[CategoryTag{names[tag.CategoryID], tag.Name}] = tag.ID
+	}
+
+	refs := make([]mo.Reference, 0, len(spec.Objects))
+	for ref := range spec.Objects {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Reference().String() < refs[j].Reference().String()
+	})
+
+	// Resolve desired tags, creating any that are missing
+	for _, ref := range refs {
+		for _, tag := range spec.Objects[ref.Reference()] {
+			if _, ok := tags[tag]; ok {
+				continue
+			}
+			if !spec.Create {
+				return nil, fmt.Errorf("tag %q not found", tag)
+			}
+
+			if _, ok := ids[tag.Category]; !ok {
+				res.Categories = append(res.Categories, tag.Category)
+				ids[tag.Category] = ""
+				if !spec.DryRun {
+					ids[tag.Category], err = c.CreateCategory(ctx, &Category{Name: tag.Category, Cardinality: "MULTIPLE"})
+					if err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			res.Tags = append(res.Tags, tag)
+			tags[tag] = ""
+			if !spec.DryRun {
+				tags[tag], err = c.CreateTag(ctx, &Tag{CategoryID: ids[tag.Category], Name: tag.Name})
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	attached, err := c.GetAttachedTagsOnObjects(ctx, refs)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[types.ManagedObjectReference][]Tag, len(attached))
+	for _, a := range attached {
+		current[a.ObjectID.Reference()] = a.Tags
+	}
+
+	attach := make(map[CategoryTag][]mo.Reference)
+	var order []CategoryTag
+
+	for _, ref := range refs {
+		change := ReconcileChange{Object: ref.Reference()}
+
+		desired := make(map[CategoryTag]bool)
+		for _, tag := range spec.Objects[change.Object] {
+			desired[tag] = true
+		}
+
+		var detach []string
+		for _, tag := range current[change.Object] {
+			ct := CategoryTag{names[tag.CategoryID], tag.Name}
+			if desired[ct] {
+				delete(desired, ct)
+				continue
+			}
+			change.Detach = append(change.Detach, ct)
+			detach = append(detach, tag.ID)
+		}
+
+		for _, tag := range spec.Objects[change.Object] {
+			if !desired[tag] {
+				continue
+			}
+			delete(desired, tag)
+			change.Attach = append(change.Attach, tag)
+			if _, ok := attach[tag]; !ok {
+				order = append(order, tag)
+			}
+			attach[tag] = append(attach[tag], ref)
+		}
+
+		if len(change.Attach) == 0 && len(change.Detach) == 0 {
+			continue
+		}
+		res.Changes = append(res.Changes, change)
+
+		// Detach before attach, in case of a category with SINGLE cardinality
+		if len(detach) != 0 && !spec.DryRun {
+			if err = c.DetachMultipleTagsFromObject(ctx, detach, ref); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if spec.DryRun {
+		return res, nil
+	}
+
+	for _, tag := range order {
+		if err = c.AttachTagToMultipleObjects(ctx, tags[tag], attach[tag]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
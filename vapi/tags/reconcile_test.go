@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tags_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestReconcile(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := tags.NewManager(c)
+
+		cat, err := m.CreateCategory(ctx, &tags.Category{Name: "env", Cardinality: "MULTIPLE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tagIDs := map[string]string{}
+		for _, name := range []string{"prod", "dev"} {
+			tagIDs[name], err = m.CreateTag(ctx, &tags.Tag{CategoryID: cat, Name: name})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		finder := find.NewFinder(vc)
+		vms, err := finder.VirtualMachineList(ctx, "*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm0, vm1 := vms[0].Reference(), vms[1].Reference()
+
+		if err = m.AttachTag(ctx, tagIDs["dev"], vm0); err != nil {
+			t.Fatal(err)
+		}
+		if err = m.AttachTag(ctx, tagIDs["prod"], vm1); err != nil {
+			t.Fatal(err)
+		}
+
+		prod := tags.CategoryTag{Category: "env", Name: "prod"}
+		dev := tags.CategoryTag{Category: "env", Name: "dev"}
+		team := tags.CategoryTag{Category: "team", Name: "a"}
+
+		spec := tags.ReconcileSpec{
+			Objects: map[types.ManagedObjectReference][]tags.CategoryTag{
+				vm0: {prod, team},
+				vm1: {prod},
+			},
+		}
+
+		if _, err = m.Reconcile(ctx, spec); err == nil {
+			t.Error("expected error for missing tag")
+		}
+
+		attached := func(ref types.ManagedObjectReference) []string {
+			res, err := m.GetAttachedTags(ctx, ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, tag := range res {
+				names = append(names, tag.Name)
+			}
+			sort.Strings(names)
+			return names
+		}
+
+		spec.Create = true
+		spec.DryRun = true
+
+		res, err := m.Reconcile(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expect := &tags.ReconcileResult{
+			Categories: []string{"team"},
+			Tags:       []tags.CategoryTag{team},
+			Changes: []tags.ReconcileChange{
+				{Object: vm0, Attach: []tags.CategoryTag{prod, team}, Detach: []tags.CategoryTag{dev}},
+			},
+		}
+		if !reflect.DeepEqual(res, expect) {
+			t.Errorf("dry run: %#v", res)
+		}
+
+		if names := attached(vm0); !reflect.DeepEqual(names, []string{"dev"}) {
+			t.Errorf("dry run changed tags: %v", names)
+		}
+
+		spec.DryRun = false
+
+		res, err = m.Reconcile(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(res, expect) {
+			t.Errorf("apply: %#v", res)
+		}
+
+		if names := attached(vm0); !reflect.DeepEqual(names, []string{"a", "prod"}) {
+			t.Errorf("vm0 tags: %v", names)
+		}
+		if names := attached(vm1); !reflect.DeepEqual(names, []string{"prod"}) {
+			t.Errorf("vm1 tags: %v", names)
+		}
+
+		res, err = m.Reconcile(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Categories)+len(res.Tags)+len(res.Changes) != 0 {
+			t.Errorf("expected no changes: %#v", res)
+		}
+	})
+}
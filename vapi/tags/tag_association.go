@@ -21,9 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/vmware/govmomi/vapi/internal"
 	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func (c *Manager) tagID(ctx context.Context, id string) (string, error) {
@@ -375,3 +377,109 @@ func (c *Manager) GetAttachedTagsOnObjects(ctx context.Context, objectID []mo.Re
 
 	return objs, nil
 }
+
+// SelectAttachedObjects returns the objects attached to tags matching all of the given selectors.
+// Each selector is one of "category:name", "category:*" (any tag in the category) or "name"
+// (a tag with the given name in any category).
+// Tags matching a single selector are ORed, the objects matching each selector are ANDed.
+// Only the tags of the given categories are fetched, unless a selector without a category is given.
+func (c *Manager) SelectAttachedObjects(ctx context.Context, selector ...string) ([]types.ManagedObjectReference, error) {
+	var all []Tag // fetched on demand for selectors without a category
+	categories := make(map[string]string)
+
+	matches := make([][]string, len(selector))
+	var ids []string
+
+	for i, s := range selector {
+		category, name := "", s
+		if n := strings.SplitN(s, ":", 2); len(n) == 2 {
+			category, name = n[0], n[1]
+		}
+
+		switch {
+		case category == "":
+			if all == nil {
+				var err error
+				if all, err = c.GetTags(ctx); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, tag := range all {
+				if tag.Name == name {
+					matches[i] = append(matches[i], tag.ID)
+				}
+			}
+		default:
+			id, ok := categories[category]
+			if !ok {
+				cat, err := c.GetCategory(ctx, category)
+				if err != nil {
+					return nil, fmt.Errorf("category %q: %s", category, err)
+				}
+				id = cat.ID
+				categories[category] = id
+			}
+
+			if name == "*" {
+				tags, err := c.ListTagsForCategory(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				matches[i] = tags
+				break
+			}
+
+			tags, err := c.GetTagsForCategory(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, tag := range tags {
+				if tag.Name == name {
+					matches[i] = append(matches[i], tag.ID)
+				}
+			}
+		}
+
+		if len(matches[i]) == 0 {
+			return nil, fmt.Errorf("tag %q not found", s)
+		}
+
+		ids = append(ids, matches[i]...)
+	}
+
+	attached, err := c.ListAttachedObjectsOnTags(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string][]mo.Reference, len(attached))
+	for _, a := range attached {
+		objects[a.TagID] = append(objects[a.TagID], a.ObjectIDs...)
+	}
+
+	var refs []types.ManagedObjectReference
+
+	for i, match := range matches {
+		seen := make(map[types.ManagedObjectReference]bool)
+		for _, ref := range refs {
+			seen[ref] = false
+		}
+
+		var set []types.ManagedObjectReference
+		for _, id := range match {
+			for _, obj := range objects[id] {
+				ref := obj.Reference()
+				if done, ok := seen[ref]; done || (i != 0 && !ok) {
+					continue
+				}
+				seen[ref] = true
+				set = append(set, ref)
+			}
+		}
+		refs = set
+	}
+
+	return refs, nil
+}
@@ -19,7 +19,9 @@ package tags_test
 import (
 	"context"
 	"errors"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/vmware/govmomi/simulator"
@@ -338,6 +340,119 @@ func attachTags(t *testing.T, ctx context.Context, mgr *tags.Manager, ref mo.Ref
 	return nil
 }
 
+// tagGets counts the requests to get a single tag
+type tagGets struct {
+	http.RoundTripper
+	n int
+}
+
+func (t *tagGets) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/tagging/tag/id:") {
+		t.n++
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+func TestManager_SelectAttachedObjects(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		m := tags.NewManager(c)
+
+		vm0 := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+		var vm1 *simulator.VirtualMachine
+		for _, obj := range simulator.Map.All("VirtualMachine") {
+			if obj.Reference() != vm0.Reference() {
+				vm1 = obj.(*simulator.VirtualMachine)
+				break
+			}
+		}
+		host := simulator.Map.Any("HostSystem").(*simulator.HostSystem)
+
+		attach := map[string][]mo.Reference{
+			"env:prod":  {vm0, vm1},
+			"env:dev":   nil,
+			"team:a":    {vm0},
+			"team:b":    nil,
+			"team:c":    nil,
+			"team:prod": {host},
+		}
+
+		ids := make(map[string]string)
+		for name, refs := range attach {
+			n := strings.SplitN(name, ":", 2)
+			cat, ok := ids[n[0]]
+			if !ok {
+				id, err := m.CreateCategory(ctx, &tags.Category{Name: n[0], Cardinality: "MULTIPLE"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				ids[n[0]], cat = id, id
+			}
+
+			id, err := m.CreateTag(ctx, &tags.Tag{Name: n[1], CategoryID: cat})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, ref := range refs {
+				if err = m.AttachTag(ctx, id, ref); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		gets := &tagGets{RoundTripper: c.Transport}
+		c.Transport = gets
+
+		tests := []struct {
+			selector []string
+			expect   []mo.Reference
+			gets     int
+		}{
+			{[]string{"env:prod"}, []mo.Reference{vm0, vm1}, 2},
+			{[]string{"env:*"}, []mo.Reference{vm0, vm1}, 0},
+			{[]string{"env:*", "team:a"}, []mo.Reference{vm0}, 4},
+			{[]string{"prod"}, []mo.Reference{vm0, vm1, host}, 6},
+			{[]string{"env:enoent"}, nil, 2},
+			{[]string{"enoent:prod"}, nil, 0},
+		}
+
+		for _, test := range tests {
+			gets.n = 0
+
+			refs, err := m.SelectAttachedObjects(ctx, test.selector...)
+			if test.expect == nil {
+				if err == nil {
+					t.Errorf("%s: expected error", test.selector)
+				}
+			} else if err != nil {
+				t.Errorf("%s: %s", test.selector, err)
+			}
+
+			found := make(map[string]bool)
+			for _, ref := range refs {
+				found[ref.String()] = true
+			}
+			for _, ref := range test.expect {
+				if !found[ref.Reference().String()] {
+					t.Errorf("%s: missing %s", test.selector, ref.Reference())
+				}
+			}
+			if len(refs) != len(test.expect) {
+				t.Errorf("%s: refs=%v", test.selector, refs)
+			}
+
+			if gets.n != test.gets {
+				t.Errorf("%s: %d tag requests, expected %d", test.selector, gets.n, test.gets)
+			}
+		}
+	})
+}
+
 func getTags(t *testing.T, ctx context.Context, mgr *tags.Manager, ref mo.Reference) ([]tags.Tag, error) {
 	t.Helper()
 
@@ -0,0 +1,524 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"github.com/vmware/govmomi/vapi/internal"
+	"github.com/vmware/govmomi/vapi/rest"
+)
+
+// vcenter vm
+// The vcenter.vm API provides services for managing virtual machines, their hardware, guest and tools.
+// https://developer.vmware.com/apis/vsphere-automation/latest/vcenter/vm/
+
+// VM power states
+const (
+	VMPowerStatePoweredOff = "POWERED_OFF"
+	VMPowerStatePoweredOn  = "POWERED_ON"
+	VMPowerStateSuspended  = "SUSPENDED"
+)
+
+// VM power actions
+const (
+	VMPowerStart   = "start"
+	VMPowerStop    = "stop"
+	VMPowerSuspend = "suspend"
+	VMPowerReset   = "reset"
+)
+
+// VMFilter specifies the properties that a VM must match to be included in ListVMs results.
+// An empty field matches any VM.
+type VMFilter struct {
+	VMs           []string
+	Names         []string
+	Folders       []string
+	Datacenters   []string
+	Hosts         []string
+	Clusters      []string
+	ResourcePools []string
+	PowerStates   []string
+}
+
+// VMSummary contains commonly used information about a virtual machine
+type VMSummary struct {
+	VM            string `json:"vm"`
+	Name          string `json:"name"`
+	PowerState    string `json:"power_state"`
+	CPUCount      int    `json:"cpu_count,omitempty"`
+	MemorySizeMiB int64  `json:"memory_size_MiB,omitempty"`
+}
+
+// VMPlacement specifies where a virtual machine is created
+type VMPlacement struct {
+	Folder       string `json:"folder,omitempty"`
+	ResourcePool string `json:"resource_pool,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Cluster      string `json:"cluster,omitempty"`
+	Datastore    string `json:"datastore,omitempty"`
+}
+
+// VMCPU contains the CPU configuration of a virtual machine
+type VMCPU struct {
+	Count            int  `json:"count,omitempty"`
+	CoresPerSocket   int  `json:"cores_per_socket,omitempty"`
+	HotAddEnabled    bool `json:"hot_add_enabled,omitempty"`
+	HotRemoveEnabled bool `json:"hot_remove_enabled,omitempty"`
+}
+
+// VMMemory contains the memory configuration of a virtual machine
+type VMMemory struct {
+	SizeMiB       int64 `json:"size_MiB,omitempty"`
+	HotAddEnabled bool  `json:"hot_add_enabled,omitempty"`
+}
+
+// VMHardware contains the virtual hardware version of a virtual machine
+type VMHardware struct {
+	Version       string `json:"version,omitempty"`
+	UpgradePolicy string `json:"upgrade_policy,omitempty"`
+	UpgradeStatus string `json:"upgrade_status,omitempty"`
+}
+
+// VMIdentity contains the identifiers of a virtual machine
+type VMIdentity struct {
+	Name         string `json:"name"`
+	InstanceUUID string `json:"instance_uuid,omitempty"`
+	BiosUUID     string `json:"bios_uuid,omitempty"`
+}
+
+// VMDiskBacking specifies the backing of a virtual disk
+type VMDiskBacking struct {
+	Type     string `json:"type"`
+	VMDKFile string `json:"vmdk_file,omitempty"`
+}
+
+// VMDiskVMDK specifies a new VMDK to create as the backing of a virtual disk
+type VMDiskVMDK struct {
+	Name     string `json:"name,omitempty"`
+	Capacity int64  `json:"capacity,omitempty"`
+}
+
+// VMDiskInfo contains information about a virtual disk
+type VMDiskInfo struct {
+	Label    string        `json:"label"`
+	Type     string        `json:"type"`
+	Capacity int64         `json:"capacity,omitempty"`
+	Backing  VMDiskBacking `json:"backing"`
+}
+
+// VMDiskCreateSpec specifies a virtual disk to add to a virtual machine.
+// One of NewVMDK or Backing must be set.
+type VMDiskCreateSpec struct {
+	Type    string         `json:"type,omitempty"`
+	NewVMDK *VMDiskVMDK    `json:"new_vmdk,omitempty"`
+	Backing *VMDiskBacking `json:"backing,omitempty"`
+}
+
+// VMNicBacking specifies the network backing of a virtual Ethernet adapter
+type VMNicBacking struct {
+	Type        string `json:"type"`
+	Network     string `json:"network,omitempty"`
+	NetworkName string `json:"network_name,omitempty"`
+}
+
+// VMNicInfo contains information about a virtual Ethernet adapter
+type VMNicInfo struct {
+	Label          string       `json:"label"`
+	Type           string       `json:"type"`
+	MacType        string       `json:"mac_type,omitempty"`
+	MacAddress     string       `json:"mac_address,omitempty"`
+	State          string       `json:"state"`
+	StartConnected bool         `json:"start_connected"`
+	Backing        VMNicBacking `json:"backing"`
+}
+
+// VMNicCreateSpec specifies a virtual Ethernet adapter to add to a virtual machine
+type VMNicCreateSpec struct {
+	Type           string        `json:"type,omitempty"`
+	MacType        string        `json:"mac_type,omitempty"`
+	MacAddress     string        `json:"mac_address,omitempty"`
+	StartConnected bool          `json:"start_connected,omitempty"`
+	Backing        *VMNicBacking `json:"backing,omitempty"`
+}
+
+// VMCdromBacking specifies the backing of a virtual CD-ROM device
+type VMCdromBacking struct {
+	Type       string `json:"type"`
+	ISOFile    string `json:"iso_file,omitempty"`
+	HostDevice string `json:"host_device,omitempty"`
+}
+
+// VMCdromInfo contains information about a virtual CD-ROM device
+type VMCdromInfo struct {
+	Label          string         `json:"label"`
+	Type           string         `json:"type"`
+	State          string         `json:"state"`
+	StartConnected bool           `json:"start_connected"`
+	Backing        VMCdromBacking `json:"backing"`
+}
+
+// VMCdromCreateSpec specifies a virtual CD-ROM device to add to a virtual machine
+type VMCdromCreateSpec struct {
+	Type           string          `json:"type,omitempty"`
+	StartConnected bool            `json:"start_connected,omitempty"`
+	Backing        *VMCdromBacking `json:"backing,omitempty"`
+}
+
+// VMInfo contains information about a virtual machine.
+// Disks, Nics and Cdroms are keyed by device ID.
+type VMInfo struct {
+	Name       string                 `json:"name"`
+	PowerState string                 `json:"power_state"`
+	GuestOS    string                 `json:"guest_OS"`
+	Identity   *VMIdentity            `json:"identity,omitempty"`
+	Hardware   VMHardware             `json:"hardware"`
+	CPU        VMCPU                  `json:"cpu"`
+	Memory     VMMemory               `json:"memory"`
+	Disks      map[string]VMDiskInfo  `json:"disks,omitempty"`
+	Nics       map[string]VMNicInfo   `json:"nics,omitempty"`
+	Cdroms     map[string]VMCdromInfo `json:"cdroms,omitempty"`
+}
+
+// VMCreateSpec specifies a virtual machine to create
+type VMCreateSpec struct {
+	Name      string              `json:"name,omitempty"`
+	GuestOS   string              `json:"guest_OS"`
+	Placement *VMPlacement        `json:"placement,omitempty"`
+	CPU       *VMCPU              `json:"cpu,omitempty"`
+	Memory    *VMMemory           `json:"memory,omitempty"`
+	Disks     []VMDiskCreateSpec  `json:"disks,omitempty"`
+	Nics      []VMNicCreateSpec   `json:"nics,omitempty"`
+	Cdroms    []VMCdromCreateSpec `json:"cdroms,omitempty"`
+}
+
+// VMGuestIdentity contains information about the guest operating system, as reported by VMware Tools
+type VMGuestIdentity struct {
+	Name      string                  `json:"name"`
+	Family    string                  `json:"family"`
+	FullName  rest.LocalizableMessage `json:"full_name"`
+	HostName  string                  `json:"host_name"`
+	IPAddress string                  `json:"ip_address,omitempty"`
+}
+
+// VMGuestDNS contains the guest DNS configuration
+type VMGuestDNS struct {
+	IPAddresses   []string `json:"ip_addresses"`
+	SearchDomains []string `json:"search_domains"`
+}
+
+// VMGuestDNSValues contains the guest DNS host and domain name
+type VMGuestDNSValues struct {
+	DomainName string `json:"domain_name,omitempty"`
+	HostName   string `json:"host_name,omitempty"`
+}
+
+// VMGuestNetworking contains the guest network configuration
+type VMGuestNetworking struct {
+	DNSValues *VMGuestDNSValues `json:"dns_values,omitempty"`
+	DNS       *VMGuestDNS       `json:"dns,omitempty"`
+}
+
+// VMGuestIPAddress is an IP address of a guest network interface
+type VMGuestIPAddress struct {
+	IPAddress    string `json:"ip_address"`
+	PrefixLength int32  `json:"prefix_length"`
+	State        string `json:"state"`
+}
+
+// VMGuestIPConfig contains the IP configuration of a guest network interface
+type VMGuestIPConfig struct {
+	IPAddresses []VMGuestIPAddress `json:"ip_addresses"`
+}
+
+// VMGuestInterface contains information about a guest network interface.
+// Nic is the device ID of the virtual Ethernet adapter backing the interface, if any.
+type VMGuestInterface struct {
+	MacAddress string           `json:"mac_address,omitempty"`
+	Nic        string           `json:"nic,omitempty"`
+	IP         *VMGuestIPConfig `json:"ip,omitempty"`
+}
+
+// VMTools contains information about VMware Tools in a virtual machine
+type VMTools struct {
+	AutoUpdateSupported bool   `json:"auto_update_supported"`
+	InstallAttemptCount int    `json:"install_attempt_count,omitempty"`
+	VersionNumber       int    `json:"version_number,omitempty"`
+	Version             string `json:"version,omitempty"`
+	UpgradePolicy       string `json:"upgrade_policy"`
+	VersionStatus       string `json:"version_status,omitempty"`
+	InstallType         string `json:"install_type,omitempty"`
+	RunState            string `json:"run_state"`
+}
+
+// VMToolsUpdate specifies the VMware Tools configuration to update
+type VMToolsUpdate struct {
+	UpgradePolicy string `json:"upgrade_policy,omitempty"`
+}
+
+// VMToolsInstaller contains the VMware Tools installer state
+type VMToolsInstaller struct {
+	IsConnected bool `json:"is_connected"`
+}
+
+func vmPath(id string, elem ...string) string {
+	return path.Join(append([]string{internal.VCenterVMPath, id}, elem...)...)
+}
+
+// ListVMs returns the virtual machines matching the given filter, or all virtual machines if filter is nil
+func (c *Manager) ListVMs(ctx context.Context, filter *VMFilter) ([]VMSummary, error) {
+	url := c.Resource(internal.VCenterVMPath)
+	if filter != nil {
+		params := []struct {
+			name   string
+			values []string
+		}{
+			{"vms", filter.VMs},
+			{"names", filter.Names},
+			{"folders", filter.Folders},
+			{"datacenters", filter.Datacenters},
+			{"hosts", filter.Hosts},
+			{"clusters", filter.Clusters},
+			{"resource_pools", filter.ResourcePools},
+			{"power_states", filter.PowerStates},
+		}
+		for _, p := range params {
+			for _, v := range p.values {
+				url.WithParam(p.name, v)
+			}
+		}
+	}
+	var res []VMSummary
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetVM returns information about the given virtual machine
+func (c *Manager) GetVM(ctx context.Context, id string) (*VMInfo, error) {
+	url := c.Resource(vmPath(id))
+	var res VMInfo
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateVM creates a virtual machine, returning its ID
+func (c *Manager) CreateVM(ctx context.Context, spec VMCreateSpec) (string, error) {
+	url := c.Resource(internal.VCenterVMPath)
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteVM deletes the given virtual machine
+func (c *Manager) DeleteVM(ctx context.Context, id string) error {
+	url := c.Resource(vmPath(id))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// GetVMPower returns the power state of the given virtual machine
+func (c *Manager) GetVMPower(ctx context.Context, id string) (string, error) {
+	url := c.Resource(vmPath(id, "power"))
+	var res struct {
+		State string `json:"state"`
+	}
+	return res.State, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// PowerVM invokes the given power action, one of VMPowerStart, VMPowerStop, VMPowerSuspend or VMPowerReset
+func (c *Manager) PowerVM(ctx context.Context, id string, action string) error {
+	url := c.Resource(vmPath(id, "power")).WithParam("action", action)
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// vmDevices lists the IDs of the given hardware device kind
+func (c *Manager) vmDevices(ctx context.Context, id, kind, key string) ([]string, error) {
+	url := c.Resource(vmPath(id, "hardware", kind))
+	var res []map[string]string
+	if err := c.Do(ctx, url.Request(http.MethodGet), &res); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(res))
+	for i := range res {
+		ids[i] = res[i][key]
+	}
+	return ids, nil
+}
+
+// ListVMDisks returns the IDs of the given virtual machine's disks
+func (c *Manager) ListVMDisks(ctx context.Context, id string) ([]string, error) {
+	return c.vmDevices(ctx, id, "disk", "disk")
+}
+
+// GetVMDisk returns information about a virtual machine's disk
+func (c *Manager) GetVMDisk(ctx context.Context, id, disk string) (*VMDiskInfo, error) {
+	url := c.Resource(vmPath(id, "hardware", "disk", disk))
+	var res VMDiskInfo
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateVMDisk adds a disk to the given virtual machine, returning the disk ID
+func (c *Manager) CreateVMDisk(ctx context.Context, id string, spec VMDiskCreateSpec) (string, error) {
+	url := c.Resource(vmPath(id, "hardware", "disk"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteVMDisk removes a disk from the given virtual machine. The disk's backing file is not deleted.
+func (c *Manager) DeleteVMDisk(ctx context.Context, id, disk string) error {
+	url := c.Resource(vmPath(id, "hardware", "disk", disk))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// ListVMNics returns the IDs of the given virtual machine's Ethernet adapters
+func (c *Manager) ListVMNics(ctx context.Context, id string) ([]string, error) {
+	return c.vmDevices(ctx, id, "ethernet", "nic")
+}
+
+// GetVMNic returns information about a virtual machine's Ethernet adapter
+func (c *Manager) GetVMNic(ctx context.Context, id, nic string) (*VMNicInfo, error) {
+	url := c.Resource(vmPath(id, "hardware", "ethernet", nic))
+	var res VMNicInfo
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateVMNic adds an Ethernet adapter to the given virtual machine, returning the nic ID
+func (c *Manager) CreateVMNic(ctx context.Context, id string, spec VMNicCreateSpec) (string, error) {
+	url := c.Resource(vmPath(id, "hardware", "ethernet"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteVMNic removes an Ethernet adapter from the given virtual machine
+func (c *Manager) DeleteVMNic(ctx context.Context, id, nic string) error {
+	url := c.Resource(vmPath(id, "hardware", "ethernet", nic))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// ListVMCdroms returns the IDs of the given virtual machine's CD-ROM devices
+func (c *Manager) ListVMCdroms(ctx context.Context, id string) ([]string, error) {
+	return c.vmDevices(ctx, id, "cdrom", "cdrom")
+}
+
+// GetVMCdrom returns information about a virtual machine's CD-ROM device
+func (c *Manager) GetVMCdrom(ctx context.Context, id, cdrom string) (*VMCdromInfo, error) {
+	url := c.Resource(vmPath(id, "hardware", "cdrom", cdrom))
+	var res VMCdromInfo
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// CreateVMCdrom adds a CD-ROM device to the given virtual machine, returning the cdrom ID
+func (c *Manager) CreateVMCdrom(ctx context.Context, id string, spec VMCdromCreateSpec) (string, error) {
+	url := c.Resource(vmPath(id, "hardware", "cdrom"))
+	var res string
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteVMCdrom removes a CD-ROM device from the given virtual machine
+func (c *Manager) DeleteVMCdrom(ctx context.Context, id, cdrom string) error {
+	url := c.Resource(vmPath(id, "hardware", "cdrom", cdrom))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
+// GetVMGuestIdentity returns the guest operating system identity, which requires VMware Tools to be running
+func (c *Manager) GetVMGuestIdentity(ctx context.Context, id string) (*VMGuestIdentity, error) {
+	url := c.Resource(vmPath(id, "guest", "identity"))
+	var res VMGuestIdentity
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// GetVMGuestNetworking returns the guest network configuration, which requires VMware Tools to be running
+func (c *Manager) GetVMGuestNetworking(ctx context.Context, id string) (*VMGuestNetworking, error) {
+	url := c.Resource(vmPath(id, "guest", "networking"))
+	var res VMGuestNetworking
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ListVMGuestInterfaces returns the guest network interfaces, which requires VMware Tools to be running
+func (c *Manager) ListVMGuestInterfaces(ctx context.Context, id string) ([]VMGuestInterface, error) {
+	url := c.Resource(vmPath(id, "guest", "networking", "interfaces"))
+	var res []VMGuestInterface
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetVMTools returns the VMware Tools state of the given virtual machine
+func (c *Manager) GetVMTools(ctx context.Context, id string) (*VMTools, error) {
+	url := c.Resource(vmPath(id, "tools"))
+	var res VMTools
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// UpdateVMTools updates the VMware Tools configuration of the given virtual machine
+func (c *Manager) UpdateVMTools(ctx context.Context, id string, spec VMToolsUpdate) error {
+	url := c.Resource(vmPath(id, "tools"))
+	return c.Do(ctx, url.Request(http.MethodPatch, spec), nil)
+}
+
+// UpgradeVMTools upgrades VMware Tools in the given virtual machine
+func (c *Manager) UpgradeVMTools(ctx context.Context, id string) error {
+	url := c.Resource(vmPath(id, "tools")).WithParam("action", "upgrade")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// GetVMToolsInstaller returns the VMware Tools installer state of the given virtual machine
+func (c *Manager) GetVMToolsInstaller(ctx context.Context, id string) (*VMToolsInstaller, error) {
+	url := c.Resource(vmPath(id, "tools", "installer"))
+	var res VMToolsInstaller
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// ConnectVMToolsInstaller connects the VMware Tools installer ISO to the given virtual machine's CD-ROM
+func (c *Manager) ConnectVMToolsInstaller(ctx context.Context, id string) error {
+	url := c.Resource(vmPath(id, "tools", "installer")).WithParam("action", "connect")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
+
+// DisconnectVMToolsInstaller disconnects the VMware Tools installer ISO from the given virtual machine
+func (c *Manager) DisconnectVMToolsInstaller(ctx context.Context, id string) error {
+	url := c.Resource(vmPath(id, "tools", "installer")).WithParam("action", "disconnect")
+	return c.Do(ctx, url.Request(http.MethodPost), nil)
+}
@@ -0,0 +1,348 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+func TestVM(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		err := c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := vcenter.NewManager(c)
+
+		vms, err := m.ListVMs(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) == 0 {
+			t.Fatal("expected vms")
+		}
+
+		vms, err = m.ListVMs(ctx, &vcenter.VMFilter{PowerStates: []string{vcenter.VMPowerStatePoweredOff}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 0 {
+			t.Errorf("expected 0 powered off vms, got %d", len(vms))
+		}
+
+		// datacenter and cluster filters must both match
+		dc := simulator.Map.Any("Datacenter").Reference().Value
+		cluster := simulator.Map.Any("ClusterComputeResource").(*simulator.ClusterComputeResource)
+
+		vms, err = m.ListVMs(ctx, &vcenter.VMFilter{Datacenters: []string{dc}, Clusters: []string{cluster.Self.Value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) == 0 {
+			t.Fatal("expected vms")
+		}
+		for _, vm := range vms {
+			host := simulator.Map.Get(types.ManagedObjectReference{Type: "VirtualMachine", Value: vm.VM}).(*simulator.VirtualMachine).Runtime.Host
+			if simulator.Map.Get(*host).(*simulator.HostSystem).Parent.Value != cluster.Self.Value {
+				t.Errorf("vm %s is not in cluster %s", vm.Name, cluster.Name)
+			}
+		}
+
+		vms, err = m.ListVMs(ctx, &vcenter.VMFilter{Datacenters: []string{dc}, Clusters: []string{"enoent"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 0 {
+			t.Errorf("vms=%#v", vms)
+		}
+
+		vm := simulator.Map.Any("VirtualMachine").(*simulator.VirtualMachine)
+
+		vms, err = m.ListVMs(ctx, &vcenter.VMFilter{Names: []string{vm.Name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vms) != 1 || vms[0].VM != vm.Self.Value {
+			t.Fatalf("vms=%#v", vms)
+		}
+
+		_, err = m.GetVM(ctx, "enoent")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		info, err := m.GetVM(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Name != vm.Name || info.PowerState != vcenter.VMPowerStatePoweredOn {
+			t.Errorf("info=%#v", info)
+		}
+		if info.GuestOS != "OTHER" {
+			t.Errorf("guest_OS=%s", info.GuestOS)
+		}
+		if len(info.Disks) != 1 || len(info.Nics) != 1 {
+			t.Errorf("disks=%d nics=%d", len(info.Disks), len(info.Nics))
+		}
+
+		// guest endpoints require tools to be running
+		_, err = m.GetVMGuestIdentity(ctx, vm.Self.Value)
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		simulator.Map.Update(vm, []types.PropertyChange{
+			{Name: "guest.toolsRunningStatus", Val: string(types.VirtualMachineToolsRunningStatusGuestToolsRunning)},
+		})
+
+		identity, err := m.GetVMGuestIdentity(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.Name != info.GuestOS {
+			t.Errorf("identity=%#v", identity)
+		}
+
+		_, err = m.GetVMGuestNetworking(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.ListVMGuestInterfaces(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tools, err := m.GetVMTools(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tools.RunState != "RUNNING" {
+			t.Errorf("run_state=%s", tools.RunState)
+		}
+
+		err = m.UpdateVMTools(ctx, vm.Self.Value, vcenter.VMToolsUpdate{UpgradePolicy: "UPGRADE_AT_POWER_CYCLE"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.UpgradeVMTools(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tools, err = m.GetVMTools(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tools.UpgradePolicy != "UPGRADE_AT_POWER_CYCLE" || tools.VersionStatus != "CURRENT" {
+			t.Errorf("tools=%#v", tools)
+		}
+
+		err = m.ConnectVMToolsInstaller(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		installer, err := m.GetVMToolsInstaller(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !installer.IsConnected {
+			t.Error("expected installer to be connected")
+		}
+
+		err = m.DisconnectVMToolsInstaller(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.PowerVM(ctx, vm.Self.Value, vcenter.VMPowerStart)
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		err = m.PowerVM(ctx, vm.Self.Value, "enoent")
+		if !errors.Is(err, rest.ErrorInvalidArgument) {
+			t.Errorf("err=%v", err)
+		}
+
+		for _, action := range []string{vcenter.VMPowerSuspend, vcenter.VMPowerStart, vcenter.VMPowerStop} {
+			err = m.PowerVM(ctx, vm.Self.Value, action)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		state, err := m.GetVMPower(ctx, vm.Self.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state != vcenter.VMPowerStatePoweredOff {
+			t.Errorf("state=%s", state)
+		}
+	})
+}
+
+func TestCreateVM(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+
+		err := c.Login(ctx, simulator.DefaultLogin)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := vcenter.NewManager(c)
+
+		folder := simulator.Map.Any("Folder").(*simulator.Folder)
+		for _, f := range simulator.Map.All("Folder") {
+			if f.(*simulator.Folder).Name == "vm" {
+				folder = f.(*simulator.Folder)
+			}
+		}
+		pool := simulator.Map.Any("ResourcePool")
+		ds := simulator.Map.Any("Datastore")
+		network := simulator.Map.Any("Network")
+
+		spec := vcenter.VMCreateSpec{
+			Name:    "rest-vm",
+			GuestOS: "UBUNTU_64",
+			Placement: &vcenter.VMPlacement{
+				Folder:       folder.Self.Value,
+				ResourcePool: pool.Reference().Value,
+				Datastore:    ds.Reference().Value,
+			},
+			CPU:    &vcenter.VMCPU{Count: 2},
+			Memory: &vcenter.VMMemory{SizeMiB: 2048},
+			Disks: []vcenter.VMDiskCreateSpec{
+				{NewVMDK: &vcenter.VMDiskVMDK{Name: "disk-0.vmdk", Capacity: 10 * 1024 * 1024 * 1024}},
+			},
+			Nics: []vcenter.VMNicCreateSpec{
+				{Backing: &vcenter.VMNicBacking{Type: "STANDARD_PORTGROUP", Network: network.Reference().Value}},
+			},
+		}
+
+		_, err = m.CreateVM(ctx, vcenter.VMCreateSpec{Name: "invalid", GuestOS: "ENOENT"})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		id, err := m.CreateVM(ctx, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info, err := m.GetVM(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.GuestOS != spec.GuestOS || info.CPU.Count != 2 || info.Memory.SizeMiB != 2048 {
+			t.Errorf("info=%#v", info)
+		}
+		if len(info.Disks) != 1 || len(info.Nics) != 1 {
+			t.Fatalf("disks=%d nics=%d", len(info.Disks), len(info.Nics))
+		}
+
+		disks, err := m.ListVMDisks(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		disk, err := m.GetVMDisk(ctx, id, disks[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if disk.Type != "SCSI" || disk.Capacity != spec.Disks[0].NewVMDK.Capacity {
+			t.Errorf("disk=%#v", disk)
+		}
+
+		nics, err := m.ListVMNics(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nic, err := m.GetVMNic(ctx, id, nics[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nic.Backing.Network != spec.Nics[0].Backing.Network {
+			t.Errorf("nic=%#v", nic)
+		}
+
+		cdrom, err := m.CreateVMCdrom(ctx, id, vcenter.VMCdromCreateSpec{
+			Backing: &vcenter.VMCdromBacking{Type: "ISO_FILE", ISOFile: "[LocalDS_0] example.iso"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		info2, err := m.GetVMCdrom(ctx, id, cdrom)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info2.Backing.Type != "ISO_FILE" {
+			t.Errorf("cdrom=%#v", info2)
+		}
+
+		err = m.DeleteVMCdrom(ctx, id, cdrom)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.GetVMCdrom(ctx, id, cdrom)
+		if err == nil {
+			t.Error("expected error")
+		}
+
+		err = m.DeleteVMNic(ctx, id, nics[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		disk0, err := m.CreateVMDisk(ctx, id, vcenter.VMDiskCreateSpec{NewVMDK: &vcenter.VMDiskVMDK{Capacity: 1024 * 1024}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.DeleteVMDisk(ctx, id, disk0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = m.DeleteVM(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.GetVM(ctx, id)
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -149,6 +149,17 @@ type CheckIn struct {
 	Message string `json:"message"`
 }
 
+// TemplateVersion of a library item containing a VM template
+type TemplateVersion struct {
+	Version    string `json:"version"`
+	VMTemplate string `json:"vm_template"`
+}
+
+// Rollback specification
+type Rollback struct {
+	Message string `json:"message,omitempty"`
+}
+
 // CreateTemplate creates a library VMTX item in content library from an existing VM
 func (c *Manager) CreateTemplate(ctx context.Context, vmtx Template) (string, error) {
 	url := c.Resource(internal.VCenterVMTXLibraryItem)
@@ -209,6 +220,42 @@ func (c *Manager) CheckIn(ctx context.Context, libraryItemID string, vm mo.Refer
 	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
 }
 
+// ListTemplateVersions returns the previous versions of a library item containing a VM template.
+func (c *Manager) ListTemplateVersions(ctx context.Context, libraryItemID string) ([]TemplateVersion, error) {
+	url := c.Resource(path.Join(internal.VCenterVMTXLibraryItem, libraryItemID, "versions"))
+	var res []TemplateVersion
+	return res, c.Do(ctx, url.Request(http.MethodGet), &res)
+}
+
+// GetTemplateVersion returns the given version of a library item containing a VM template.
+func (c *Manager) GetTemplateVersion(ctx context.Context, libraryItemID string, version string) (*TemplateVersion, error) {
+	url := c.Resource(path.Join(internal.VCenterVMTXLibraryItem, libraryItemID, "versions", version))
+	res := TemplateVersion{Version: version}
+	err := c.Do(ctx, url.Request(http.MethodGet), &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RollbackTemplate restores the VM template of a library item to the given version,
+// returning the new content version of the item.
+func (c *Manager) RollbackTemplate(ctx context.Context, libraryItemID string, version string, rollback *Rollback) (string, error) {
+	p := path.Join(internal.VCenterVMTXLibraryItem, libraryItemID, "versions", version)
+	url := c.Resource(p).WithParam("action", "rollback")
+	var res string
+	spec := struct {
+		*Rollback `json:"spec"`
+	}{rollback}
+	return res, c.Do(ctx, url.Request(http.MethodPost, spec), &res)
+}
+
+// DeleteTemplateVersion deletes the given previous version of a library item containing a VM template.
+func (c *Manager) DeleteTemplateVersion(ctx context.Context, libraryItemID string, version string) error {
+	url := c.Resource(path.Join(internal.VCenterVMTXLibraryItem, libraryItemID, "versions", version))
+	return c.Do(ctx, url.Request(http.MethodDelete), nil)
+}
+
 // TemplateLibrary params for synchronizing subscription library OVF items to VM Template items
 type TemplateLibrary struct {
 	Source      library.Library
@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vcenter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/library"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/vcenter"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestTemplateVersions(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		c := rest.NewClient(vc)
+		if err := c.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+
+		finder := find.NewFinder(vc)
+		ds, err := finder.DefaultDatastore(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		folder, err := finder.DefaultFolder(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := finder.ResourcePool(ctx, "DC0_H0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lm := library.NewManager(c)
+		id, err := lm.CreateLibrary(ctx, library.Library{
+			Name:    "templates",
+			Type:    "LOCAL",
+			Storage: []library.StorageBackings{{DatastoreID: ds.Reference().Value, Type: "DATASTORE"}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m := vcenter.NewManager(c)
+		placement := &vcenter.Placement{
+			Folder:       folder.Reference().Value,
+			ResourcePool: pool.Reference().Value,
+		}
+
+		item, err := m.CreateTemplate(ctx, vcenter.Template{
+			Name:      "vmtx",
+			Library:   id,
+			SourceVM:  vm.Reference().Value,
+			Placement: placement,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		versions, err := m.ListTemplateVersions(ctx, item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 0 {
+			t.Errorf("versions=%#v", versions)
+		}
+
+		checkin := func(name string) string {
+			ref, err := m.CheckOut(ctx, item, &vcenter.CheckOut{Name: name, Placement: placement})
+			if err != nil {
+				t.Fatal(err)
+			}
+			version, err := m.CheckIn(ctx, item, ref, &vcenter.CheckIn{Message: name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return version
+		}
+
+		for i, expect := range []string{"2", "3"} {
+			if version := checkin(expect); version != expect {
+				t.Errorf("%d: version=%s", i, version)
+			}
+		}
+
+		versions, err = m.ListTemplateVersions(ctx, item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 2 || versions[0].Version != "1" || versions[1].Version != "2" {
+			t.Fatalf("versions=%#v", versions)
+		}
+
+		v1, err := m.GetTemplateVersion(ctx, item, "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *v1 != versions[0] {
+			t.Errorf("version=%#v", v1)
+		}
+
+		_, err = m.GetTemplateVersion(ctx, item, "enoent")
+		if !errors.Is(err, rest.ErrorNotFound) {
+			t.Errorf("err=%v", err)
+		}
+
+		version, err := m.RollbackTemplate(ctx, item, "1", &vcenter.Rollback{Message: "rollback"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != "4" {
+			t.Errorf("version=%s", version)
+		}
+
+		info, err := lm.GetLibraryItem(ctx, item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ContentVersion != "4" {
+			t.Errorf("content version=%s", info.ContentVersion)
+		}
+
+		tmpl, err := finder.VirtualMachine(ctx, "vmtx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tmpl.Reference().Value != v1.VMTemplate {
+			t.Errorf("template=%s", tmpl.Reference())
+		}
+
+		if err = m.DeleteTemplateVersion(ctx, item, "2"); err != nil {
+			t.Fatal(err)
+		}
+
+		versions, err = m.ListTemplateVersions(ctx, item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(versions) != 1 || versions[0].Version != "3" {
+			t.Errorf("versions=%#v", versions)
+		}
+	})
+}
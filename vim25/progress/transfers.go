@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Transfers aggregates the progress of multiple file transfers, possibly concurrent, into a single progress report.
+// The percentage is weighted by file size and the detail reports the number of completed transfers.
+type Transfers struct {
+	mu sync.Mutex
+	wg sync.WaitGroup
+	ch chan<- Report
+
+	size     int64
+	pos      int64
+	files    int
+	complete int
+}
+
+type transfersReport struct {
+	pct    float32
+	detail string
+}
+
+func (r transfersReport) Percentage() float32 {
+	return r.pct
+}
+
+func (r transfersReport) Detail() string {
+	return r.detail
+}
+
+func (r transfersReport) Error() error {
+	return nil
+}
+
+// NewTransfers returns a Transfers instance for the given number of files and their total size.
+func NewTransfers(s Sinker, files int, size int64) *Transfers {
+	return &Transfers{
+		ch:    s.Sink(),
+		files: files,
+		size:  size,
+	}
+}
+
+// Sinker returns a Sinker for the transfer of a single file of the given size.
+func (t *Transfers) Sinker(size int64) Sinker {
+	return SinkFunc(func() chan<- Report {
+		ch := make(chan Report)
+		t.wg.Add(1)
+
+		go func() {
+			defer t.wg.Done()
+
+			var pos int64
+			failed := false
+
+			for r := range ch {
+				if r.Error() != nil {
+					failed = true
+					continue
+				}
+				n := int64(float64(r.Percentage()) / 100 * float64(size))
+				t.update(n-pos, false)
+				pos = n
+			}
+
+			if failed {
+				// a failed transfer does not count towards the total, it may be retried
+				if pos != 0 {
+					t.update(-pos, false)
+				}
+			} else {
+				t.update(size-pos, true)
+			}
+		}()
+
+		return ch
+	})
+}
+
+func (t *Transfers) update(n int64, done bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pos += n
+	if done {
+		t.complete++
+	}
+
+	var pct float32
+	if t.size > 0 {
+		pct = 100 * float32(t.pos) / float32(t.size)
+	} else {
+		pct = 100 * float32(t.complete) / float32(t.files)
+	}
+
+	t.ch <- transfersReport{
+		pct:    pct,
+		detail: fmt.Sprintf("%d/%d files", t.complete, t.files),
+	}
+}
+
+// Done waits for all file transfer reports and closes the downstream progress channel.
+func (t *Transfers) Done() {
+	t.wg.Wait()
+	close(t.ch)
+}
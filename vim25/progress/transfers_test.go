@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2022 VMware, Inc. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTransfers(t *testing.T) {
+	ch := make(chan Report)
+	done := make(chan []Report)
+
+	go func() {
+		var reports []Report
+		for r := range ch {
+			reports = append(reports, r)
+		}
+		done <- reports
+	}()
+
+	tr := NewTransfers(&dummySinker{ch: ch}, 3, 400)
+
+	in := tr.Sinker(100).Sink()
+	in <- dummyReport{p: 50}
+	close(in)
+
+	in = tr.Sinker(300).Sink()
+	in <- dummyReport{p: 50}
+	in <- dummyReport{e: errors.New("failed")}
+	close(in)
+
+	in = tr.Sinker(300).Sink()
+	close(in)
+
+	tr.Done()
+
+	reports := <-done
+	if len(reports) != 5 {
+		t.Fatalf("reports=%d", len(reports))
+	}
+
+	last := reports[len(reports)-1]
+	if last.Detail() != "2/3 files" {
+		t.Errorf("detail=%q", last.Detail())
+	}
+
+	if last.Percentage() != 100 {
+		t.Errorf("percentage=%f", last.Percentage())
+	}
+}